@@ -0,0 +1,35 @@
+package cmd
+
+import (
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/zapier/tfbuddy/pkg/tfc_trigger"
+)
+
+// configCmd represents the config command
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Sub commands for the .tfbuddy.yaml project configuration",
+	Long:  ``,
+}
+
+// configSchemaCmd represents the config schema command
+var configSchemaCmd = &cobra.Command{
+	Use:   "schema",
+	Short: "Print the JSON Schema for .tfbuddy.yaml.",
+	Long:  ``,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		schema, err := tfc_trigger.ProjectConfigSchema()
+		if err != nil {
+			return err
+		}
+		_, err = os.Stdout.Write(schema)
+		return err
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(configCmd)
+	configCmd.AddCommand(configSchemaCmd)
+}
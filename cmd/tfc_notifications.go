@@ -0,0 +1,97 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/go-tfe"
+	"github.com/spf13/cobra"
+	"github.com/zapier/tfbuddy/internal/config"
+	"github.com/zapier/tfbuddy/pkg/tfc_api"
+)
+
+var notificationsOrganization string
+var notificationsWorkspaces []string
+var notificationsForce bool
+
+// tfcNotificationsCmd groups the notification configuration commands
+var tfcNotificationsCmd = &cobra.Command{
+	Use:   "notifications",
+	Short: "Manage the TFC notification configurations of workspaces.",
+	Long:  ``,
+	// the TFC client reads TFC_TOKEN and the organization tokens itself
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		return nil
+	},
+}
+
+// tfcNotificationsSyncCmd represents the notifications sync command
+var tfcNotificationsSyncCmd = &cobra.Command{
+	Use:   "sync",
+	Short: "Create or fix the TFBuddy notification configuration of workspaces.",
+	Long: `Create the TFBuddy notification configuration of every workspace of the
+organization, or of the given workspaces, and fix the configurations that have
+drifted. Workspaces in tfc-notification-deny-list are skipped.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg := loadConfig()
+		if cfg.TFCNotificationURL == "" {
+			return fmt.Errorf("%s is not set", config.KeyTFCNotificationURL)
+		}
+		org := notificationsOrganization
+		if org == "" {
+			org = cfg.DefaultTFCOrganization
+		}
+		if org == "" {
+			return fmt.Errorf("--tfc_organization is not set")
+		}
+		return syncNotifications(cmd.Context(), cfg, tfc_api.NewTFCClient(cfg), org)
+	},
+}
+
+func syncNotifications(ctx context.Context, cfg config.Config, client tfc_api.ApiClient, org string) error {
+	var workspaces []*tfe.Workspace
+	if len(notificationsWorkspaces) == 0 {
+		all, err := client.ListWorkspaces(ctx, org)
+		if err != nil {
+			return fmt.Errorf("could not list workspaces of %s. %w", org, err)
+		}
+		workspaces = all
+	}
+	for _, name := range notificationsWorkspaces {
+		ws, err := client.GetWorkspaceByName(ctx, org, name)
+		if err != nil {
+			return fmt.Errorf("could not get workspace %s/%s. %w", org, name, err)
+		}
+		workspaces = append(workspaces, ws)
+	}
+
+	opts := tfc_api.NewNotificationOptions(cfg)
+	opts.Force = notificationsForce
+	failed := 0
+	for _, ws := range workspaces {
+		if !tfc_api.NotificationsManaged(cfg, org, ws.Name) {
+			fmt.Printf("%s/%s: skipped\n", org, ws.Name)
+			continue
+		}
+		result, err := client.EnsureNotificationConfiguration(ctx, ws.ID, opts)
+		if err != nil {
+			failed++
+			fmt.Printf("%s/%s: failed: %s\n", org, ws.Name, err)
+			continue
+		}
+		fmt.Printf("%s/%s: %s\n", org, ws.Name, result)
+	}
+	if failed > 0 {
+		return fmt.Errorf("could not sync the notification configuration of %d workspaces", failed)
+	}
+	return nil
+}
+
+func init() {
+	tfcCmd.AddCommand(tfcNotificationsCmd)
+	tfcNotificationsCmd.AddCommand(tfcNotificationsSyncCmd)
+
+	tfcNotificationsSyncCmd.Flags().StringVar(&notificationsOrganization, "tfc_organization", "", "The Terraform Cloud organization, defaults to default-tfc-organization.")
+	tfcNotificationsSyncCmd.Flags().StringSliceVar(&notificationsWorkspaces, "tfc_workspace", nil, "Workspaces to sync, defaults to all workspaces of the organization.")
+	tfcNotificationsSyncCmd.Flags().BoolVar(&notificationsForce, "force", false, "Update configurations that have not drifted, e.g. to set a new token.")
+}
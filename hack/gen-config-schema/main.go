@@ -0,0 +1,46 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/zapier/tfbuddy/pkg/tfc_trigger"
+)
+
+func main() {
+	if err := run(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func run() error {
+	schema, err := tfc_trigger.ProjectConfigSchema()
+	if err != nil {
+		return err
+	}
+
+	schemaPath, err := findSchemaPath()
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(schemaPath, schema, 0o644); err != nil {
+		return fmt.Errorf("write schema: %w", err)
+	}
+	return nil
+}
+
+func findSchemaPath() (string, error) {
+	candidates := []string{
+		"docs",
+		filepath.Join("..", "..", "docs"),
+	}
+	for _, candidate := range candidates {
+		dir := filepath.Clean(candidate)
+		if _, err := os.Stat(filepath.Join(dir, "usage.md")); err == nil {
+			return filepath.Join(dir, "tfbuddy.schema.json"), nil
+		}
+	}
+	return "", fmt.Errorf("could not locate docs directory from current working directory")
+}
@@ -18,10 +18,15 @@ const (
 	KeyOTELCollectorPort          = "otel-collector-port"
 	KeyGitlabHookSecretKey        = "gitlab-hook-secret-key"
 	KeyGithubHookSecretKey        = "github-hook-secret-key"
+	KeyGithubAppID                = "github-app-id"
+	KeyGithubAppPrivateKeyFile    = "github-app-private-key-file"
+	KeyGithubBaseURL              = "github-base-url"
+	KeyGithubUploadURL            = "github-upload-url"
 	KeyDefaultTFCOrganization     = "default-tfc-organization"
 	KeyWorkspaceAllowList         = "workspace-allow-list"
 	KeyWorkspaceDenyList          = "workspace-deny-list"
 	KeyAllowAutoMerge             = "allow-auto-merge"
+	KeyAllowForkMRs               = "allow-fork-mrs"
 	KeyFailCIOnSentinelSoftFail   = "fail-ci-on-sentinel-soft-fail"
 	KeyDeleteOldComments          = "delete-old-comments"
 	KeyNATSServiceURL             = "nats-service-url"
@@ -30,8 +35,42 @@ const (
 	KeyGithubRepoAllowList        = "github-repo-allow-list"
 	KeyGithubCloneDepth           = "github-clone-depth"
 	KeyGitlabCloneDepth           = "gitlab-clone-depth"
+	KeyGitlabBaseURL              = "gitlab-base-url"
+	KeyGitlabInstances            = "gitlab-instances"
+	KeyGitlabCAFile               = "gitlab-ca-file"
+	KeyGitlabInsecureSkipVerify   = "gitlab-insecure-skip-verify"
+	KeyBitbucketBaseURL           = "bitbucket-base-url"
+	KeyBitbucketHookSecretKey     = "bitbucket-hook-secret-key"
+	KeyBitbucketHookUnsigned      = "bitbucket-hook-allow-unsigned"
+	KeyBitbucketRepoAllowList     = "bitbucket-repo-allow-list"
+	KeyBitbucketCloneDepth        = "bitbucket-clone-depth"
+	KeyAzureDevOpsURL             = "azure-devops-url"
+	KeyAzureDevOpsHookSecretKey   = "azure-devops-hook-secret-key"
+	KeyAzureDevOpsHookUnsigned    = "azure-devops-hook-allow-unauthenticated"
+	KeyAzureDevOpsRepoAllowList   = "azure-devops-repo-allow-list"
+	KeyAzureDevOpsCloneDepth      = "azure-devops-clone-depth"
+	KeyGiteaURL                   = "gitea-url"
+	KeyGiteaHookSecretKey         = "gitea-hook-secret-key"
+	KeyGiteaHookUnsigned          = "gitea-hook-allow-unsigned"
+	KeyGiteaRepoAllowList         = "gitea-repo-allow-list"
+	KeyGiteaCloneDepth            = "gitea-clone-depth"
 	KeyWorkspaceFanoutEnabled     = "workspace-fanout-enabled"
 	KeyWorkspaceJetStreamReplicas = "workspace-jetstream-replicas"
+	KeyTFCAddress                 = "tfc-address"
+	KeyTFCCAFile                  = "tfc-ca-file"
+	KeyTFCTokensFile              = "tfc-tokens-file"
+	KeyTFCTokenFallback           = "tfc-token-fallback"
+	KeyTFCRunTaskHMACKey          = "tfc-run-task-hmac-key"
+	KeyTFCNotificationTokens      = "tfc-notification-tokens"
+	KeyTFCNotificationURL         = "tfc-notification-url"
+	KeyTFCNotificationDenyList    = "tfc-notification-deny-list"
+	KeyTFCNotificationUnsigned    = "tfc-notification-allow-unsigned"
+	KeyTFCRunTaskRejectExternal   = "tfc-run-task-reject-external-runs"
+	KeyTFCWorkspaceCreateOrgs     = "tfc-workspace-create-organizations"
+	KeyTFCCostDeltaThreshold      = "tfc-cost-delta-threshold"
+	KeyTFCPolicyOverrideUsers     = "tfc-policy-override-users"
+	KeyTFCErrorLogMaxLength       = "tfc-error-log-max-length"
+	KeyTFCCancelSupersededPlans   = "tfc-cancel-superseded-plans"
 	KeyTFCRateLimitRPS            = "tfc-rate-limit-rps"
 	KeyTFCRateLimitBurst          = "tfc-rate-limit-burst"
 )
@@ -44,10 +83,15 @@ type Config struct {
 	OTELCollectorPort          string   `mapstructure:"otel-collector-port"`
 	GitlabHookSecretKey        string   `mapstructure:"gitlab-hook-secret-key"`
 	GithubHookSecretKey        string   `mapstructure:"github-hook-secret-key"`
+	GithubAppID                int      `mapstructure:"github-app-id"`
+	GithubAppPrivateKeyFile    string   `mapstructure:"github-app-private-key-file"`
+	GithubBaseURL              string   `mapstructure:"github-base-url"`
+	GithubUploadURL            string   `mapstructure:"github-upload-url"`
 	DefaultTFCOrganization     string   `mapstructure:"default-tfc-organization"`
 	WorkspaceAllowList         []string `mapstructure:"workspace-allow-list"`
 	WorkspaceDenyList          []string `mapstructure:"workspace-deny-list"`
 	AllowAutoMerge             bool     `mapstructure:"allow-auto-merge"`
+	AllowForkMRs               bool     `mapstructure:"allow-fork-mrs"`
 	FailCIOnSentinelSoftFail   bool     `mapstructure:"fail-ci-on-sentinel-soft-fail"`
 	DeleteOldComments          bool     `mapstructure:"delete-old-comments"`
 	NATSServiceURL             string   `mapstructure:"nats-service-url"`
@@ -56,8 +100,42 @@ type Config struct {
 	GithubRepoAllowList        []string `mapstructure:"github-repo-allow-list"`
 	GithubCloneDepth           int      `mapstructure:"github-clone-depth"`
 	GitlabCloneDepth           int      `mapstructure:"gitlab-clone-depth"`
+	GitlabBaseURL              string   `mapstructure:"gitlab-base-url"`
+	GitlabInstances            []string `mapstructure:"gitlab-instances"`
+	GitlabCAFile               string   `mapstructure:"gitlab-ca-file"`
+	GitlabInsecureSkipVerify   bool     `mapstructure:"gitlab-insecure-skip-verify"`
+	BitbucketBaseURL           string   `mapstructure:"bitbucket-base-url"`
+	BitbucketHookSecretKey     string   `mapstructure:"bitbucket-hook-secret-key"`
+	BitbucketHookUnsigned      bool     `mapstructure:"bitbucket-hook-allow-unsigned"`
+	BitbucketRepoAllowList     []string `mapstructure:"bitbucket-repo-allow-list"`
+	BitbucketCloneDepth        int      `mapstructure:"bitbucket-clone-depth"`
+	AzureDevOpsURL             string   `mapstructure:"azure-devops-url"`
+	AzureDevOpsHookSecretKey   string   `mapstructure:"azure-devops-hook-secret-key"`
+	AzureDevOpsHookUnsigned    bool     `mapstructure:"azure-devops-hook-allow-unauthenticated"`
+	AzureDevOpsRepoAllowList   []string `mapstructure:"azure-devops-repo-allow-list"`
+	AzureDevOpsCloneDepth      int      `mapstructure:"azure-devops-clone-depth"`
+	GiteaURL                   string   `mapstructure:"gitea-url"`
+	GiteaHookSecretKey         string   `mapstructure:"gitea-hook-secret-key"`
+	GiteaHookUnsigned          bool     `mapstructure:"gitea-hook-allow-unsigned"`
+	GiteaRepoAllowList         []string `mapstructure:"gitea-repo-allow-list"`
+	GiteaCloneDepth            int      `mapstructure:"gitea-clone-depth"`
 	WorkspaceFanoutEnabled     bool     `mapstructure:"workspace-fanout-enabled"`
 	WorkspaceJetStreamReplicas int      `mapstructure:"workspace-jetstream-replicas"`
+	TFCAddress                 string   `mapstructure:"tfc-address"`
+	TFCCAFile                  string   `mapstructure:"tfc-ca-file"`
+	TFCTokensFile              string   `mapstructure:"tfc-tokens-file"`
+	TFCTokenFallback           bool     `mapstructure:"tfc-token-fallback"`
+	TFCRunTaskHMACKey          string   `mapstructure:"tfc-run-task-hmac-key"`
+	TFCNotificationTokens      []string `mapstructure:"tfc-notification-tokens"`
+	TFCNotificationURL         string   `mapstructure:"tfc-notification-url"`
+	TFCNotificationDenyList    []string `mapstructure:"tfc-notification-deny-list"`
+	TFCNotificationUnsigned    bool     `mapstructure:"tfc-notification-allow-unsigned"`
+	TFCRunTaskRejectExternal   bool     `mapstructure:"tfc-run-task-reject-external-runs"`
+	TFCWorkspaceCreateOrgs     []string `mapstructure:"tfc-workspace-create-organizations"`
+	TFCCostDeltaThreshold      int      `mapstructure:"tfc-cost-delta-threshold"`
+	TFCPolicyOverrideUsers     []string `mapstructure:"tfc-policy-override-users"`
+	TFCErrorLogMaxLength       int      `mapstructure:"tfc-error-log-max-length"`
+	TFCCancelSupersededPlans   bool     `mapstructure:"tfc-cancel-superseded-plans"`
 	TFCRateLimitRPS            int      `mapstructure:"tfc-rate-limit-rps"`
 	TFCRateLimitBurst          int      `mapstructure:"tfc-rate-limit-burst"`
 }
@@ -79,10 +157,17 @@ var bindings = []binding{
 	{key: KeyOTELCollectorPort, defaultValue: "", description: "OpenTelemetry collector port."},
 	{key: KeyGitlabHookSecretKey, defaultValue: "", description: "Secret key used to validate incoming GitLab webhooks."},
 	{key: KeyGithubHookSecretKey, defaultValue: "", description: "Secret key used to validate incoming GitHub webhooks."},
+	{key: KeyGithubAppID, defaultValue: 0, description: "GitHub App ID. When set together with the private key file, TFBuddy authenticates as the app instead of using GITHUB_TOKEN."},
+	{key: KeyGithubAppPrivateKeyFile, defaultValue: "", description: "Path to the PEM encoded private key of the GitHub App."},
+	{key: KeyGithubBaseURL, defaultValue: "", description: "API base URL of a GitHub Enterprise Server instance, e.g. https://github.example.com/api/v3/. Empty means github.com."},
+	{key: KeyGithubUploadURL, defaultValue: "", description: "Upload URL of a GitHub Enterprise Server instance. Defaults to the uploads endpoint of the API base URL host."},
 	{key: KeyDefaultTFCOrganization, defaultValue: "", description: "Default Terraform Cloud organization for workspaces that omit one in .tfbuddy.yaml."},
 	{key: KeyWorkspaceAllowList, defaultValue: []string{}, description: "Comma-separated workspace allow list. Entries without an organization use the default Terraform Cloud organization."},
 	{key: KeyWorkspaceDenyList, defaultValue: []string{}, description: "Comma-separated workspace deny list. Entries without an organization use the default Terraform Cloud organization."},
 	{key: KeyAllowAutoMerge, defaultValue: true, description: "Globally enable or disable TFBuddy-managed auto-merge."},
+	{key: KeyAllowForkMRs, defaultValue: false, description: "Allow runs for merge requests from forks. They never plan automatically; a maintainer must comment `tfc plan` or `tfc apply`. Supported on GitLab and GitHub, other providers refuse forks."},
+	{key: KeyTFCErrorLogMaxLength, defaultValue: 10000, description: "Maximum length, in characters, of the Terraform errors of a failed run posted in the merge request; longer output is truncated. 0 disables posting them."},
+	{key: KeyTFCCancelSupersededPlans, defaultValue: true, description: "Cancel the speculative plans of a merge request that are still running when a newer commit of it is planned, and stop polling them."},
 	{key: KeyFailCIOnSentinelSoftFail, defaultValue: false, description: "Mark CI as failed when Terraform policy checks soft-fail."},
 	{key: KeyDeleteOldComments, defaultValue: false, description: "Delete older bot comments for the same workspace and action after posting a newer one."},
 	{key: KeyNATSServiceURL, defaultValue: "", description: "NATS connection URL. When empty, TFBuddy falls back to the NATS client default."},
@@ -91,8 +176,40 @@ var bindings = []binding{
 	{key: KeyGithubRepoAllowList, defaultValue: []string{}, description: "Comma-separated GitHub repository allow list prefixes."},
 	{key: KeyGithubCloneDepth, defaultValue: 0, description: "Git clone depth to use for GitHub merge request checkouts. Zero means full history."},
 	{key: KeyGitlabCloneDepth, defaultValue: 0, description: "Git clone depth to use for GitLab merge request checkouts. Zero means full history."},
+	{key: KeyGitlabBaseURL, defaultValue: "", description: "URL of a self-managed GitLab instance, e.g. https://gitlab.example.com. Empty means gitlab.com."},
+	{key: KeyGitlabInstances, defaultValue: []string{}, description: "Comma-separated URLs of additional GitLab instances. The token for each is read from GITLAB_TOKEN_<HOST>, e.g. GITLAB_TOKEN_GITLAB_EXAMPLE_COM."},
+	{key: KeyGitlabCAFile, defaultValue: "", description: "Path to a PEM bundle of extra CA certificates trusted when connecting to GitLab."},
+	{key: KeyGitlabInsecureSkipVerify, defaultValue: false, description: "Skip TLS certificate verification when connecting to GitLab. Only use for testing."},
+	{key: KeyBitbucketBaseURL, defaultValue: "", description: "URL of a Bitbucket Data Center instance, e.g. https://bitbucket.example.com. Empty means Bitbucket Cloud."},
+	{key: KeyBitbucketHookSecretKey, defaultValue: "", description: "Secret used to verify the signature of incoming Bitbucket webhooks. Webhooks are rejected when it is not set."},
+	{key: KeyBitbucketHookUnsigned, defaultValue: false, description: "Accept unsigned Bitbucket webhooks when bitbucket-hook-secret-key is not set. Insecure: anyone reaching the hook can trigger plans and applies."},
+	{key: KeyBitbucketRepoAllowList, defaultValue: []string{}, description: "Comma-separated Bitbucket repository allow list prefixes, e.g. workspace/ or PROJECT/."},
+	{key: KeyBitbucketCloneDepth, defaultValue: 0, description: "Git clone depth to use for Bitbucket pull request checkouts. Zero means full history."},
+	{key: KeyAzureDevOpsURL, defaultValue: "", description: "URL of the Azure DevOps organization, e.g. https://dev.azure.com/example, or of an Azure DevOps Server collection."},
+	{key: KeyAzureDevOpsHookSecretKey, defaultValue: "", description: "Password expected in the basic authentication of incoming Azure DevOps service hooks. Service hooks are rejected when it is not set."},
+	{key: KeyAzureDevOpsHookUnsigned, defaultValue: false, description: "Accept unauthenticated Azure DevOps service hooks when azure-devops-hook-secret-key is not set. Insecure: anyone reaching the hook can trigger plans and applies."},
+	{key: KeyAzureDevOpsRepoAllowList, defaultValue: []string{}, description: "Comma-separated Azure DevOps repository allow list prefixes, e.g. project/ or project/repo."},
+	{key: KeyAzureDevOpsCloneDepth, defaultValue: 0, description: "Git clone depth to use for Azure DevOps pull request checkouts. Zero means full history."},
+	{key: KeyGiteaURL, defaultValue: "", description: "URL of the Gitea or Forgejo instance, e.g. https://gitea.example.com."},
+	{key: KeyGiteaHookSecretKey, defaultValue: "", description: "Secret used to verify the signature of incoming Gitea and Forgejo webhooks. Webhooks are rejected when it is not set."},
+	{key: KeyGiteaHookUnsigned, defaultValue: false, description: "Accept unsigned Gitea and Forgejo webhooks when gitea-hook-secret-key is not set. Insecure: anyone reaching the hook can trigger plans and applies."},
+	{key: KeyGiteaRepoAllowList, defaultValue: []string{}, description: "Comma-separated Gitea repository allow list prefixes, e.g. owner/ or owner/repo."},
+	{key: KeyGiteaCloneDepth, defaultValue: 0, description: "Git clone depth to use for Gitea pull request checkouts. Zero means full history."},
 	{key: KeyWorkspaceFanoutEnabled, defaultValue: true, description: "Enable per-workspace JetStream fan-out (one NATS message per workspace) to keep AckWait windows scoped per workspace. When disabled, TFBuddy falls back to the inline per-MR loop."},
 	{key: KeyWorkspaceJetStreamReplicas, defaultValue: 1, description: "JetStream replica count for the workspace-trigger stream. Use 1 for single-node NATS or local dev; set to your NATS cluster size (often 3) in production for durability."},
+	{key: KeyTFCAddress, defaultValue: "https://app.terraform.io", description: "Address of HCP Terraform or of a Terraform Enterprise instance, used for API calls and run links. Use https://app.eu.terraform.io for the HCP Terraform EU region."},
+	{key: KeyTFCCAFile, defaultValue: "", description: "Path to a PEM bundle of extra CA certificates trusted when connecting to Terraform Enterprise."},
+	{key: KeyTFCTokensFile, defaultValue: "", description: "Path to a YAML file mapping TFC organization names to API tokens, e.g. a mounted secret. Tokens can also be set with TFC_TOKEN_<ORG> environment variables."},
+	{key: KeyTFCTokenFallback, defaultValue: false, description: "Use TFC_TOKEN for organizations without a token of their own once tokens are configured for individual organizations. Without it, such organizations have no credentials."},
+	{key: KeyTFCRunTaskHMACKey, defaultValue: "", description: "HMAC key of the TFBuddy run task in TFC, used to verify the signature of incoming run task requests. Run task requests are rejected when it is not set."},
+	{key: KeyTFCNotificationTokens, defaultValue: []string{}, description: "Comma-separated tokens of the TFC notification configurations, used to verify the signature of incoming notifications. Set several tokens to rotate them."},
+	{key: KeyTFCNotificationURL, defaultValue: "", description: "Public URL of the TFC notification hook, e.g. https://tfbuddy.example.com/hooks/tfc/notification. When set, TFBuddy creates or fixes the notification configuration of the workspaces it runs, signed with the first of tfc-notification-tokens."},
+	{key: KeyTFCNotificationDenyList, defaultValue: []string{}, description: "Comma-separated workspaces whose notification configuration TFBuddy does not manage. Entries without an organization use the default Terraform Cloud organization."},
+	{key: KeyTFCNotificationUnsigned, defaultValue: false, description: "Accept unsigned TFC notifications when tfc-notification-tokens is not set. Insecure: anyone reaching the hook can forge run statuses and trigger auto-merges."},
+	{key: KeyTFCRunTaskRejectExternal, defaultValue: false, description: "Fail the TFBuddy run task for runs that were not created by TFBuddy, e.g. runs started from the TFC UI or CLI."},
+	{key: KeyTFCWorkspaceCreateOrgs, defaultValue: []string{}, description: "Comma-separated Terraform Cloud organizations in which TFBuddy may create the workspaces declared with a `create` block in .tfbuddy.yaml."},
+	{key: KeyTFCCostDeltaThreshold, defaultValue: 0, description: "Monthly cost increase, in USD, estimated by the latest plan of the applied commit above which `tfc apply` requires `--accept-cost`. Applies without a finished estimate also require it. 0 disables the check."},
+	{key: KeyTFCPolicyOverrideUsers, defaultValue: []string{}, description: "Comma-separated VCS usernames allowed to override failed policies with `tfc override-policy`. When empty, project maintainers may override them on GitLab and GitHub."},
 	{key: KeyTFCRateLimitRPS, defaultValue: 30, description: "Client-side rate limit (requests per second) for the Terraform Cloud API. Tuned to match TFC's documented per-token limit and prevent 429s when many workspaces are triggered concurrently."},
 	{key: KeyTFCRateLimitBurst, defaultValue: 30, description: "Burst capacity for the TFC API token-bucket rate limiter."},
 }
@@ -0,0 +1,11 @@
+package allow_list
+
+import (
+	"github.com/zapier/tfbuddy/internal/config"
+)
+
+// IsAzureDevOpsRepoAllowed matches the repository, project/repo within the
+// configured organization, against the allow list.
+func IsAzureDevOpsRepoAllowed(cfg config.Config, fullName string) bool {
+	return isRepoAllowed(cfg.AzureDevOpsRepoAllowList, fullName)
+}
@@ -0,0 +1,11 @@
+package allow_list
+
+import (
+	"github.com/zapier/tfbuddy/internal/config"
+)
+
+// IsBitbucketRepoAllowed matches the repository, workspace/repo on Bitbucket
+// Cloud or PROJECT/repo on Bitbucket Data Center, against the allow list.
+func IsBitbucketRepoAllowed(cfg config.Config, fullName string) bool {
+	return isRepoAllowed(cfg.BitbucketRepoAllowList, fullName)
+}
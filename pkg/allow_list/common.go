@@ -25,3 +25,23 @@ func getAllowList(allowed []string) []string {
 
 	return nil
 }
+
+// isRepoAllowed matches the full name of a repository against the prefixes
+// of the allow list. An empty allow list denies every repository.
+func isRepoAllowed(allowed []string, fullName string) bool {
+	allowList := getAllowList(allowed)
+	if len(allowList) == 0 {
+		log.Warn().Str("repo", fullName).Msg("denying action for repo because allow list is not set.")
+		return false
+	}
+
+	for _, prefix := range allowList {
+		if strings.HasPrefix(fullName, prefix) {
+			log.Debug().Str("repo", fullName).Msg("repo in allow list")
+			return true
+		}
+	}
+
+	log.Warn().Str("repo", fullName).Msg("denying action for repo because not found in allow list.")
+	return false
+}
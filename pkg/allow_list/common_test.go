@@ -3,6 +3,8 @@ package allow_list
 import (
 	"reflect"
 	"testing"
+
+	"github.com/zapier/tfbuddy/internal/config"
 )
 
 func TestGetAllowList(t *testing.T) {
@@ -81,3 +83,50 @@ func TestGetAllowList(t *testing.T) {
 		})
 	}
 }
+
+func TestIsRepoAllowed(t *testing.T) {
+	tests := []struct {
+		name     string
+		fullName string
+		allowed  []string
+		want     bool
+	}{
+		{name: "owner allowed", fullName: "zapier/tfbuddy", allowed: []string{"zapier/"}, want: true},
+		{name: "repo allowed", fullName: "INFRA/terraform", allowed: []string{"zapier/", "INFRA/terraform"}, want: true},
+		{name: "repo denied", fullName: "other/repo", allowed: []string{"zapier/"}, want: false},
+		{name: "allow list not set", fullName: "zapier/tfbuddy", allowed: nil, want: false},
+		{name: "only empty entries", fullName: "zapier/tfbuddy", allowed: []string{" ", ""}, want: false},
+		{name: "case sensitivity", fullName: "infra/terraform", allowed: []string{"INFRA/"}, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isRepoAllowed(tt.allowed, tt.fullName); got != tt.want {
+				t.Errorf("isRepoAllowed() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRepoAllowLists(t *testing.T) {
+	tests := []struct {
+		env       string
+		isAllowed func(config.Config, string) bool
+	}{
+		{env: "TFBUDDY_BITBUCKET_REPO_ALLOW_LIST", isAllowed: IsBitbucketRepoAllowed},
+		{env: "TFBUDDY_GITEA_REPO_ALLOW_LIST", isAllowed: IsGiteaRepoAllowed},
+		{env: "TFBUDDY_AZURE_DEVOPS_REPO_ALLOW_LIST", isAllowed: IsAzureDevOpsRepoAllowed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.env, func(t *testing.T) {
+			t.Setenv(tt.env, "infra/,platform/tfbuddy")
+			config.Reload()
+
+			if !tt.isAllowed(config.C, "platform/tfbuddy") {
+				t.Errorf("expected platform/tfbuddy to be allowed by %s", tt.env)
+			}
+			if tt.isAllowed(config.C, "other/repo") {
+				t.Errorf("expected other/repo to be denied by %s", tt.env)
+			}
+		})
+	}
+}
@@ -0,0 +1,11 @@
+package allow_list
+
+import (
+	"github.com/zapier/tfbuddy/internal/config"
+)
+
+// IsGiteaRepoAllowed matches the repository, owner/repo, against the allow
+// list.
+func IsGiteaRepoAllowed(cfg config.Config, fullName string) bool {
+	return isRepoAllowed(cfg.GiteaRepoAllowList, fullName)
+}
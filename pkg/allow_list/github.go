@@ -1,12 +1,16 @@
 package allow_list
 
 import (
+	"net/url"
 	"strings"
 
 	"github.com/rs/zerolog/log"
 	"github.com/zapier/tfbuddy/internal/config"
 )
 
+// IsGithubRepoAllowed matches the repository against the allow list. Both
+// the repository and the allow list entries may be qualified with the host
+// of the configured GitHub instance, e.g. github.example.com/org/.
 func IsGithubRepoAllowed(cfg config.Config, fullName string) bool {
 	githubAllowList := getAllowList(cfg.GithubRepoAllowList)
 	if len(githubAllowList) == 0 {
@@ -14,8 +18,11 @@ func IsGithubRepoAllowed(cfg config.Config, fullName string) bool {
 		return false
 	}
 
+	host := githubHost(cfg)
+	name := strings.TrimPrefix(fullName, host+"/")
+	qualifiedName := host + "/" + name
 	for _, allowed := range githubAllowList {
-		if strings.HasPrefix(fullName, allowed) {
+		if strings.HasPrefix(name, allowed) || strings.HasPrefix(qualifiedName, allowed) {
 			log.Debug().Str("repo", fullName).Msg("repo in allow list")
 			return true
 		}
@@ -24,3 +31,13 @@ func IsGithubRepoAllowed(cfg config.Config, fullName string) bool {
 	log.Warn().Str("repo", fullName).Msg("denying action for repo because not found in allow list.")
 	return false
 }
+
+// githubHost returns the host of the configured GitHub instance.
+func githubHost(cfg config.Config) string {
+	if cfg.GithubBaseURL != "" {
+		if u, err := url.Parse(cfg.GithubBaseURL); err == nil && u.Host != "" {
+			return u.Host
+		}
+	}
+	return "github.com"
+}
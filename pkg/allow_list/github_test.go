@@ -10,6 +10,7 @@ func TestIsGithubRepoAllowed(t *testing.T) {
 	type args struct {
 		fullName string
 		allowEnv string
+		baseURL  string
 	}
 	tests := []struct {
 		name string
@@ -96,6 +97,41 @@ func TestIsGithubRepoAllowed(t *testing.T) {
 			},
 			want: true,
 		},
+		{
+			name: "host qualified allow list on github.com",
+			args: args{
+				fullName: "org/repo",
+				allowEnv: "github.com/org/",
+			},
+			want: true,
+		},
+		{
+			name: "host qualified allow list on enterprise server",
+			args: args{
+				fullName: "org/repo",
+				allowEnv: "github.example.com/org/",
+				baseURL:  "https://github.example.com/api/v3/",
+			},
+			want: true,
+		},
+		{
+			name: "allow list for another host",
+			args: args{
+				fullName: "org/repo",
+				allowEnv: "github.com/org/",
+				baseURL:  "https://github.example.com/api/v3/",
+			},
+			want: false,
+		},
+		{
+			name: "host qualified repo name",
+			args: args{
+				fullName: "github.example.com/org/repo",
+				allowEnv: "org/",
+				baseURL:  "https://github.example.com/api/v3/",
+			},
+			want: true,
+		},
 		{
 			name: "case sensitivity",
 			args: args{
@@ -109,6 +145,7 @@ func TestIsGithubRepoAllowed(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			t.Setenv("TFBUDDY_GITHUB_REPO_ALLOW_LIST", tt.args.allowEnv)
+			t.Setenv("TFBUDDY_GITHUB_BASE_URL", tt.args.baseURL)
 			config.Reload()
 
 			if got := IsGithubRepoAllowed(config.C, tt.args.fullName); got != tt.want {
@@ -3,6 +3,7 @@ package comment_actions
 import (
 	"errors"
 	"fmt"
+	"regexp"
 	"strings"
 
 	"github.com/jessevdk/go-flags"
@@ -19,6 +20,9 @@ var (
 	ErrPermanent     = fmt.Errorf("could not parse comment as command. %w", utils.ErrPermanent)
 )
 
+// commitSHA matches an abbreviated or full commit SHA named after the command.
+var commitSHA = regexp.MustCompile(`^[0-9a-f]{7,40}$`)
+
 type CommentOpts struct {
 	TriggerOpts *tfc_trigger.TFCTriggerOptions
 	Args        CommentArgs `positional-args:"yes" required:"yes"`
@@ -61,6 +65,9 @@ func ParseCommentCommand(noteBody string) (*CommentOpts, error) {
 	if opts.TriggerOpts.Action == tfc_trigger.InvalidAction {
 		return nil, ErrInvalidAction
 	}
+	if len(opts.Args.Rest) > 0 && commitSHA.MatchString(opts.Args.Rest[0]) {
+		opts.TriggerOpts.CommentSHA = opts.Args.Rest[0]
+	}
 
 	return opts, nil
 }
@@ -80,6 +80,48 @@ func TestParseCommentCommand(t *testing.T) {
 				Command: "apply",
 			},
 		}, nil, "long flag for allow empty run"},
+		{"tfc apply --accept-cost", &CommentOpts{
+			TriggerOpts: &tfc_trigger.TFCTriggerOptions{
+				Action:     tfc_trigger.ApplyAction,
+				AcceptCost: true,
+			},
+			Args: CommentArgs{
+				Agent:   "tfc",
+				Command: "apply",
+			},
+		}, nil, "accept cost"},
+		{"tfc override-policy -w fake_space", &CommentOpts{
+			TriggerOpts: &tfc_trigger.TFCTriggerOptions{
+				Action:    tfc_trigger.OverridePolicyAction,
+				Workspace: "fake_space",
+			},
+			Args: CommentArgs{
+				Agent:   "tfc",
+				Command: "override-policy",
+			},
+		}, nil, "override policy"},
+		{"tfc plan 1A2B3C4D -w fake_space", &CommentOpts{
+			TriggerOpts: &tfc_trigger.TFCTriggerOptions{
+				Action:     tfc_trigger.PlanAction,
+				Workspace:  "fake_space",
+				CommentSHA: "1a2b3c4d",
+			},
+			Args: CommentArgs{
+				Agent:   "tfc",
+				Command: "plan",
+				Rest:    []string{"1a2b3c4d"},
+			},
+		}, nil, "plan pinned to a commit"},
+		{"tfc plan please", &CommentOpts{
+			TriggerOpts: &tfc_trigger.TFCTriggerOptions{
+				Action: tfc_trigger.PlanAction,
+			},
+			Args: CommentArgs{
+				Agent:   "tfc",
+				Command: "plan",
+				Rest:    []string{"please"},
+			},
+		}, nil, "trailing word is not a commit"},
 		{"tfc apply -k", nil, ErrPermanent, "invalid command"},
 	}
 
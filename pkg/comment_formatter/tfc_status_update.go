@@ -1,7 +1,9 @@
 package comment_formatter
 
 import (
+	"context"
 	"fmt"
+	"strconv"
 	"strings"
 
 	"github.com/hashicorp/go-tfe"
@@ -31,7 +33,7 @@ func getProperTargetedApplyText(rmd runstream.RunMetadata, run *tfe.Run, wsName
 func FormatRunStatusCommentBody(cfg config.Config, tfc tfc_api.ApiClient, run *tfe.Run, rmd runstream.RunMetadata) (main, toplevel string, resolve bool) {
 	wsName := run.Workspace.Name
 	org := run.Workspace.Organization.Name
-	runUrl := fmt.Sprintf("https://app.terraform.io/app/%s/workspaces/%s/runs/%s", org, wsName, run.ID)
+	runUrl := tfc_api.RunURL(cfg, org, wsName, run.ID)
 
 	extraInfo := ""
 	resolveDiscussion := false
@@ -56,7 +58,8 @@ func FormatRunStatusCommentBody(cfg config.Config, tfc tfc_api.ApiClient, run *t
 	case tfe.RunDiscarded:
 		// no extra info
 	case tfe.RunErrored:
-		if rmd.GetAction() == runstream.PlanAction {
+		extraInfo += errorLogSection(cfg, tfc, run)
+		if extraInfo == "" && rmd.GetAction() == runstream.PlanAction {
 			extraInfo += failedPlanSummaryFormat
 		}
 
@@ -67,6 +70,7 @@ func FormatRunStatusCommentBody(cfg config.Config, tfc tfc_api.ApiClient, run *t
 		}
 	case tfe.RunPlanned:
 		extraInfo = fmt.Sprintf(successPlanSummaryFormat, run.Apply.ResourceImports, run.Plan.ResourceAdditions, run.Plan.ResourceChanges, run.Plan.ResourceDestructions)
+		extraInfo += formatCostEstimate(run.CostEstimate)
 		if !run.AutoApply {
 			if len(run.TargetAddrs) > 0 {
 				extraInfo += getProperTargetedApplyText(rmd, run, wsName)
@@ -83,6 +87,7 @@ func FormatRunStatusCommentBody(cfg config.Config, tfc tfc_api.ApiClient, run *t
 		} else {
 			extraInfo += "<br>" + terraform_plan.PresentPlanChangesAsMarkdown(b, runUrl) + "</br>"
 		}
+		extraInfo += formatCostEstimate(run.CostEstimate)
 		log.Trace().Str("plan_id", run.Plan.ID).Str("plan_json", string(b)).Msg("")
 
 		if hasChanges(run.Plan) {
@@ -108,12 +113,18 @@ func FormatRunStatusCommentBody(cfg config.Config, tfc tfc_api.ApiClient, run *t
 		} else {
 			extraInfo = "Policy Checks: Soft Failed — approval required in Terraform Cloud before apply can proceed."
 		}
+		extraInfo += policyResultsSection(tfc, run, wsName)
+	case tfe.RunPostPlanAwaitingDecision:
+		// Policy evaluations failed; an override is required before apply
+		extraInfo = "Policy Checks: Failed — an override is required before apply can proceed."
+		extraInfo += policyResultsSection(tfc, run, wsName)
 	case tfe.RunPolicyChecked:
 		// Policy checks completed successfully
 		extraInfo = "Policy Checks: Passed."
 		if !run.AutoApply {
 			extraInfo += " Plan requires confirmation through the Terraform Cloud console. Click Run URL link to open & confirm."
 		}
+		extraInfo += policyResultsSection(tfc, run, wsName)
 
 	default:
 		log.Debug().Str("project", rmd.GetMRProjectNameWithNamespace()).Int("mergeRequestID", rmd.GetMRInternalID()).Str("run_status", string(run.Status)).Msg("No action defined for status.")
@@ -132,6 +143,119 @@ func FormatRunStatusCommentBody(cfg config.Config, tfc tfc_api.ApiClient, run *t
 
 }
 
+// formatCostEstimate renders the monthly cost estimated by TFC for the run, if
+// any.
+func formatCostEstimate(ce *tfe.CostEstimate) string {
+	delta, ok := tfc_api.MonthlyCostDelta(ce)
+	if !ok {
+		return ""
+	}
+	sign := "+"
+	if delta < 0 {
+		sign = "-"
+		delta = -delta
+	}
+	return fmt.Sprintf(costEstimateFormat, formatUSD(ce.PriorMonthlyCost), formatUSD(ce.ProposedMonthlyCost), sign, delta)
+}
+
+// errorLogSection renders the Terraform errors of the plan or apply log of an
+// errored run, with secrets redacted and at most cfg.TFCErrorLogMaxLength
+// characters long.
+func errorLogSection(cfg config.Config, tfc tfc_api.ApiClient, run *tfe.Run) string {
+	if cfg.TFCErrorLogMaxLength <= 0 {
+		return ""
+	}
+	var logs []byte
+	var err error
+	switch {
+	case run.Apply != nil && run.Apply.Status == tfe.ApplyErrored:
+		logs, err = tfc.GetApplyLogs(context.Background(), run.Apply.ID)
+	case run.Plan != nil && run.Plan.ID != "":
+		logs, err = tfc.GetPlanLogs(context.Background(), run.Plan.ID)
+	default:
+		return ""
+	}
+	if err != nil {
+		log.Error().Err(err).Str("run", run.ID).Msg("could not get run logs")
+		return ""
+	}
+	diags := tfc_api.ErrorDiagnostics(logs)
+	if len(diags) == 0 {
+		return ""
+	}
+
+	text := utils.RedactSecrets(strings.Join(diags, "\n\n"))
+	if len(text) > cfg.TFCErrorLogMaxLength {
+		text = strings.ToValidUTF8(text[:cfg.TFCErrorLogMaxLength], "")
+		if i := strings.LastIndex(text, "\n"); i > 0 {
+			text = text[:i]
+		}
+		text += "\n\n" + truncatedErrorLog
+	}
+	fence := codeFence(text)
+	return fmt.Sprintf(errorLogFormat, len(diags), fence, text, fence)
+}
+
+// codeFence returns a code fence longer than any backtick run in text.
+func codeFence(text string) string {
+	longest, current := 0, 0
+	for _, r := range text {
+		if r == '`' {
+			current++
+			longest = max(longest, current)
+		} else {
+			current = 0
+		}
+	}
+	return strings.Repeat("`", max(3, longest+1))
+}
+
+// policyResultsSection renders the policies of the run that did not pass and
+// how to override them.
+func policyResultsSection(tfc tfc_api.ApiClient, run *tfe.Run, wsName string) string {
+	results, err := tfc.GetPolicyResults(context.Background(), run.ID)
+	if err != nil {
+		log.Error().Err(err).Str("run", run.ID).Msg("could not get policy results")
+		return ""
+	}
+	failed := results.Failed()
+	if len(failed) == 0 {
+		return ""
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, policyResultsFormat, len(results.Outcomes)-len(failed), len(failed))
+	for _, o := range failed {
+		fmt.Fprintf(&b, "| %s | %s | %s | %s | %s |\n",
+			escapeTableCell(o.PolicySet), escapeTableCell(o.Policy), o.EnforcementLevel,
+			policyStatusEmoji(o.Status)+" "+o.Status, escapeTableCell(o.Message))
+	}
+	if results.Overridable {
+		fmt.Fprintf(&b, howToOverridePolicyFormat, wsName)
+	}
+	return b.String()
+}
+
+func policyStatusEmoji(status string) string {
+	if status == tfc_api.PolicyOutcomeErrored {
+		return ":warning:"
+	}
+	return ":x:"
+}
+
+// escapeTableCell keeps text on a single markdown table row.
+func escapeTableCell(text string) string {
+	text = strings.ReplaceAll(text, "|", "\\|")
+	return strings.Join(strings.Fields(text), " ")
+}
+
+func formatUSD(amount string) string {
+	v, err := strconv.ParseFloat(amount, 64)
+	if err != nil {
+		return amount
+	}
+	return fmt.Sprintf("$%.2f", v)
+}
+
 func hasChanges(plan *tfe.Plan) bool {
 	if plan.ResourceAdditions > 0 {
 		return true
@@ -161,12 +285,42 @@ var failedPlanSummaryFormat = `
 *Click Terraform Cloud URL to see detailed plan output*
 `
 
+var errorLogFormat = `
+
+<details><summary>Terraform errors (%d)</summary>
+
+%s
+%s
+%s
+
+</details>
+`
+
+var truncatedErrorLog = `[truncated, see the Run URL for the full log]`
+
 var successPlanSummaryFormat = `
   * Imports: %d
   * Additions: %d
   * Changes: %d
   * Destructions: %d`
 
+var costEstimateFormat = `
+
+**Monthly cost estimate**: %s → %s (%s$%.2f)`
+
+var policyResultsFormat = `
+
+**Policies**: %d passed, %d not passed
+
+| Policy set | Policy | Enforcement | Result | Message |
+| ---------- | ------ | ----------- | ------ | ------- |
+`
+
+var howToOverridePolicyFormat = `
+* To **override** the failed policies and continue the apply, comment:
+	> ` + "`tfc override-policy -w %s`" + `
+`
+
 var manualMRMergeSnippet = `Remember to **merge** the MR once the apply has succeeded`
 var autoMRMergeSnippet = `Your MR will be **automatically** merged once the apply has succeeded`
 var howToApplyFormat = `
@@ -1,6 +1,7 @@
 package git
 
 import (
+	"errors"
 	"fmt"
 	"io/fs"
 	"os"
@@ -19,6 +20,10 @@ type Repository struct {
 	*git.Repository
 	authentication *githttp.BasicAuth
 	localDir       string
+	// caBundle and insecureSkipTLS are used when fetching from servers with
+	// certificates not trusted by the system.
+	caBundle        []byte
+	insecureSkipTLS bool
 }
 
 func NewRepository(repo *git.Repository, auth *githttp.BasicAuth, localDir string) *Repository {
@@ -28,14 +33,24 @@ func NewRepository(repo *git.Repository, auth *githttp.BasicAuth, localDir strin
 		Repository:     repo,
 	}
 }
+
+// WithTLS sets the TLS options used when fetching from the remote.
+func (gr *Repository) WithTLS(caBundle []byte, insecureSkipTLS bool) *Repository {
+	gr.caBundle = caBundle
+	gr.insecureSkipTLS = insecureSkipTLS
+	return gr
+}
+
 func (gr *Repository) GetLocalDirectory() string {
 	return gr.localDir
 }
 func (gr *Repository) FetchUpstreamBranch(branch string) error {
 	ref := fmt.Sprintf("refs/heads/%s:refs/heads/%s", branch, branch)
 	err := gr.Fetch(&git.FetchOptions{
-		RefSpecs: []config.RefSpec{config.RefSpec(ref)},
-		Auth:     gr.authentication,
+		RefSpecs:        []config.RefSpec{config.RefSpec(ref)},
+		Auth:            gr.authentication,
+		CABundle:        gr.caBundle,
+		InsecureSkipTLS: gr.insecureSkipTLS,
 	})
 	if err != nil && err.Error() != git.NoErrAlreadyUpToDate.Error() {
 		return utils.CreatePermanentError(err)
@@ -144,3 +159,37 @@ func GetCloneDepth(cfg appconfig.Config, envVar string) int {
 	}
 	return 0
 }
+
+// CloneRef clones the single reference ref of the repository at url into dest
+// and checks out commit sha, detached. Unlike a branch clone it supports refs
+// outside refs/heads, such as the head refs GitLab and GitHub keep in the
+// target project for merge requests from forks.
+func CloneRef(dest, url, ref, sha string, opts *git.FetchOptions) (*git.Repository, error) {
+	repo, err := git.PlainInit(dest, false)
+	if errors.Is(err, git.ErrRepositoryAlreadyExists) {
+		repo, err = git.PlainOpen(dest)
+	}
+	if err != nil {
+		return nil, err
+	}
+	_, err = repo.CreateRemote(&config.RemoteConfig{Name: git.DefaultRemoteName, URLs: []string{url}})
+	if err != nil && !errors.Is(err, git.ErrRemoteExists) {
+		return nil, err
+	}
+
+	head := plumbing.NewRemoteReferenceName(git.DefaultRemoteName, "merge-request-head")
+	opts.RemoteName = git.DefaultRemoteName
+	opts.RefSpecs = []config.RefSpec{config.RefSpec(fmt.Sprintf("+%s:%s", ref, head))}
+	if err := repo.Fetch(opts); err != nil && !errors.Is(err, git.NoErrAlreadyUpToDate) {
+		return nil, fmt.Errorf("could not fetch %s: %w", ref, err)
+	}
+
+	wt, err := repo.Worktree()
+	if err != nil {
+		return nil, err
+	}
+	if err := wt.Checkout(&git.CheckoutOptions{Hash: plumbing.NewHash(sha), Force: true}); err != nil {
+		return nil, fmt.Errorf("could not check out %s: %w", sha, err)
+	}
+	return repo, nil
+}
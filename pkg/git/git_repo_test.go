@@ -2,8 +2,12 @@ package git
 
 import (
 	"os"
+	"path/filepath"
 	"testing"
 
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+
 	"github.com/spf13/viper"
 	"github.com/stretchr/testify/assert"
 	"github.com/zapier/tfbuddy/internal/config"
@@ -96,6 +100,46 @@ func TestGetModifiedFileNamesBetweenCommitsNoResults(t *testing.T) {
 	assert.Equal(t, len(modifiedFiles), 0, "expected no files modified between master and test")
 }
 
+func TestCloneRef(t *testing.T) {
+	upstreamDir := t.TempDir()
+	upstream, err := git.PlainInit(upstreamDir, false)
+	assert.Equal(t, nil, err)
+	target := &mocks.TestGitRepo{Repo: upstream}
+	initialCommit, err := target.CreateCommitFileOnCurrentBranch("main.tf", "init commit")
+	assert.Equal(t, nil, err)
+
+	// the fork's commit is only reachable through the merge request head ref
+	err = target.SwitchToBranch("fork")
+	assert.Equal(t, nil, err)
+	forkCommit, err := target.CreateCommitFileOnCurrentBranch("fork.tf", "fork commit")
+	assert.Equal(t, nil, err)
+	err = upstream.Storer.SetReference(plumbing.NewHashReference("refs/merge-requests/7/head", plumbing.NewHash(forkCommit)))
+	assert.Equal(t, nil, err)
+	err = target.SwitchToBranch("master")
+	assert.Equal(t, nil, err)
+	err = upstream.Storer.RemoveReference(plumbing.NewBranchReferenceName("fork"))
+	assert.Equal(t, nil, err)
+
+	dest := t.TempDir()
+	repo, err := CloneRef(dest, upstreamDir, "refs/merge-requests/7/head", forkCommit, &git.FetchOptions{})
+	assert.Equal(t, nil, err)
+	head, err := repo.Head()
+	assert.Equal(t, nil, err)
+	assert.Equal(t, forkCommit, head.Hash().String())
+	_, err = os.Stat(filepath.Join(dest, "fork.tf"))
+	assert.Equal(t, nil, err)
+
+	client := NewRepository(repo, nil, dest)
+	err = client.FetchUpstreamBranch("master")
+	assert.Equal(t, nil, err)
+	common, err := client.GetMergeBase("HEAD", "master")
+	assert.Equal(t, nil, err)
+	assert.Equal(t, initialCommit, common)
+
+	_, err = CloneRef(t.TempDir(), upstreamDir, "refs/merge-requests/8/head", forkCommit, &git.FetchOptions{})
+	assert.NotEqual(t, nil, err)
+}
+
 func TestGitCloneDepth(t *testing.T) {
 	testVar := "git-clone-test"
 	defer os.Unsetenv(testVar)
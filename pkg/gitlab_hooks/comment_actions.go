@@ -20,6 +20,18 @@ type deliveryIDProvider interface {
 	GetDeliveryID() string
 }
 
+// noteIDProvider is the optional accessor for the ID of the note that
+// triggered the event, used to react to it.
+type noteIDProvider interface {
+	GetNoteID() int64
+}
+
+// commenterProvider is the optional accessor for the username of the author
+// of the note, needed to run merge requests from forks.
+type commenterProvider interface {
+	GetCommenter() string
+}
+
 // processNoteEvent processes GitLab Webhooks for Note events
 // In the Gitlab API, MR comments are called Notes
 func (w *GitlabEventWorker) processNoteEvent(ctx context.Context, event vcs.MRCommentEvent) (projectName string, err error) {
@@ -28,6 +40,9 @@ func (w *GitlabEventWorker) processNoteEvent(ctx context.Context, event vcs.MRCo
 
 	proj := event.GetProject().GetPathWithNamespace()
 	if !allow_list.IsGitlabProjectAllowed(w.cfg, proj) {
+		if _, err := comment_actions.ParseCommentCommand(event.GetAttributes().GetNote()); err == nil {
+			w.react(ctx, event, vcs.ReactionRefused)
+		}
 		return proj, nil
 	}
 
@@ -43,9 +58,14 @@ func (w *GitlabEventWorker) processNoteEvent(ctx context.Context, event vcs.MRCo
 		}
 		return proj, err
 	}
+	w.react(ctx, event, vcs.ReactionPickedUp)
 
 	opts.TriggerOpts.Branch = event.GetMR().GetSourceBranch()
 	opts.TriggerOpts.CommitSHA = event.GetLastCommit().GetSHA()
+	if opts.TriggerOpts.CommentSHA == "" {
+		// the note event carries the head commit at the time of the comment
+		opts.TriggerOpts.CommentSHA = opts.TriggerOpts.CommitSHA
+	}
 	opts.TriggerOpts.ProjectNameWithNamespace = proj
 	opts.TriggerOpts.MergeRequestIID = event.GetMR().GetInternalID()
 	opts.TriggerOpts.TriggerSource = tfc_trigger.CommentTrigger
@@ -53,6 +73,9 @@ func (w *GitlabEventWorker) processNoteEvent(ctx context.Context, event vcs.MRCo
 	if dp, ok := event.(deliveryIDProvider); ok {
 		opts.TriggerOpts.DeliveryID = dp.GetDeliveryID()
 	}
+	if cp, ok := event.(commenterProvider); ok {
+		opts.TriggerOpts.Commenter = cp.GetCommenter()
+	}
 
 	cfg, err := tfc_trigger.NewTFCTriggerConfig(opts.TriggerOpts)
 	if err != nil {
@@ -86,14 +109,18 @@ func (w *GitlabEventWorker) processNoteEvent(ctx context.Context, event vcs.MRCo
 		log.Debug().Str("project", proj).Int("mergeRequestID", event.GetMR().GetInternalID()).Msg("Got TFC apply command")
 		if !w.checkApproval(ctx, event) {
 			w.postMessageToMergeRequest(ctx, event, ":no_entry: Apply failed. Merge Request requires approval.")
+			w.react(ctx, event, vcs.ReactionRefused)
 			return proj, nil
 		}
 		if !w.checkForMergeConflicts(ctx, event) {
 			w.postMessageToMergeRequest(ctx, event, ":no_entry: Apply failed. Merge Request has conflicts that need to be resolved.")
+			w.react(ctx, event, vcs.ReactionRefused)
 			return proj, nil
 		}
 	case "lock":
 		log.Debug().Str("project", proj).Int("mergeRequestID", event.GetMR().GetInternalID()).Msg("Got TFC lock command")
+	case "override-policy":
+		log.Debug().Str("project", proj).Int("mergeRequestID", event.GetMR().GetInternalID()).Msg("Got TFC override-policy command")
 	case "plan":
 		log.Debug().Str("project", proj).Int("mergeRequestID", event.GetMR().GetInternalID()).Msg("Got TFC plan command")
 	case "unlock":
@@ -102,6 +129,9 @@ func (w *GitlabEventWorker) processNoteEvent(ctx context.Context, event vcs.MRCo
 		return proj, nil
 	}
 	executedWorkspaces, tfError := trigger.TriggerTFCEvents(ctx)
+	for _, reaction := range executedWorkspaces.Reactions(tfError) {
+		w.react(ctx, event, reaction)
+	}
 	if tfError == nil && executedWorkspaces != nil {
 		if len(executedWorkspaces.Errored) > 0 {
 			for _, failedWS := range executedWorkspaces.Errored {
@@ -117,6 +147,18 @@ func (w *GitlabEventWorker) processNoteEvent(ctx context.Context, event vcs.MRCo
 
 }
 
+// react adds reaction to the note that triggered event.
+func (w *GitlabEventWorker) react(ctx context.Context, event vcs.MRCommentEvent, reaction vcs.Reaction) {
+	if _, ok := w.gl.(vcs.Reactions); !ok {
+		return
+	}
+	np, ok := event.GetAttributes().(noteIDProvider)
+	if !ok {
+		return
+	}
+	vcs.React(ctx, w.gl, event.GetProject().GetPathWithNamespace(), event.GetMR().GetInternalID(), np.GetNoteID(), reaction)
+}
+
 func (w *GitlabEventWorker) checkApproval(ctx context.Context, event vcs.MRCommentEvent) bool {
 	ctx, span := otel.Tracer("hooks").Start(ctx, "checkApproval")
 	defer span.End()
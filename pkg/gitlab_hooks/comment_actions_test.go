@@ -15,6 +15,8 @@ import (
 	"github.com/zapier/tfbuddy/pkg/tfc_api"
 	"github.com/zapier/tfbuddy/pkg/tfc_trigger"
 	"github.com/zapier/tfbuddy/pkg/vcs"
+	"github.com/zapier/tfbuddy/pkg/vcs/gitlab"
+	gogitlab "gitlab.com/gitlab-org/api/client-go"
 	"go.uber.org/mock/gomock"
 )
 
@@ -445,3 +447,102 @@ func TestProcessNoteEventNoErrorNoRuns(t *testing.T) {
 		t.Fatal("expected a project name to be returned")
 	}
 }
+
+// reactingGitClient is a GitClient that supports reactions.
+type reactingGitClient struct {
+	*mocks.MockGitClient
+	*mocks.MockReactions
+}
+
+func TestProcessNoteEventReactions(t *testing.T) {
+	tests := []struct {
+		name          string
+		project       string
+		note          string
+		approved      bool
+		triggered     *tfc_trigger.TriggeredTFCWorkspaces
+		triggerErr    error
+		wantReactions []vcs.Reaction
+	}{
+		{
+			name:          "runs dispatched",
+			project:       "zapier/tfbuddy",
+			note:          "tfc plan",
+			triggered:     &tfc_trigger.TriggeredTFCWorkspaces{Executed: []string{"service-tf-buddy"}},
+			wantReactions: []vcs.Reaction{vcs.ReactionPickedUp, vcs.ReactionDispatched},
+		},
+		{
+			name:          "workspace errored",
+			project:       "zapier/tfbuddy",
+			note:          "tfc plan",
+			triggered:     &tfc_trigger.TriggeredTFCWorkspaces{Errored: []*tfc_trigger.ErroredWorkspace{{Name: "service-tf-buddy", Error: "locked"}}},
+			wantReactions: []vcs.Reaction{vcs.ReactionPickedUp, vcs.ReactionRefused},
+		},
+		{
+			name:          "trigger failed",
+			project:       "zapier/tfbuddy",
+			note:          "tfc plan",
+			triggerErr:    fmt.Errorf("something went wrong"),
+			wantReactions: []vcs.Reaction{vcs.ReactionPickedUp, vcs.ReactionRefused},
+		},
+		{
+			name:          "apply not approved",
+			project:       "zapier/tfbuddy",
+			note:          "tfc apply",
+			wantReactions: []vcs.Reaction{vcs.ReactionPickedUp, vcs.ReactionRefused},
+		},
+		{
+			name:          "project not allowed",
+			project:       "other/tfbuddy",
+			note:          "tfc apply",
+			wantReactions: []vcs.Reaction{vcs.ReactionRefused},
+		},
+		{
+			name:    "not a command",
+			project: "other/tfbuddy",
+			note:    "looks good",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			mockGitClient := mocks.NewMockGitClient(mockCtrl)
+			mockGitClient.EXPECT().CreateMergeRequestComment(gomock.Any(), 101, tt.project, gomock.Any()).Return(nil).AnyTimes()
+			mockApproval := mocks.NewMockMRApproved(mockCtrl)
+			mockApproval.EXPECT().IsApproved().Return(tt.approved).AnyTimes()
+			mockGitClient.EXPECT().GetMergeRequestApprovals(gomock.Any(), 101, tt.project).Return(mockApproval, nil).AnyTimes()
+
+			var gotReactions []vcs.Reaction
+			mockReactions := mocks.NewMockReactions(mockCtrl)
+			mockReactions.EXPECT().AddCommentReaction(gomock.Any(), tt.project, 101, int64(55), gomock.Any()).
+				DoAndReturn(func(_ context.Context, _ string, _ int, _ int64, reaction vcs.Reaction) error {
+					gotReactions = append(gotReactions, reaction)
+					return nil
+				}).AnyTimes()
+
+			mockTFCTrigger := mocks.NewMockTrigger(mockCtrl)
+			mockTFCTrigger.EXPECT().TriggerTFCEvents(gomock.Any()).Return(tt.triggered, tt.triggerErr).AnyTimes()
+
+			w := &GitlabEventWorker{
+				cfg: config.Config{GitlabProjectAllowList: []string{"zapier/"}},
+				gl:  &reactingGitClient{mockGitClient, mockReactions},
+				triggerCreation: func(appCfg config.Config, gl vcs.GitClient, tfc tfc_api.ApiClient, runstream runstream.StreamClient, cfg *tfc_trigger.TFCTriggerOptions) tfc_trigger.Trigger {
+					return mockTFCTrigger
+				},
+			}
+
+			event := &gogitlab.MergeCommentEvent{}
+			event.Project.PathWithNamespace = tt.project
+			event.ObjectAttributes.ID = 55
+			event.ObjectAttributes.Note = tt.note
+			event.MergeRequest.IID = 101
+			event.MergeRequest.SourceBranch = "feature"
+			event.MergeRequest.LastCommit.ID = "abc123"
+
+			w.processNoteEvent(context.Background(), &NoteEventMsg{Payload: &gitlab.GitlabMergeCommentEvent{MergeCommentEvent: event}})
+			assert.Equal(t, tt.wantReactions, gotReactions)
+		})
+	}
+}
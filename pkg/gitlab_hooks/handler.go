@@ -2,7 +2,9 @@ package gitlab_hooks
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
 
 	"github.com/prometheus/client_golang/prometheus"
@@ -116,10 +118,11 @@ func (h *GitlabHooksHandler) handler(c echo.Context) error {
 		ctx, span := otel.Tracer("GitlabHandler").Start(c.Request().Context(), "Gitlab - MergeRequestHook")
 		defer span.End()
 
-		event, err := getGitlabEventBody[gogitlab.MergeEvent](c)
+		event, wip, err := getMergeEventBody(c)
 		if checkError(ctx, err, "could not decode merge request event") {
 			break
 		}
+		h.qualifyMergeEvent(event)
 		log.Info().Str("project", event.Project.PathWithNamespace).Str("action", event.ObjectAttributes.Action).Int("mergeRequestID", event.ObjectAttributes.IID).Msg("processing GitLab Merge Request event")
 
 		span.SetAttributes(
@@ -131,6 +134,7 @@ func (h *GitlabHooksHandler) handler(c echo.Context) error {
 		msg := &MergeRequestEventMsg{
 			GitlabHookEvent: GitlabHookEvent{},
 			Payload:         event,
+			WorkInProgress:  wip,
 			DeliveryID:      gitlabDeliveryID(c.Request()),
 		}
 
@@ -147,6 +151,7 @@ func (h *GitlabHooksHandler) handler(c echo.Context) error {
 			break
 		}
 		event.DeliveryID = gitlabDeliveryID(c.Request())
+		h.qualifyCommentEvent(event.Payload.MergeCommentEvent)
 		log.Info().Str("project", event.Payload.GetProject().GetPathWithNamespace()).Int("mergeRequestID", event.Payload.GetMR().GetInternalID()).Str("discussionID", event.Payload.GetDiscussionID()).Msg("processing GitLab Note/Comment event")
 
 		proj = event.Payload.GetProject().GetPathWithNamespace()
@@ -173,6 +178,32 @@ func (h *GitlabHooksHandler) handler(c echo.Context) error {
 	return c.String(http.StatusOK, "OK")
 }
 
+// qualifyMergeEvent prefixes project paths of additional GitLab instances
+// with their host, so the event is processed with that instance's client.
+func (h *GitlabHooksHandler) qualifyMergeEvent(event *gogitlab.MergeEvent) {
+	if len(h.cfg.GitlabInstances) == 0 {
+		return
+	}
+	event.Project.PathWithNamespace = gitlab.QualifiedProjectPath(h.cfg, event.Project.WebURL, event.Project.PathWithNamespace)
+	h.qualifyRepository(event.ObjectAttributes.Source)
+	h.qualifyRepository(event.ObjectAttributes.Target)
+}
+
+func (h *GitlabHooksHandler) qualifyCommentEvent(event *gogitlab.MergeCommentEvent) {
+	if len(h.cfg.GitlabInstances) == 0 {
+		return
+	}
+	event.Project.PathWithNamespace = gitlab.QualifiedProjectPath(h.cfg, event.Project.WebURL, event.Project.PathWithNamespace)
+	h.qualifyRepository(event.MergeRequest.Source)
+	h.qualifyRepository(event.MergeRequest.Target)
+}
+
+func (h *GitlabHooksHandler) qualifyRepository(repo *gogitlab.Repository) {
+	if repo != nil {
+		repo.PathWithNamespace = gitlab.QualifiedProjectPath(h.cfg, repo.WebURL, repo.PathWithNamespace)
+	}
+}
+
 func getGitlabEventBody[T any](c echo.Context) (*T, error) {
 	event := new(T)
 
@@ -184,6 +215,29 @@ func getGitlabEventBody[T any](c echo.Context) (*T, error) {
 	return event, nil
 }
 
+// getMergeEventBody decodes a merge request event along with its
+// `work_in_progress` change, which gogitlab.MergeEvent does not include.
+func getMergeEventBody(c echo.Context) (*gogitlab.MergeEvent, *BoolChange, error) {
+	b, err := io.ReadAll(c.Request().Body)
+	if err != nil {
+		return nil, nil, err
+	}
+	event := &gogitlab.MergeEvent{}
+	if err := json.Unmarshal(b, event); err != nil {
+		log.Error().Err(err).Msg("failed to unmarshall event payload")
+		return nil, nil, err
+	}
+	var changes struct {
+		Changes struct {
+			WorkInProgress *BoolChange `json:"work_in_progress"`
+		} `json:"changes"`
+	}
+	if err := json.Unmarshal(b, &changes); err != nil {
+		return nil, nil, err
+	}
+	return event, changes.Changes.WorkInProgress, nil
+}
+
 func getNoteEventBody(c echo.Context) (*NoteEventMsg, error) {
 	event, err := getGitlabEventBody[gogitlab.MergeCommentEvent](c)
 	if err != nil {
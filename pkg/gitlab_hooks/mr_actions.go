@@ -33,7 +33,7 @@ func (w *GitlabEventWorker) processMergeRequestEvent(msg *MergeRequestEventMsg)
 		Action:                   tfc_trigger.PlanAction,
 		Branch:                   event.ObjectAttributes.SourceBranch,
 		CommitSHA:                event.ObjectAttributes.LastCommit.ID,
-		ProjectNameWithNamespace: event.ObjectAttributes.Source.PathWithNamespace,
+		ProjectNameWithNamespace: event.Project.PathWithNamespace,
 		MergeRequestIID:          event.ObjectAttributes.IID,
 		TriggerSource:            tfc_trigger.MergeRequestEventTrigger,
 		VcsProvider:              "gitlab",
@@ -60,6 +60,11 @@ func (w *GitlabEventWorker) processMergeRequestEvent(msg *MergeRequestEventMsg)
 			_, err := trigger.TriggerTFCEvents(ctx)
 			return projectName, err
 		}
+		// drafts are not planned on push, so plan once the MR is marked ready
+		if msg.MarkedReady() {
+			_, err := trigger.TriggerTFCEvents(ctx)
+			return projectName, err
+		}
 
 	case "merge", "close":
 		return projectName, trigger.TriggerCleanupEvent(ctx)
@@ -0,0 +1,37 @@
+package gitlab_hooks
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMergeRequestEventMsg_MarkedReady(t *testing.T) {
+	tests := []struct {
+		name    string
+		changes string
+		want    bool
+	}{
+		{"draft removed", `{"draft":{"previous":true,"current":false}}`, true},
+		{"work in progress removed", `{"work_in_progress":{"previous":true,"current":false}}`, true},
+		{"marked as draft", `{"draft":{"previous":false,"current":true},"work_in_progress":{"previous":false,"current":true}}`, false},
+		{"title changed", `{"title":{"previous":"a","current":"b"}}`, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body := `{"object_kind":"merge_request","object_attributes":{"iid":1,"action":"update"},"changes":` + tt.changes + `}`
+			req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			c := echo.New().NewContext(req, httptest.NewRecorder())
+
+			event, wip, err := getMergeEventBody(c)
+			assert.NoError(t, err)
+			msg := &MergeRequestEventMsg{Payload: event, WorkInProgress: wip}
+			assert.Equal(t, tt.want, msg.MarkedReady())
+		})
+	}
+}
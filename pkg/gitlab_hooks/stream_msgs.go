@@ -90,6 +90,10 @@ func (e *NoteEventMsg) GetDeliveryID() string {
 	return e.DeliveryID
 }
 
+func (e *NoteEventMsg) GetCommenter() string {
+	return e.Payload.GetCommenter()
+}
+
 // ----------------------------------------------
 
 func mrEventsStreamSubject() string {
@@ -100,12 +104,30 @@ type MergeRequestEventMsg struct {
 	GitlabHookEvent
 
 	Payload *gogitlab.MergeEvent `json:"payload"`
+	// WorkInProgress is the `work_in_progress` change sent by GitLab versions
+	// predating drafts, which the client library does not decode.
+	WorkInProgress *BoolChange `json:"workInProgress,omitempty"`
 	// DeliveryID anchors the workspace fan-out dedup key to the upstream webhook.
 	DeliveryID string                 `json:"deliveryID"`
 	Carrier    propagation.MapCarrier `json:"Carrier"`
 	Context    context.Context
 }
 
+// BoolChange is the previous and current value of a boolean attribute changed
+// by a merge request event.
+type BoolChange struct {
+	Previous bool `json:"previous"`
+	Current  bool `json:"current"`
+}
+
+// MarkedReady reports whether the event marks a draft merge request as ready.
+func (e *MergeRequestEventMsg) MarkedReady() bool {
+	if e.Payload.Changes.Draft.Previous && !e.Payload.Changes.Draft.Current {
+		return true
+	}
+	return e.WorkInProgress != nil && e.WorkInProgress.Previous && !e.WorkInProgress.Current
+}
+
 func (e *MergeRequestEventMsg) GetId(ctx context.Context) string {
 	return fmt.Sprintf("%d-%s-%s", e.Payload.ObjectAttributes.ID, e.Payload.ObjectAttributes.Action, e.Payload.ObjectAttributes.LastCommit.ID)
 }
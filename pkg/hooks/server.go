@@ -1,6 +1,8 @@
 package hooks
 
 import (
+	"net/http"
+
 	"github.com/heptiolabs/healthcheck"
 	"github.com/labstack/echo-contrib/prometheus"
 	"github.com/labstack/echo/v4"
@@ -11,6 +13,9 @@ import (
 	"github.com/zapier/tfbuddy/pkg/hooks_stream"
 	"github.com/zapier/tfbuddy/pkg/tfc_trigger"
 	"github.com/zapier/tfbuddy/pkg/vcs"
+	"github.com/zapier/tfbuddy/pkg/vcs/azuredevops"
+	"github.com/zapier/tfbuddy/pkg/vcs/bitbucket"
+	"github.com/zapier/tfbuddy/pkg/vcs/gitea"
 	"github.com/zapier/tfbuddy/pkg/vcs/github"
 	"github.com/ziflex/lecho/v3"
 	"go.opentelemetry.io/contrib/instrumentation/github.com/labstack/echo/otelecho"
@@ -20,6 +25,9 @@ import (
 	"github.com/zapier/tfbuddy/pkg/runstream"
 	"github.com/zapier/tfbuddy/pkg/tfc_api"
 	"github.com/zapier/tfbuddy/pkg/tfc_hooks"
+	adoHooks "github.com/zapier/tfbuddy/pkg/vcs/azuredevops/hooks"
+	bbHooks "github.com/zapier/tfbuddy/pkg/vcs/bitbucket/hooks"
+	giteaHooks "github.com/zapier/tfbuddy/pkg/vcs/gitea/hooks"
 	ghHooks "github.com/zapier/tfbuddy/pkg/vcs/github/hooks"
 	"github.com/zapier/tfbuddy/pkg/vcs/gitlab"
 )
@@ -38,6 +46,15 @@ func StartServer(cfg config.Config) {
 	e.GET("/ready", echo.WrapHandler(health))
 	e.GET("/live", echo.WrapHandler(health))
 
+	// serve the .tfbuddy.yaml JSON Schema so editors can validate project config
+	projectSchema, err := tfc_trigger.ProjectConfigSchema()
+	if err != nil {
+		log.Fatal().Err(err).Msg("could not generate .tfbuddy.yaml schema")
+	}
+	e.GET(tfc_trigger.ProjectConfigSchemaID, func(c echo.Context) error {
+		return c.Blob(http.StatusOK, "application/schema+json", projectSchema)
+	})
+
 	// setup NATS client & streams
 	nc := tfnats.Connect(cfg)
 	js, err := nc.JetStream(nats.PublishAsyncMaxPending(256))
@@ -53,9 +70,26 @@ func StartServer(cfg config.Config) {
 	health.AddLivenessCheck("hook-stream", hs.HealthCheck)
 
 	// setup API clients
-	gl := gitlab.NewGitlabClient(cfg)
+	gl := gitlab.NewGitlabClients(cfg)
 	gh := github.NewGithubClient(cfg)
-	tfc := tfc_api.NewTFCClient()
+	bb := bitbucket.NewBitbucketClient(cfg)
+	ado := azuredevops.NewAzureDevOpsClient(cfg)
+	gt := gitea.NewGiteaClient(cfg)
+	tfc := tfc_api.NewTFCClient(cfg)
+
+	vcsClients := map[string]vcs.GitClient{
+		"gitlab": gl,
+		"github": gh,
+	}
+	if bb != nil {
+		vcsClients["bitbucket"] = bb
+	}
+	if ado != nil {
+		vcsClients["azuredevops"] = ado
+	}
+	if gt != nil {
+		vcsClients["gitea"] = gt
+	}
 
 	// Per-workspace fan-out queue. Flagged so operators can fall back to the
 	// legacy inline path during rollout.
@@ -65,10 +99,6 @@ func StartServer(cfg config.Config) {
 		if err != nil {
 			log.Fatal().Err(err).Msg("could not configure workspace trigger stream")
 		}
-		vcsClients := map[string]vcs.GitClient{
-			"gitlab": gl,
-			"github": gh,
-		}
 		if _, err := tfc_trigger.NewWorkspaceTriggerWorker(ws, cfg, vcsClients, tfc, rs); err != nil {
 			log.Fatal().Err(err).Msg("could not start workspace trigger worker")
 		}
@@ -102,18 +132,61 @@ func StartServer(cfg config.Config) {
 	hooksGroup.POST("/gitlab/group", gitlabGroupHandler.GroupHandler())
 	hooksGroup.POST("/gitlab/project", gitlabGroupHandler.ProjectHandler())
 
+	//
+	// Bitbucket
+	//
+	if bb != nil {
+		bitbucketHooksHandler := bbHooks.NewBitbucketHooksHandler(cfg, bb, tfc, rs, js, workspaceStream)
+		hooksGroup.POST("/bitbucket", bitbucketHooksHandler.Handler)
+	}
+
+	//
+	// Azure DevOps
+	//
+	if ado != nil {
+		azureDevOpsHooksHandler := adoHooks.NewAzureDevOpsHooksHandler(cfg, ado, tfc, rs, js, workspaceStream)
+		hooksGroup.POST("/azuredevops", azureDevOpsHooksHandler.Handler)
+	}
+
+	//
+	// Gitea / Forgejo
+	//
+	if gt != nil {
+		giteaHooksHandler := giteaHooks.NewGiteaHooksHandler(cfg, gt, tfc, rs, js, workspaceStream)
+		hooksGroup.POST("/gitea", giteaHooksHandler.Handler)
+	}
+
 	//
 	// Terraform Cloud
 	//
-	hooksGroup.POST("/tfc/run_task", tfc_hooks.RunTaskHandler)
+	runTaskHandler := tfc_hooks.NewRunTaskHandler(cfg, tfc, rs, vcsClients)
+	hooksGroup.POST("/tfc/run_task", runTaskHandler.Handler())
 	// Run Notifications Handler
-	notifHandler := tfc_hooks.NewNotificationHandler(tfc, rs)
+	notifHandler := tfc_hooks.NewNotificationHandler(cfg, tfc, rs)
 	hooksGroup.POST("/tfc/notification", notifHandler.Handler())
 
 	// Github Run Events Processor
 	ghep := github.NewRunEventsWorker(cfg, gh, rs, tfc)
 	defer ghep.Close()
 
+	// Bitbucket Run Events Processor
+	if bb != nil {
+		bbep := bitbucket.NewRunEventsWorker(cfg, bb, rs, tfc)
+		defer bbep.Close()
+	}
+
+	// Azure DevOps Run Events Processor
+	if ado != nil {
+		adoep := azuredevops.NewRunEventsWorker(cfg, ado, rs, tfc)
+		defer adoep.Close()
+	}
+
+	// Gitea Run Events Processor
+	if gt != nil {
+		gtep := gitea.NewRunEventsWorker(cfg, gt, rs, tfc)
+		defer gtep.Close()
+	}
+
 	// Gitlab Run Events Processor
 	grsp := gitlab.NewRunStatusProcessor(cfg, gl, rs, tfc)
 	defer grsp.Close()
@@ -5,6 +5,7 @@
 //
 //	mockgen -source interfaces.go -destination=../mocks/mock_runstream.go -package=mocks github.com/zapier/tfbuddy/pkg/runstream
 //
+
 // Package mocks is a generated GoMock package.
 package mocks
 
@@ -21,6 +22,7 @@ import (
 type MockStreamClient struct {
 	ctrl     *gomock.Controller
 	recorder *MockStreamClientMockRecorder
+	isgomock struct{}
 }
 
 // MockStreamClientMockRecorder is the mock recorder for MockStreamClient.
@@ -111,6 +113,20 @@ func (mr *MockStreamClientMockRecorder) PublishTFRunEvent(ctx, re any) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PublishTFRunEvent", reflect.TypeOf((*MockStreamClient)(nil).PublishTFRunEvent), ctx, re)
 }
 
+// StopTFRunPollingTask mocks base method.
+func (m *MockStreamClient) StopTFRunPollingTask(runID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StopTFRunPollingTask", runID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// StopTFRunPollingTask indicates an expected call of StopTFRunPollingTask.
+func (mr *MockStreamClientMockRecorder) StopTFRunPollingTask(runID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StopTFRunPollingTask", reflect.TypeOf((*MockStreamClient)(nil).StopTFRunPollingTask), runID)
+}
+
 // SubscribeTFRunEvents mocks base method.
 func (m *MockStreamClient) SubscribeTFRunEvents(queue string, cb func(runstream.RunEvent) bool) (func(), error) {
 	m.ctrl.T.Helper()
@@ -145,6 +161,7 @@ func (mr *MockStreamClientMockRecorder) SubscribeTFRunPollingTasks(cb any) *gomo
 type MockRunEvent struct {
 	ctrl     *gomock.Controller
 	recorder *MockRunEventMockRecorder
+	isgomock struct{}
 }
 
 // MockRunEventMockRecorder is the mock recorder for MockRunEvent.
@@ -260,6 +277,7 @@ func (mr *MockRunEventMockRecorder) SetMetadata(arg0 any) *gomock.Call {
 type MockRunMetadata struct {
 	ctrl     *gomock.Controller
 	recorder *MockRunMetadataMockRecorder
+	isgomock struct{}
 }
 
 // MockRunMetadataMockRecorder is the mock recorder for MockRunMetadata.
@@ -437,6 +455,7 @@ func (mr *MockRunMetadataMockRecorder) GetWorkspace() *gomock.Call {
 type MockRunPollingTask struct {
 	ctrl     *gomock.Controller
 	recorder *MockRunPollingTaskMockRecorder
+	isgomock struct{}
 }
 
 // MockRunPollingTaskMockRecorder is the mock recorder for MockRunPollingTask.
@@ -56,6 +56,20 @@ func (mr *MockApiClientMockRecorder) AddTags(ctx, workspace, prefix, value any)
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddTags", reflect.TypeOf((*MockApiClient)(nil).AddTags), ctx, workspace, prefix, value)
 }
 
+// CancelRun mocks base method.
+func (m *MockApiClient) CancelRun(ctx context.Context, runID, comment string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CancelRun", ctx, runID, comment)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CancelRun indicates an expected call of CancelRun.
+func (mr *MockApiClientMockRecorder) CancelRun(ctx, runID, comment any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CancelRun", reflect.TypeOf((*MockApiClient)(nil).CancelRun), ctx, runID, comment)
+}
+
 // CreateRunFromSource mocks base method.
 func (m *MockApiClient) CreateRunFromSource(ctx context.Context, opts *tfc_api.ApiRunOptions) (*tfe.Run, error) {
 	m.ctrl.T.Helper()
@@ -71,6 +85,66 @@ func (mr *MockApiClientMockRecorder) CreateRunFromSource(ctx, opts any) *gomock.
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateRunFromSource", reflect.TypeOf((*MockApiClient)(nil).CreateRunFromSource), ctx, opts)
 }
 
+// CreateWorkspace mocks base method.
+func (m *MockApiClient) CreateWorkspace(ctx context.Context, opts *tfc_api.ApiWorkspaceOptions) (*tfe.Workspace, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateWorkspace", ctx, opts)
+	ret0, _ := ret[0].(*tfe.Workspace)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateWorkspace indicates an expected call of CreateWorkspace.
+func (mr *MockApiClientMockRecorder) CreateWorkspace(ctx, opts any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateWorkspace", reflect.TypeOf((*MockApiClient)(nil).CreateWorkspace), ctx, opts)
+}
+
+// EnsureNotificationConfiguration mocks base method.
+func (m *MockApiClient) EnsureNotificationConfiguration(ctx context.Context, workspaceID string, opts tfc_api.NotificationOptions) (tfc_api.NotificationSyncResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EnsureNotificationConfiguration", ctx, workspaceID, opts)
+	ret0, _ := ret[0].(tfc_api.NotificationSyncResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// EnsureNotificationConfiguration indicates an expected call of EnsureNotificationConfiguration.
+func (mr *MockApiClientMockRecorder) EnsureNotificationConfiguration(ctx, workspaceID, opts any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EnsureNotificationConfiguration", reflect.TypeOf((*MockApiClient)(nil).EnsureNotificationConfiguration), ctx, workspaceID, opts)
+}
+
+// GetApplyLogs mocks base method.
+func (m *MockApiClient) GetApplyLogs(ctx context.Context, applyID string) ([]byte, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetApplyLogs", ctx, applyID)
+	ret0, _ := ret[0].([]byte)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetApplyLogs indicates an expected call of GetApplyLogs.
+func (mr *MockApiClientMockRecorder) GetApplyLogs(ctx, applyID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetApplyLogs", reflect.TypeOf((*MockApiClient)(nil).GetApplyLogs), ctx, applyID)
+}
+
+// GetPlanLogs mocks base method.
+func (m *MockApiClient) GetPlanLogs(ctx context.Context, planID string) ([]byte, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPlanLogs", ctx, planID)
+	ret0, _ := ret[0].([]byte)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPlanLogs indicates an expected call of GetPlanLogs.
+func (mr *MockApiClientMockRecorder) GetPlanLogs(ctx, planID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPlanLogs", reflect.TypeOf((*MockApiClient)(nil).GetPlanLogs), ctx, planID)
+}
+
 // GetPlanOutput mocks base method.
 func (m *MockApiClient) GetPlanOutput(id string) ([]byte, error) {
 	m.ctrl.T.Helper()
@@ -86,6 +160,21 @@ func (mr *MockApiClientMockRecorder) GetPlanOutput(id any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPlanOutput", reflect.TypeOf((*MockApiClient)(nil).GetPlanOutput), id)
 }
 
+// GetPolicyResults mocks base method.
+func (m *MockApiClient) GetPolicyResults(ctx context.Context, runID string) (*tfc_api.PolicyResults, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPolicyResults", ctx, runID)
+	ret0, _ := ret[0].(*tfc_api.PolicyResults)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPolicyResults indicates an expected call of GetPolicyResults.
+func (mr *MockApiClientMockRecorder) GetPolicyResults(ctx, runID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPolicyResults", reflect.TypeOf((*MockApiClient)(nil).GetPolicyResults), ctx, runID)
+}
+
 // GetRun mocks base method.
 func (m *MockApiClient) GetRun(ctx context.Context, id string) (*tfe.Run, error) {
 	m.ctrl.T.Helper()
@@ -146,6 +235,51 @@ func (mr *MockApiClientMockRecorder) GetWorkspaceByName(ctx, org, name any) *gom
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkspaceByName", reflect.TypeOf((*MockApiClient)(nil).GetWorkspaceByName), ctx, org, name)
 }
 
+// ListRunsAwaitingPolicyOverride mocks base method.
+func (m *MockApiClient) ListRunsAwaitingPolicyOverride(ctx context.Context, workspaceID, messagePrefix string) ([]*tfe.Run, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListRunsAwaitingPolicyOverride", ctx, workspaceID, messagePrefix)
+	ret0, _ := ret[0].([]*tfe.Run)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListRunsAwaitingPolicyOverride indicates an expected call of ListRunsAwaitingPolicyOverride.
+func (mr *MockApiClientMockRecorder) ListRunsAwaitingPolicyOverride(ctx, workspaceID, messagePrefix any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListRunsAwaitingPolicyOverride", reflect.TypeOf((*MockApiClient)(nil).ListRunsAwaitingPolicyOverride), ctx, workspaceID, messagePrefix)
+}
+
+// ListSpeculativeRuns mocks base method.
+func (m *MockApiClient) ListSpeculativeRuns(ctx context.Context, workspaceID, messagePrefix string) ([]*tfe.Run, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListSpeculativeRuns", ctx, workspaceID, messagePrefix)
+	ret0, _ := ret[0].([]*tfe.Run)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListSpeculativeRuns indicates an expected call of ListSpeculativeRuns.
+func (mr *MockApiClientMockRecorder) ListSpeculativeRuns(ctx, workspaceID, messagePrefix any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListSpeculativeRuns", reflect.TypeOf((*MockApiClient)(nil).ListSpeculativeRuns), ctx, workspaceID, messagePrefix)
+}
+
+// ListWorkspaces mocks base method.
+func (m *MockApiClient) ListWorkspaces(ctx context.Context, org string) ([]*tfe.Workspace, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListWorkspaces", ctx, org)
+	ret0, _ := ret[0].([]*tfe.Workspace)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListWorkspaces indicates an expected call of ListWorkspaces.
+func (mr *MockApiClientMockRecorder) ListWorkspaces(ctx, org any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListWorkspaces", reflect.TypeOf((*MockApiClient)(nil).ListWorkspaces), ctx, org)
+}
+
 // LockUnlockWorkspace mocks base method.
 func (m *MockApiClient) LockUnlockWorkspace(ctx context.Context, workspace, reason, tag string, lock bool) error {
 	m.ctrl.T.Helper()
@@ -160,6 +294,20 @@ func (mr *MockApiClientMockRecorder) LockUnlockWorkspace(ctx, workspace, reason,
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LockUnlockWorkspace", reflect.TypeOf((*MockApiClient)(nil).LockUnlockWorkspace), ctx, workspace, reason, tag, lock)
 }
 
+// OverridePolicy mocks base method.
+func (m *MockApiClient) OverridePolicy(ctx context.Context, runID, comment string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "OverridePolicy", ctx, runID, comment)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// OverridePolicy indicates an expected call of OverridePolicy.
+func (mr *MockApiClientMockRecorder) OverridePolicy(ctx, runID, comment any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OverridePolicy", reflect.TypeOf((*MockApiClient)(nil).OverridePolicy), ctx, runID, comment)
+}
+
 // RemoveTagsByName mocks base method.
 func (m *MockApiClient) RemoveTagsByName(ctx context.Context, workspace string, names []string) error {
 	m.ctrl.T.Helper()
@@ -5,6 +5,7 @@
 //
 //	mockgen -source interfaces.go -destination=../mocks/mock_vcs.go -package=mocks github.com/zapier/tfbuddy/pkg/vcs
 //
+
 // Package mocks is a generated GoMock package.
 package mocks
 
@@ -20,6 +21,7 @@ import (
 type MockGitClient struct {
 	ctrl     *gomock.Controller
 	recorder *MockGitClientMockRecorder
+	isgomock struct{}
 }
 
 // MockGitClientMockRecorder is the mock recorder for MockGitClient.
@@ -144,7 +146,7 @@ func (mr *MockGitClientMockRecorder) GetMergeRequestModifiedFiles(ctx, mrIID, pr
 }
 
 // GetOldRunUrls mocks base method.
-func (m *MockGitClient) GetOldRunUrls(ctx context.Context, mrIID int, project string, rootCommentID int, workspace string, action string) (string, error) {
+func (m *MockGitClient) GetOldRunUrls(ctx context.Context, mrIID int, project string, rootCommentID int, workspace, action string) (string, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "GetOldRunUrls", ctx, mrIID, project, rootCommentID, workspace, action)
 	ret0, _ := ret[0].(string)
@@ -246,10 +248,88 @@ func (mr *MockGitClientMockRecorder) UpdateMergeRequestDiscussionNote(ctx, mrIID
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateMergeRequestDiscussionNote", reflect.TypeOf((*MockGitClient)(nil).UpdateMergeRequestDiscussionNote), ctx, mrIID, noteID, project, discussionID, comment)
 }
 
+// MockReactions is a mock of Reactions interface.
+type MockReactions struct {
+	ctrl     *gomock.Controller
+	recorder *MockReactionsMockRecorder
+	isgomock struct{}
+}
+
+// MockReactionsMockRecorder is the mock recorder for MockReactions.
+type MockReactionsMockRecorder struct {
+	mock *MockReactions
+}
+
+// NewMockReactions creates a new mock instance.
+func NewMockReactions(ctrl *gomock.Controller) *MockReactions {
+	mock := &MockReactions{ctrl: ctrl}
+	mock.recorder = &MockReactionsMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockReactions) EXPECT() *MockReactionsMockRecorder {
+	return m.recorder
+}
+
+// AddCommentReaction mocks base method.
+func (m *MockReactions) AddCommentReaction(ctx context.Context, project string, mrIID int, commentID int64, reaction vcs.Reaction) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddCommentReaction", ctx, project, mrIID, commentID, reaction)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddCommentReaction indicates an expected call of AddCommentReaction.
+func (mr *MockReactionsMockRecorder) AddCommentReaction(ctx, project, mrIID, commentID, reaction any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddCommentReaction", reflect.TypeOf((*MockReactions)(nil).AddCommentReaction), ctx, project, mrIID, commentID, reaction)
+}
+
+// MockMaintainers is a mock of Maintainers interface.
+type MockMaintainers struct {
+	ctrl     *gomock.Controller
+	recorder *MockMaintainersMockRecorder
+	isgomock struct{}
+}
+
+// MockMaintainersMockRecorder is the mock recorder for MockMaintainers.
+type MockMaintainersMockRecorder struct {
+	mock *MockMaintainers
+}
+
+// NewMockMaintainers creates a new mock instance.
+func NewMockMaintainers(ctrl *gomock.Controller) *MockMaintainers {
+	mock := &MockMaintainers{ctrl: ctrl}
+	mock.recorder = &MockMaintainersMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockMaintainers) EXPECT() *MockMaintainersMockRecorder {
+	return m.recorder
+}
+
+// IsMaintainer mocks base method.
+func (m *MockMaintainers) IsMaintainer(ctx context.Context, project, username string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsMaintainer", ctx, project, username)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IsMaintainer indicates an expected call of IsMaintainer.
+func (mr *MockMaintainersMockRecorder) IsMaintainer(ctx, project, username any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsMaintainer", reflect.TypeOf((*MockMaintainers)(nil).IsMaintainer), ctx, project, username)
+}
+
 // MockGitRepo is a mock of GitRepo interface.
 type MockGitRepo struct {
 	ctrl     *gomock.Controller
 	recorder *MockGitRepoMockRecorder
+	isgomock struct{}
 }
 
 // MockGitRepoMockRecorder is the mock recorder for MockGitRepo.
@@ -331,6 +411,7 @@ func (mr *MockGitRepoMockRecorder) GetModifiedFileNamesBetweenCommits(oldest, ne
 type MockMRApproved struct {
 	ctrl     *gomock.Controller
 	recorder *MockMRApprovedMockRecorder
+	isgomock struct{}
 }
 
 // MockMRApprovedMockRecorder is the mock recorder for MockMRApproved.
@@ -368,6 +449,7 @@ func (mr *MockMRApprovedMockRecorder) IsApproved() *gomock.Call {
 type MockMRDiscussion struct {
 	ctrl     *gomock.Controller
 	recorder *MockMRDiscussionMockRecorder
+	isgomock struct{}
 }
 
 // MockMRDiscussionMockRecorder is the mock recorder for MockMRDiscussion.
@@ -405,6 +487,7 @@ func (mr *MockMRDiscussionMockRecorder) GetDiscussionID() *gomock.Call {
 type MockMRDiscussionNotes struct {
 	ctrl     *gomock.Controller
 	recorder *MockMRDiscussionNotesMockRecorder
+	isgomock struct{}
 }
 
 // MockMRDiscussionNotesMockRecorder is the mock recorder for MockMRDiscussionNotes.
@@ -456,6 +539,7 @@ func (mr *MockMRDiscussionNotesMockRecorder) GetMRNotes() *gomock.Call {
 type MockMRNote struct {
 	ctrl     *gomock.Controller
 	recorder *MockMRNoteMockRecorder
+	isgomock struct{}
 }
 
 // MockMRNoteMockRecorder is the mock recorder for MockMRNote.
@@ -493,6 +577,7 @@ func (mr *MockMRNoteMockRecorder) GetNoteID() *gomock.Call {
 type MockDetailedMR struct {
 	ctrl     *gomock.Controller
 	recorder *MockDetailedMRMockRecorder
+	isgomock struct{}
 }
 
 // MockDetailedMRMockRecorder is the mock recorder for MockDetailedMR.
@@ -526,20 +611,6 @@ func (mr *MockDetailedMRMockRecorder) GetAuthor() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAuthor", reflect.TypeOf((*MockDetailedMR)(nil).GetAuthor))
 }
 
-// GetState mocks base method.
-func (m *MockDetailedMR) GetState() string {
-	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetState")
-	ret0, _ := ret[0].(string)
-	return ret0
-}
-
-// GetState indicates an expected call of GetState.
-func (mr *MockDetailedMRMockRecorder) GetState() *gomock.Call {
-	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetState", reflect.TypeOf((*MockDetailedMR)(nil).GetState))
-}
-
 // GetInternalID mocks base method.
 func (m *MockDetailedMR) GetInternalID() int {
 	m.ctrl.T.Helper()
@@ -568,6 +639,20 @@ func (mr *MockDetailedMRMockRecorder) GetSourceBranch() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSourceBranch", reflect.TypeOf((*MockDetailedMR)(nil).GetSourceBranch))
 }
 
+// GetState mocks base method.
+func (m *MockDetailedMR) GetState() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetState")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// GetState indicates an expected call of GetState.
+func (mr *MockDetailedMRMockRecorder) GetState() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetState", reflect.TypeOf((*MockDetailedMR)(nil).GetState))
+}
+
 // GetTargetBranch mocks base method.
 func (m *MockDetailedMR) GetTargetBranch() string {
 	m.ctrl.T.Helper()
@@ -628,6 +713,7 @@ func (mr *MockDetailedMRMockRecorder) HasConflicts() *gomock.Call {
 type MockMR struct {
 	ctrl     *gomock.Controller
 	recorder *MockMRMockRecorder
+	isgomock struct{}
 }
 
 // MockMRMockRecorder is the mock recorder for MockMR.
@@ -703,10 +789,101 @@ func (mr *MockMRMockRecorder) GetTargetBranch() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTargetBranch", reflect.TypeOf((*MockMR)(nil).GetTargetBranch))
 }
 
+// MockDraftMR is a mock of DraftMR interface.
+type MockDraftMR struct {
+	ctrl     *gomock.Controller
+	recorder *MockDraftMRMockRecorder
+	isgomock struct{}
+}
+
+// MockDraftMRMockRecorder is the mock recorder for MockDraftMR.
+type MockDraftMRMockRecorder struct {
+	mock *MockDraftMR
+}
+
+// NewMockDraftMR creates a new mock instance.
+func NewMockDraftMR(ctrl *gomock.Controller) *MockDraftMR {
+	mock := &MockDraftMR{ctrl: ctrl}
+	mock.recorder = &MockDraftMRMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockDraftMR) EXPECT() *MockDraftMRMockRecorder {
+	return m.recorder
+}
+
+// IsDraft mocks base method.
+func (m *MockDraftMR) IsDraft() bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsDraft")
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// IsDraft indicates an expected call of IsDraft.
+func (mr *MockDraftMRMockRecorder) IsDraft() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsDraft", reflect.TypeOf((*MockDraftMR)(nil).IsDraft))
+}
+
+// MockForkMR is a mock of ForkMR interface.
+type MockForkMR struct {
+	ctrl     *gomock.Controller
+	recorder *MockForkMRMockRecorder
+	isgomock struct{}
+}
+
+// MockForkMRMockRecorder is the mock recorder for MockForkMR.
+type MockForkMRMockRecorder struct {
+	mock *MockForkMR
+}
+
+// NewMockForkMR creates a new mock instance.
+func NewMockForkMR(ctrl *gomock.Controller) *MockForkMR {
+	mock := &MockForkMR{ctrl: ctrl}
+	mock.recorder = &MockForkMRMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockForkMR) EXPECT() *MockForkMRMockRecorder {
+	return m.recorder
+}
+
+// GetHeadSHA mocks base method.
+func (m *MockForkMR) GetHeadSHA() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetHeadSHA")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// GetHeadSHA indicates an expected call of GetHeadSHA.
+func (mr *MockForkMRMockRecorder) GetHeadSHA() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetHeadSHA", reflect.TypeOf((*MockForkMR)(nil).GetHeadSHA))
+}
+
+// IsFromFork mocks base method.
+func (m *MockForkMR) IsFromFork() bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsFromFork")
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// IsFromFork indicates an expected call of IsFromFork.
+func (mr *MockForkMRMockRecorder) IsFromFork() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsFromFork", reflect.TypeOf((*MockForkMR)(nil).IsFromFork))
+}
+
 // MockMRBranches is a mock of MRBranches interface.
 type MockMRBranches struct {
 	ctrl     *gomock.Controller
 	recorder *MockMRBranchesMockRecorder
+	isgomock struct{}
 }
 
 // MockMRBranchesMockRecorder is the mock recorder for MockMRBranches.
@@ -758,6 +935,7 @@ func (mr *MockMRBranchesMockRecorder) GetTargetBranch() *gomock.Call {
 type MockMRAuthor struct {
 	ctrl     *gomock.Controller
 	recorder *MockMRAuthorMockRecorder
+	isgomock struct{}
 }
 
 // MockMRAuthorMockRecorder is the mock recorder for MockMRAuthor.
@@ -795,6 +973,7 @@ func (mr *MockMRAuthorMockRecorder) GetUsername() *gomock.Call {
 type MockCommitStatusOptions struct {
 	ctrl     *gomock.Controller
 	recorder *MockCommitStatusOptionsMockRecorder
+	isgomock struct{}
 }
 
 // MockCommitStatusOptionsMockRecorder is the mock recorder for MockCommitStatusOptions.
@@ -902,6 +1081,7 @@ func (mr *MockCommitStatusOptionsMockRecorder) GetTargetURL() *gomock.Call {
 type MockCommitStatus struct {
 	ctrl     *gomock.Controller
 	recorder *MockCommitStatusMockRecorder
+	isgomock struct{}
 }
 
 // MockCommitStatusMockRecorder is the mock recorder for MockCommitStatus.
@@ -939,6 +1119,7 @@ func (mr *MockCommitStatusMockRecorder) Info() *gomock.Call {
 type MockProjectPipeline struct {
 	ctrl     *gomock.Controller
 	recorder *MockProjectPipelineMockRecorder
+	isgomock struct{}
 }
 
 // MockProjectPipelineMockRecorder is the mock recorder for MockProjectPipeline.
@@ -990,6 +1171,7 @@ func (mr *MockProjectPipelineMockRecorder) GetSource() *gomock.Call {
 type MockProject struct {
 	ctrl     *gomock.Controller
 	recorder *MockProjectMockRecorder
+	isgomock struct{}
 }
 
 // MockProjectMockRecorder is the mock recorder for MockProject.
@@ -1027,6 +1209,7 @@ func (mr *MockProjectMockRecorder) GetPathWithNamespace() *gomock.Call {
 type MockMRCommentEvent struct {
 	ctrl     *gomock.Controller
 	recorder *MockMRCommentEventMockRecorder
+	isgomock struct{}
 }
 
 // MockMRCommentEventMockRecorder is the mock recorder for MockMRCommentEvent.
@@ -1106,6 +1289,7 @@ func (mr *MockMRCommentEventMockRecorder) GetProject() *gomock.Call {
 type MockMRAttributes struct {
 	ctrl     *gomock.Controller
 	recorder *MockMRAttributesMockRecorder
+	isgomock struct{}
 }
 
 // MockMRAttributesMockRecorder is the mock recorder for MockMRAttributes.
@@ -1171,6 +1355,7 @@ func (mr *MockMRAttributesMockRecorder) GetType() *gomock.Call {
 type MockCommit struct {
 	ctrl     *gomock.Controller
 	recorder *MockCommitMockRecorder
+	isgomock struct{}
 }
 
 // MockCommitMockRecorder is the mock recorder for MockCommit.
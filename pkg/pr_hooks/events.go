@@ -0,0 +1,32 @@
+package pr_hooks
+
+import "errors"
+
+// Pull request actions TFBuddy reacts to, common to every provider.
+const (
+	ActionOpened    = "opened"
+	ActionUpdated   = "updated"
+	ActionClosed    = "closed"
+	ActionCommented = "commented"
+)
+
+// ErrUnhandledEvent is returned by providers for webhooks TFBuddy ignores.
+var ErrUnhandledEvent = errors.New("unhandled event")
+
+// PullRequestEvent is the part of a pull request webhook TFBuddy acts on.
+type PullRequestEvent struct {
+	// EventType is the provider's name for the webhook, e.g. the Bitbucket
+	// event key or the Gitea event header.
+	EventType string `json:"eventType"`
+	Action    string `json:"action"`
+	// Repo is the full name of the repository the pull request targets, in
+	// the form the provider's allow list and API client use.
+	Repo         string `json:"repo"`
+	PRID         int    `json:"prID"`
+	SourceBranch string `json:"sourceBranch"`
+	CommitSHA    string `json:"commitSHA"`
+	CommentID    int64  `json:"commentID,omitempty"`
+	Comment      string `json:"comment,omitempty"`
+	// Author is the user who triggered the event.
+	Author string `json:"author,omitempty"`
+}
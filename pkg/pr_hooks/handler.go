@@ -0,0 +1,136 @@
+package pr_hooks
+
+import (
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/nats-io/nats.go"
+	"github.com/rs/zerolog/log"
+	"github.com/sl1pm4t/gongs"
+	"github.com/zapier/tfbuddy/internal/config"
+	"github.com/zapier/tfbuddy/pkg/hooks_stream"
+	"github.com/zapier/tfbuddy/pkg/runstream"
+	"github.com/zapier/tfbuddy/pkg/tfc_api"
+	"github.com/zapier/tfbuddy/pkg/tfc_trigger"
+	"github.com/zapier/tfbuddy/pkg/vcs"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// Provider adapts the webhooks of a VCS provider to pull request events.
+type Provider interface {
+	// Name identifies the provider in trigger options, metrics and stream
+	// subjects, e.g. "bitbucket".
+	Name() string
+	// DisplayName names the provider in logs, e.g. "Bitbucket".
+	DisplayName() string
+	// EventType returns the event type sent in the headers of the webhook,
+	// if any.
+	EventType(r *http.Request) string
+	// Verify checks the signature or credentials of the webhook.
+	Verify(r *http.Request, body []byte) bool
+	// ParseEvent decodes the webhook and returns the unique id of the
+	// delivery, or ErrUnhandledEvent for webhooks TFBuddy ignores.
+	ParseEvent(r *http.Request, body []byte) (*PullRequestEvent, string, error)
+	// IsRepoAllowed matches the repository against the provider's allow list.
+	IsRepoAllowed(cfg config.Config, repo string) bool
+}
+
+type TriggerCreationFunc func(
+	cfg config.Config,
+	vcs vcs.GitClient,
+	tfc tfc_api.ApiClient,
+	runstream runstream.StreamClient,
+	triggerCfg *tfc_trigger.TFCTriggerOptions,
+) tfc_trigger.Trigger
+
+// HooksHandler receives the pull request and comment webhooks of a provider
+// and processes them from the hooks stream.
+type HooksHandler struct {
+	cfg             config.Config
+	provider        Provider
+	tfc             tfc_api.ApiClient
+	vcs             vcs.GitClient
+	runstream       runstream.StreamClient
+	triggerCreation TriggerCreationFunc
+	workspaceStream tfc_trigger.WorkspacePublisher
+	metrics         *metrics
+
+	prStream *gongs.GenericStream[PullRequestEventMsg, *PullRequestEventMsg]
+}
+
+func NewHooksHandler(cfg config.Config, provider Provider, vcs vcs.GitClient, tfc tfc_api.ApiClient, rs runstream.StreamClient, js nats.JetStreamContext, workspaceStream tfc_trigger.WorkspacePublisher) *HooksHandler {
+	prStream := gongs.NewGenericStream[PullRequestEventMsg](js, getJetstreamSubject(provider.Name(), PullRequestEventType), hooks_stream.HooksStreamName)
+
+	h := &HooksHandler{
+		cfg:             cfg,
+		provider:        provider,
+		tfc:             tfc,
+		vcs:             vcs,
+		runstream:       rs,
+		triggerCreation: tfc_trigger.NewTFCTrigger,
+		workspaceStream: workspaceStream,
+		metrics:         newMetrics(provider.Name(), provider.DisplayName()),
+		prStream:        prStream,
+	}
+
+	_, err := prStream.QueueSubscribe(provider.Name()+"_pr_event_worker", h.processPullRequestEvent)
+	if err != nil {
+		log.Error().Err(err).Str("vcs", provider.Name()).Msg("could not subscribe to pull request stream")
+	}
+
+	return h
+}
+
+// Handler verifies webhooks and publishes pull request and comment events to
+// the hooks stream.
+func (h *HooksHandler) Handler(c echo.Context) error {
+	h.metrics.received.Inc()
+	ctx, span := otel.Tracer("hooks").Start(c.Request().Context(), h.provider.DisplayName()+" - HooksHandler")
+	defer span.End()
+
+	name := h.provider.DisplayName()
+	eventType := h.provider.EventType(c.Request())
+	body, err := io.ReadAll(c.Request().Body)
+	if err != nil {
+		h.metrics.failed.WithLabelValues(eventType, "", "invalid-body").Inc()
+		return c.String(http.StatusBadRequest, "could not read body")
+	}
+	if !h.provider.Verify(c.Request(), body) {
+		h.metrics.failed.WithLabelValues(eventType, "", "invalid-signature").Inc()
+		return c.String(http.StatusUnauthorized, "Unauthorized")
+	}
+
+	event, deliveryID, err := h.provider.ParseEvent(c.Request(), body)
+	if errors.Is(err, ErrUnhandledEvent) {
+		log.Debug().Str("eventType", eventType).Msgf("ignoring %s event", name)
+		h.metrics.ignored.WithLabelValues(eventType, "", "unhandled-event-type").Inc()
+		return c.String(http.StatusOK, "OK")
+	}
+	if err != nil {
+		log.Error().Err(err).Str("eventType", eventType).Msgf("could not decode %s event", name)
+		h.metrics.failed.WithLabelValues(eventType, "", "invalid-payload").Inc()
+		return c.String(http.StatusBadRequest, "invalid payload")
+	}
+	span.SetAttributes(
+		attribute.String("repository", event.Repo),
+		attribute.String("eventType", event.EventType),
+		attribute.Int("pullRequestID", event.PRID),
+	)
+	log.Info().Str("repo", event.Repo).Str("eventType", event.EventType).Str("action", event.Action).Int("PR", event.PRID).Msgf("processing %s event", name)
+
+	_, err = h.prStream.Publish(ctx, &PullRequestEventMsg{
+		Payload:     event,
+		VcsProvider: h.provider.Name(),
+		DeliveryID:  deliveryID,
+	})
+	if err != nil {
+		log.Error().Err(err).Str("repo", event.Repo).Int("PR", event.PRID).Msgf("could not publish %s event to stream", name)
+		h.metrics.failed.WithLabelValues(event.EventType, event.Repo, "publish-error").Inc()
+		return c.String(http.StatusInternalServerError, "could not publish event")
+	}
+	h.metrics.success.WithLabelValues(event.EventType, event.Repo).Inc()
+	return c.String(http.StatusOK, "OK")
+}
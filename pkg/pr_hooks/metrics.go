@@ -0,0 +1,66 @@
+package pr_hooks
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var commonLabels = []string{
+	"eventType",
+	"repository",
+}
+
+// metrics counts the webhooks of one provider as
+// `tfbuddy_<provider>_webhook_<outcome>`.
+type metrics struct {
+	received prometheus.Counter
+	success  *prometheus.CounterVec
+	failed   *prometheus.CounterVec
+	ignored  *prometheus.CounterVec
+}
+
+func newMetrics(provider, displayName string) *metrics {
+	return &metrics{
+		received: register(prometheus.NewCounter(prometheus.CounterOpts{
+			Name: fmt.Sprintf("tfbuddy_%s_webhook_received", provider),
+			Help: fmt.Sprintf("Count of all %s webhooks received", displayName),
+		})),
+		success: register(prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: fmt.Sprintf("tfbuddy_%s_webhook_success", provider),
+				Help: fmt.Sprintf("Count of all %s WebHook that were published to stream", displayName),
+			},
+			commonLabels,
+		)),
+		failed: register(prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: fmt.Sprintf("tfbuddy_%s_webhook_failed", provider),
+				Help: fmt.Sprintf("Count of all %s WebHook that could not be verified or published to stream", displayName),
+			},
+			append(commonLabels, "reason"),
+		)),
+		ignored: register(prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: fmt.Sprintf("tfbuddy_%s_webhook_ignored", provider),
+				Help: fmt.Sprintf("Count of all %s WebHook that were ignored", displayName),
+			},
+			append(commonLabels, "reason"),
+		)),
+	}
+}
+
+// register registers the collector, or returns the one already registered
+// under its name when a handler for the provider was created before.
+func register[C prometheus.Collector](c C) C {
+	err := prometheus.DefaultRegisterer.Register(c)
+	var are prometheus.AlreadyRegisteredError
+	if errors.As(err, &are) {
+		return are.ExistingCollector.(C)
+	}
+	if err != nil {
+		panic(err)
+	}
+	return c
+}
@@ -0,0 +1,151 @@
+package pr_hooks
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/hashicorp/go-tfe"
+	"github.com/rs/zerolog/log"
+	"github.com/zapier/tfbuddy/internal/config"
+	"github.com/zapier/tfbuddy/pkg/comment_formatter"
+	"github.com/zapier/tfbuddy/pkg/runstream"
+	"github.com/zapier/tfbuddy/pkg/tfc_api"
+	"github.com/zapier/tfbuddy/pkg/vcs"
+	"go.opentelemetry.io/otel"
+)
+
+// RunReporter reports TFC runs on the pull requests of one provider.
+type RunReporter interface {
+	// SetStatus reports the state of a run action as the status
+	// `TFC/<action>/<workspace>` of the pull request.
+	SetStatus(ctx context.Context, state StatusState, action string, rmd runstream.RunMetadata)
+	// PostRunComment publishes the run status in the pull request. summary
+	// replaces the comment created when the run was triggered and is empty
+	// when it does not change, details holds the plan or apply output and
+	// resolve is set once nothing is left to do for the run.
+	PostRunComment(ctx context.Context, run *tfe.Run, rmd runstream.RunMetadata, summary, details string, resolve bool)
+}
+
+type RunEventsWorker struct {
+	cfg          config.Config
+	client       vcs.GitClient
+	reporter     RunReporter
+	rs           runstream.StreamClient
+	tfc          tfc_api.ApiClient
+	eventQCloser func()
+}
+
+// NewRunEventsWorker reports the runs triggered from the provider named
+// provider on its pull requests.
+func NewRunEventsWorker(cfg config.Config, provider string, client vcs.GitClient, reporter RunReporter, rs runstream.StreamClient, tfc tfc_api.ApiClient) *RunEventsWorker {
+	w := &RunEventsWorker{
+		cfg:      cfg,
+		client:   client,
+		reporter: reporter,
+		rs:       rs,
+		tfc:      tfc,
+	}
+
+	// subscribe to TFRunEvents (TFC Notifications)
+	var err error
+	w.eventQCloser, err = rs.SubscribeTFRunEvents(provider, w.eventStreamCallback)
+	if err != nil {
+		log.Fatal().Err(err).Msg("could not create RunStream subscription")
+	}
+
+	return w
+}
+
+func (w *RunEventsWorker) Close() {
+	w.eventQCloser()
+}
+
+// eventStreamCallback processes TFC run notifications via the NATS stream
+func (w *RunEventsWorker) eventStreamCallback(re runstream.RunEvent) bool {
+	ctx, span := otel.Tracer("TFC").Start(re.GetContext(), "eventStreamCallback")
+	defer span.End()
+
+	log.Debug().Interface("TFRunEvent", re).Msg("RunEventsWorker.eventStreamCallback()")
+
+	run, err := w.tfc.GetRun(ctx, re.GetRunID())
+	if err != nil {
+		span.RecordError(err)
+		log.Error().Err(err).Str("runID", re.GetRunID()).Msg("could not get run")
+		return false
+	}
+	run.Status = tfe.RunStatus(re.GetNewStatus())
+
+	w.postRunStatusComment(ctx, run, re.GetMetadata())
+	w.updateStatusForRun(ctx, run, re.GetMetadata())
+	return true
+}
+
+func (w *RunEventsWorker) postRunStatusComment(ctx context.Context, run *tfe.Run, rmd runstream.RunMetadata) {
+	ctx, span := otel.Tracer("TFC").Start(ctx, "postRunStatusComment")
+	defer span.End()
+
+	commentBody, topLevelNoteBody, resolveDiscussion := comment_formatter.FormatRunStatusCommentBody(w.cfg, w.tfc, run, rmd)
+
+	if topLevelNoteBody != "" {
+		if run.Status == tfe.RunErrored || run.Status == tfe.RunCanceled || run.Status == tfe.RunDiscarded || run.Status == tfe.RunPlannedAndFinished {
+			// the discussion is the comment of the run, or the thread holding it
+			rootID, _ := strconv.Atoi(rmd.GetDiscussionID())
+			oldUrls, err := w.client.GetOldRunUrls(ctx, rmd.GetMRInternalID(), rmd.GetMRProjectNameWithNamespace(), rootID, run.Workspace.Name, rmd.GetAction())
+			if err != nil {
+				log.Error().Str("repo", rmd.GetMRProjectNameWithNamespace()).Int("prID", rmd.GetMRInternalID()).Err(err).Msg("could not retrieve old run urls")
+			}
+			if oldUrls != "" {
+				topLevelNoteBody = fmt.Sprintf("%s\n\n%s", oldUrls, topLevelNoteBody)
+			}
+		}
+	}
+	w.reporter.PostRunComment(ctx, run, rmd, topLevelNoteBody, commentBody, resolveDiscussion)
+
+	if len(run.TargetAddrs) > 0 {
+		return
+	}
+	if run.Status == tfe.RunApplied || (run.Status == tfe.RunPlannedAndFinished && rmd.GetAction() == runstream.ApplyAction) {
+		w.mergePRIfPossible(ctx, rmd)
+	}
+}
+
+func (w *RunEventsWorker) mergePRIfPossible(ctx context.Context, rmd runstream.RunMetadata) {
+	if !rmd.GetAutoMerge() {
+		return
+	}
+	if err := w.client.MergeMR(ctx, rmd.GetMRInternalID(), rmd.GetMRProjectNameWithNamespace()); err != nil {
+		log.Error().Str("repo", rmd.GetMRProjectNameWithNamespace()).Int("prID", rmd.GetMRInternalID()).Err(err).Msg("could not merge pull request")
+	}
+}
+
+// UpdateRootComment posts the run status for providers without comment
+// threads: the comment created when the run was triggered is edited to hold
+// the summary followed by the details, or a new comment is posted when there
+// is none or it can no longer be edited.
+func UpdateRootComment(ctx context.Context, client vcs.GitClient, rmd runstream.RunMetadata, summary, details string) {
+	if summary == "" {
+		return
+	}
+	body := summary
+	if details != "" {
+		body += fmt.Sprintf("\n%s", details)
+	}
+	if rmd.GetRootNoteID() != 0 {
+		_, err := client.UpdateMergeRequestDiscussionNote(
+			ctx,
+			rmd.GetMRInternalID(),
+			int(rmd.GetRootNoteID()),
+			rmd.GetMRProjectNameWithNamespace(),
+			rmd.GetDiscussionID(),
+			body,
+		)
+		if err == nil {
+			return
+		}
+		log.Error().Str("repo", rmd.GetMRProjectNameWithNamespace()).Int("prID", rmd.GetMRInternalID()).Int64("commentID", rmd.GetRootNoteID()).Err(err).Msg("could not update PR comment, posting a new one")
+	}
+	if err := client.CreateMergeRequestComment(ctx, rmd.GetMRInternalID(), rmd.GetMRProjectNameWithNamespace(), body); err != nil {
+		log.Error().Str("repo", rmd.GetMRProjectNameWithNamespace()).Int("prID", rmd.GetMRInternalID()).Err(err).Msg("could not post PR comment")
+	}
+}
@@ -0,0 +1,81 @@
+package pr_hooks
+
+import (
+	"context"
+
+	"github.com/hashicorp/go-tfe"
+	"github.com/rs/zerolog/log"
+	"github.com/zapier/tfbuddy/pkg/runstream"
+	"go.opentelemetry.io/otel"
+)
+
+// StatusState is the state of a run action, mapped by each provider to its
+// own status states.
+type StatusState int
+
+const (
+	StatusPending StatusState = iota
+	StatusFailed
+	StatusSucceeded
+)
+
+// updateStatusForRun reports every run as a status named
+// `TFC/<action>/<workspace>` on the pull request.
+func (w *RunEventsWorker) updateStatusForRun(ctx context.Context, run *tfe.Run, rmd runstream.RunMetadata) {
+	ctx, span := otel.Tracer("TFC").Start(ctx, "updateStatusForRun")
+	defer span.End()
+
+	switch run.Status {
+	// https://www.terraform.io/cloud-docs/api-docs/run#run-states
+	case tfe.RunPending:
+		if rmd.GetAction() == runstream.PlanAction {
+			w.reporter.SetStatus(ctx, StatusPending, "plan", rmd)
+			w.reporter.SetStatus(ctx, StatusFailed, "apply", rmd)
+		} else {
+			w.reporter.SetStatus(ctx, StatusPending, "apply", rmd)
+		}
+
+	case tfe.RunApplyQueued, tfe.RunApplying:
+		w.reporter.SetStatus(ctx, StatusPending, "apply", rmd)
+
+	case tfe.RunApplied:
+		if len(run.TargetAddrs) > 0 {
+			w.reporter.SetStatus(ctx, StatusPending, "apply", rmd)
+			return
+		}
+		w.reporter.SetStatus(ctx, StatusSucceeded, "apply", rmd)
+
+	case tfe.RunCanceled, tfe.RunErrored:
+		w.reporter.SetStatus(ctx, StatusFailed, rmd.GetAction(), rmd)
+
+	case tfe.RunDiscarded:
+		w.reporter.SetStatus(ctx, StatusFailed, "plan", rmd)
+		w.reporter.SetStatus(ctx, StatusFailed, "apply", rmd)
+
+	case tfe.RunPlanning:
+		w.reporter.SetStatus(ctx, StatusPending, rmd.GetAction(), rmd)
+
+	case tfe.RunPlanned:
+		// this status is for Apply runs (as opposed to `RunPlannedAndFinished` below, so don't update the status.
+		return
+
+	case tfe.RunPlannedAndFinished:
+		w.reporter.SetStatus(ctx, StatusSucceeded, rmd.GetAction(), rmd)
+		if run.HasChanges {
+			w.reporter.SetStatus(ctx, StatusPending, "apply", rmd)
+		}
+
+	case tfe.RunPolicySoftFailed:
+		if w.cfg.FailCIOnSentinelSoftFail && rmd.GetAction() == runstream.PlanAction {
+			w.reporter.SetStatus(ctx, StatusFailed, "plan", rmd)
+		} else {
+			w.reporter.SetStatus(ctx, StatusSucceeded, rmd.GetAction(), rmd)
+		}
+
+	case tfe.RunPolicyChecked:
+		// no op
+
+	default:
+		log.Debug().Str("repo", rmd.GetMRProjectNameWithNamespace()).Int("prID", rmd.GetMRInternalID()).Str("status", string(run.Status)).Msg("ignoring run status")
+	}
+}
@@ -0,0 +1,68 @@
+package pr_hooks
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/rs/zerolog/log"
+	"github.com/zapier/tfbuddy/pkg/hooks_stream"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const PullRequestEventType = "PullRequestEvent"
+
+// getJetstreamSubject returns the subject events of the provider are
+// published on, e.g. `hooks.bitbucket.PullRequestEvent`.
+func getJetstreamSubject(provider, evtType string) string {
+	return fmt.Sprintf("%s.%s.%s", hooks_stream.HooksStreamName, provider, evtType)
+}
+
+type PullRequestEventMsg struct {
+	Payload *PullRequestEvent `json:"payload"`
+	// VcsProvider is the Name of the provider that sent the webhook.
+	VcsProvider string `json:"vcsProvider"`
+	// DeliveryID is the unique id the provider sends with each delivery. Used
+	// as the stream dedup key so redelivered hooks are dropped.
+	DeliveryID string                 `json:"deliveryID"`
+	Carrier    propagation.MapCarrier `json:"Carrier"`
+	Context    context.Context
+}
+
+func (e *PullRequestEventMsg) GetId(ctx context.Context) string {
+	if e.DeliveryID != "" {
+		return e.DeliveryID
+	}
+	p := e.Payload
+	return fmt.Sprintf("%s/%d/%s/%s/%d", p.Repo, p.PRID, p.EventType, p.CommitSHA, p.CommentID)
+}
+
+func (e *PullRequestEventMsg) DecodeEventData(b []byte) error {
+	log.Trace().RawJSON("event_data", b).Msg("decoding pull request event")
+	err := json.Unmarshal(b, e)
+	if err != nil {
+		log.Error().Err(err).Msg("could not decode pull request event")
+		return err
+	}
+	e.Context = otel.GetTextMapPropagator().Extract(context.Background(), e.Carrier)
+	return nil
+}
+
+func (e *PullRequestEventMsg) EncodeEventData(ctx context.Context) []byte {
+	ctx, span := otel.Tracer("hooks").Start(ctx, "encode_event_data",
+		trace.WithAttributes(
+			attribute.String("event_type", PullRequestEventType),
+			attribute.String("vcs", e.VcsProvider),
+		))
+	defer span.End()
+	e.Carrier = make(map[string]string)
+	otel.GetTextMapPropagator().Inject(ctx, e.Carrier)
+	b, err := json.Marshal(e)
+	if err != nil {
+		log.Error().Err(err).Msg("could not encode pull request event")
+	}
+	return b
+}
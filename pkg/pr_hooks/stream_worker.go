@@ -0,0 +1,177 @@
+package pr_hooks
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/rs/zerolog/log"
+	"github.com/zapier/tfbuddy/pkg/comment_actions"
+	"github.com/zapier/tfbuddy/pkg/tfc_trigger"
+	"github.com/zapier/tfbuddy/pkg/utils"
+	"github.com/zapier/tfbuddy/pkg/vcs"
+	"go.opentelemetry.io/otel"
+)
+
+// PullRequest is what comment commands need to know about a pull request.
+type PullRequest interface {
+	vcs.DetailedMR
+	vcs.MRApproved
+	vcs.ForkMR
+}
+
+func (h *HooksHandler) processPullRequestEvent(msg *PullRequestEventMsg) error {
+	ctx, span := otel.Tracer("hooks").Start(msg.Context, "processPullRequestEvent")
+	defer span.End()
+
+	var prErr error
+	defer func() {
+		if r := recover(); r != nil {
+			log.Error().Msgf("Unrecoverable error in pull request event processing %v", r)
+			prErr = nil
+		}
+	}()
+	prErr = h.processPullRequest(ctx, msg)
+	return utils.EmitPermanentError(prErr, func(err error) {
+		log.Error().Msgf("got a permanent error attempting to process pull request event: %s", err.Error())
+	})
+}
+
+// processPullRequest plans the touched workspaces when a pull request is
+// opened or gets new commits, releases its locks when it is merged or
+// closed, and runs `tfc` comment commands.
+func (h *HooksHandler) processPullRequest(ctx context.Context, msg *PullRequestEventMsg) error {
+	ctx, span := otel.Tracer("hooks").Start(ctx, "processPullRequest")
+	defer span.End()
+
+	if msg == nil || msg.Payload == nil {
+		return errors.New("msg is nil")
+	}
+	event := msg.Payload
+
+	log.Debug().Str("vcs", h.provider.Name()).Str("repo", event.Repo).Str("action", event.Action).Int("PR", event.PRID).Msg("processPullRequestEvent")
+	if !h.provider.IsRepoAllowed(h.cfg, event.Repo) {
+		h.metrics.ignored.WithLabelValues(event.EventType, event.Repo, "repo-not-authorized").Inc()
+		return nil
+	}
+
+	if event.Action == ActionCommented {
+		return h.processComment(ctx, msg)
+	}
+
+	cfg, err := tfc_trigger.NewTFCTriggerConfig(&tfc_trigger.TFCTriggerOptions{
+		Action:                   tfc_trigger.PlanAction,
+		Branch:                   event.SourceBranch,
+		CommitSHA:                event.CommitSHA,
+		ProjectNameWithNamespace: event.Repo,
+		MergeRequestIID:          event.PRID,
+		TriggerSource:            tfc_trigger.MergeRequestEventTrigger,
+		VcsProvider:              h.provider.Name(),
+		DeliveryID:               msg.DeliveryID,
+	})
+	if err != nil {
+		log.Error().Err(err).Msg("could not create TFCTriggerConfig")
+		return err
+	}
+
+	trigger := h.triggerCreation(h.cfg, h.vcs, h.tfc, h.runstream, cfg)
+	if h.workspaceStream != nil {
+		trigger.SetWorkspaceStream(h.workspaceStream)
+	}
+
+	switch event.Action {
+	case ActionOpened, ActionUpdated:
+		log.Debug().Str("repo", event.Repo).Int("PR", event.PRID).Msg("triggering TFC events for pull request")
+		_, err := trigger.TriggerTFCEvents(ctx)
+		return err
+	case ActionClosed:
+		log.Debug().Str("repo", event.Repo).Int("PR", event.PRID).Str("eventType", event.EventType).Msg("cleaning up after pull request")
+		return trigger.TriggerCleanupEvent(ctx)
+	}
+	return nil
+}
+
+func (h *HooksHandler) processComment(ctx context.Context, msg *PullRequestEventMsg) error {
+	ctx, span := otel.Tracer("hooks").Start(ctx, "processComment")
+	defer span.End()
+
+	event := msg.Payload
+	opts, err := comment_actions.ParseCommentCommand(event.Comment)
+	if err != nil {
+		if err == comment_actions.ErrOtherTFTool {
+			h.postPullRequestComment(ctx, event, "Use 'tfc' to interact with TFBuddy")
+		}
+		if err == comment_actions.ErrNotTFCCommand || err == comment_actions.ErrOtherTFTool {
+			h.metrics.ignored.WithLabelValues(event.EventType, event.Repo, "not-tfc-command").Inc()
+			return nil
+		}
+		return err
+	}
+
+	mr, err := h.vcs.GetMergeRequest(ctx, event.PRID, event.Repo)
+	if err != nil {
+		log.Error().Err(err).Msgf("could not process %s comment event", h.provider.DisplayName())
+		return err
+	}
+	pr, ok := mr.(PullRequest)
+	if !ok {
+		return utils.CreatePermanentError(fmt.Errorf("%s pull request %T does not have approval and head commit details", h.provider.DisplayName(), mr))
+	}
+
+	opts.TriggerOpts.Branch = pr.GetSourceBranch()
+	opts.TriggerOpts.CommitSHA = pr.GetHeadSHA()
+	opts.TriggerOpts.ProjectNameWithNamespace = event.Repo
+	opts.TriggerOpts.MergeRequestIID = event.PRID
+	opts.TriggerOpts.TriggerSource = tfc_trigger.CommentTrigger
+	opts.TriggerOpts.Commenter = event.Author
+	opts.TriggerOpts.VcsProvider = h.provider.Name()
+	opts.TriggerOpts.DeliveryID = msg.DeliveryID
+
+	cfg, err := tfc_trigger.NewTFCTriggerConfig(opts.TriggerOpts)
+	if err != nil {
+		log.Error().Err(err).Msg("could not create TFCTriggerConfig")
+		return err
+	}
+
+	trigger := h.triggerCreation(h.cfg, h.vcs, h.tfc, h.runstream, cfg)
+	if h.workspaceStream != nil {
+		trigger.SetWorkspaceStream(h.workspaceStream)
+	}
+
+	switch opts.Args.Command {
+	case "apply":
+		log.Info().Msg("Got TFC apply command")
+		if !pr.IsApproved() {
+			h.postPullRequestComment(ctx, event, ":no_entry: Apply failed. Pull Request requires approval.")
+			return nil
+		}
+		if pr.HasConflicts() {
+			h.postPullRequestComment(ctx, event, ":no_entry: Apply failed. Pull Request has conflicts that need to be resolved.")
+			return nil
+		}
+	case "lock":
+		log.Info().Msg("Got TFC lock command")
+	case "override-policy":
+		log.Info().Msg("Got TFC override-policy command")
+	case "plan":
+		log.Info().Msg("Got TFC plan command")
+	case "unlock":
+		log.Info().Msg("Got TFC unlock command")
+	default:
+		return fmt.Errorf("could not parse command")
+	}
+	executedWorkspaces, tfError := trigger.TriggerTFCEvents(ctx)
+	if tfError == nil && executedWorkspaces != nil && len(executedWorkspaces.Errored) > 0 {
+		for _, failedWS := range executedWorkspaces.Errored {
+			h.postPullRequestComment(ctx, event, fmt.Sprintf(":no_entry: %s could not be run because: %s", failedWS.Name, failedWS.Error))
+		}
+		return nil
+	}
+	return tfError
+}
+
+func (h *HooksHandler) postPullRequestComment(ctx context.Context, event *PullRequestEvent, body string) {
+	if err := h.vcs.CreateMergeRequestComment(ctx, event.PRID, event.Repo, body); err != nil {
+		log.Error().Err(err).Str("repo", event.Repo).Int("PR", event.PRID).Msg("could not post pull request comment")
+	}
+}
@@ -0,0 +1,161 @@
+package pr_hooks
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/zapier/tfbuddy/internal/config"
+	"github.com/zapier/tfbuddy/pkg/mocks"
+	"github.com/zapier/tfbuddy/pkg/runstream"
+	"github.com/zapier/tfbuddy/pkg/tfc_api"
+	"github.com/zapier/tfbuddy/pkg/tfc_trigger"
+	"github.com/zapier/tfbuddy/pkg/vcs"
+	"go.uber.org/mock/gomock"
+)
+
+type testProvider struct{}
+
+func (p *testProvider) Name() string                                 { return "test" }
+func (p *testProvider) DisplayName() string                          { return "Test" }
+func (p *testProvider) EventType(r *http.Request) string             { return "" }
+func (p *testProvider) Verify(r *http.Request, body []byte) bool     { return true }
+func (p *testProvider) IsRepoAllowed(_ config.Config, r string) bool { return r == "zapier/tfbuddy" }
+func (p *testProvider) ParseEvent(r *http.Request, body []byte) (*PullRequestEvent, string, error) {
+	return nil, "", ErrUnhandledEvent
+}
+
+type testPullRequest struct {
+	vcs.DetailedMR
+	approved bool
+}
+
+func (pr *testPullRequest) GetSourceBranch() string { return "feature" }
+func (pr *testPullRequest) HasConflicts() bool      { return false }
+func (pr *testPullRequest) IsApproved() bool        { return pr.approved }
+func (pr *testPullRequest) IsFromFork() bool        { return false }
+func (pr *testPullRequest) GetHeadSHA() string      { return "abc123" }
+
+func newTestHandler(gitClient vcs.GitClient, trigger tfc_trigger.Trigger, gotOpts **tfc_trigger.TFCTriggerOptions) *HooksHandler {
+	return &HooksHandler{
+		provider: &testProvider{},
+		vcs:      gitClient,
+		metrics:  newMetrics("test", "Test"),
+		triggerCreation: func(_ config.Config, _ vcs.GitClient, _ tfc_api.ApiClient, _ runstream.StreamClient, opts *tfc_trigger.TFCTriggerOptions) tfc_trigger.Trigger {
+			*gotOpts = opts
+			return trigger
+		},
+	}
+}
+
+func TestProcessPullRequest(t *testing.T) {
+	tests := []struct {
+		name        string
+		action      string
+		repo        string
+		wantTrigger bool
+		wantCleanup bool
+	}{
+		{name: "opened", action: ActionOpened, repo: "zapier/tfbuddy", wantTrigger: true},
+		{name: "updated", action: ActionUpdated, repo: "zapier/tfbuddy", wantTrigger: true},
+		{name: "closed", action: ActionClosed, repo: "zapier/tfbuddy", wantCleanup: true},
+		{name: "repo not allowed", action: ActionOpened, repo: "other/repo"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			mockTrigger := mocks.NewMockTrigger(mockCtrl)
+			if tt.wantTrigger {
+				mockTrigger.EXPECT().TriggerTFCEvents(gomock.Any()).Return(&tfc_trigger.TriggeredTFCWorkspaces{}, nil)
+			}
+			if tt.wantCleanup {
+				mockTrigger.EXPECT().TriggerCleanupEvent(gomock.Any()).Return(nil)
+			}
+
+			var gotOpts *tfc_trigger.TFCTriggerOptions
+			h := newTestHandler(nil, mockTrigger, &gotOpts)
+
+			err := h.processPullRequest(context.Background(), &PullRequestEventMsg{
+				DeliveryID: "delivery-1",
+				Payload: &PullRequestEvent{
+					Action:       tt.action,
+					Repo:         tt.repo,
+					PRID:         7,
+					SourceBranch: "feature",
+					CommitSHA:    "abc123",
+				},
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tt.wantTrigger || tt.wantCleanup {
+				if gotOpts == nil {
+					t.Fatal("expected a trigger to be created")
+				}
+				if gotOpts.TriggerSource != tfc_trigger.MergeRequestEventTrigger || gotOpts.VcsProvider != "test" ||
+					gotOpts.DeliveryID != "delivery-1" || gotOpts.CommitSHA != "abc123" || gotOpts.MergeRequestIID != 7 {
+					t.Errorf("unexpected trigger options %+v", gotOpts)
+				}
+			} else if gotOpts != nil {
+				t.Error("expected no trigger to be created")
+			}
+		})
+	}
+}
+
+func TestProcessComment(t *testing.T) {
+	tests := []struct {
+		name        string
+		comment     string
+		approved    bool
+		action      tfc_trigger.TriggerAction
+		commentSHA  string
+		wantComment string
+	}{
+		{name: "plan", comment: "tfc plan", action: tfc_trigger.PlanAction},
+		{name: "plan pinned to a commit", comment: "tfc plan abc1234", action: tfc_trigger.PlanAction, commentSHA: "abc1234"},
+		{name: "override policy", comment: "tfc override-policy -w prod", action: tfc_trigger.OverridePolicyAction},
+		{name: "approved apply", comment: "tfc apply", approved: true, action: tfc_trigger.ApplyAction},
+		{name: "apply without approval", comment: "tfc apply", wantComment: ":no_entry: Apply failed. Pull Request requires approval."},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			mockGit := mocks.NewMockGitClient(mockCtrl)
+			mockGit.EXPECT().GetMergeRequest(gomock.Any(), 7, "zapier/tfbuddy").Return(&testPullRequest{approved: tt.approved}, nil)
+			mockTrigger := mocks.NewMockTrigger(mockCtrl)
+			if tt.wantComment != "" {
+				mockGit.EXPECT().CreateMergeRequestComment(gomock.Any(), 7, "zapier/tfbuddy", tt.wantComment).Return(nil)
+			} else {
+				mockTrigger.EXPECT().TriggerTFCEvents(gomock.Any()).Return(&tfc_trigger.TriggeredTFCWorkspaces{}, nil)
+			}
+
+			var gotOpts *tfc_trigger.TFCTriggerOptions
+			h := newTestHandler(mockGit, mockTrigger, &gotOpts)
+
+			err := h.processPullRequest(context.Background(), &PullRequestEventMsg{
+				Payload: &PullRequestEvent{
+					Action:  ActionCommented,
+					Repo:    "zapier/tfbuddy",
+					PRID:    7,
+					Comment: tt.comment,
+					Author:  "alice",
+				},
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tt.wantComment != "" {
+				return
+			}
+			if gotOpts.Action != tt.action || gotOpts.Commenter != "alice" || gotOpts.TriggerSource != tfc_trigger.CommentTrigger ||
+				gotOpts.CommitSHA != "abc123" || gotOpts.CommentSHA != tt.commentSHA || gotOpts.Branch != "feature" || gotOpts.VcsProvider != "test" {
+				t.Errorf("unexpected trigger options %+v", gotOpts)
+			}
+		})
+	}
+}
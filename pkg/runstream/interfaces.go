@@ -13,6 +13,7 @@ type StreamClient interface {
 	AddRunMeta(rmd RunMetadata) error
 	GetRunMeta(runID string) (RunMetadata, error)
 	NewTFRunPollingTask(meta RunMetadata, delay time.Duration) RunPollingTask
+	StopTFRunPollingTask(runID string) error
 	SubscribeTFRunPollingTasks(cb func(task RunPollingTask) bool) (closer func(), err error)
 	SubscribeTFRunEvents(queue string, cb func(run RunEvent) bool) (closer func(), err error)
 }
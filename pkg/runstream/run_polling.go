@@ -3,6 +3,7 @@ package runstream
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"time"
 
@@ -57,6 +58,16 @@ func (s *Stream) NewTFRunPollingTask(meta RunMetadata, delay time.Duration) RunP
 	}
 }
 
+// StopTFRunPollingTask stops polling the run, e.g. because it was canceled.
+// A poll already in progress fails to reschedule itself.
+func (s *Stream) StopTFRunPollingTask(runID string) error {
+	err := s.pollingKV.Delete(runID)
+	if errors.Is(err, nats.ErrKeyNotFound) {
+		return nil
+	}
+	return err
+}
+
 func (task *TFRunPollingTask) Schedule(ctx context.Context) error {
 	return task.stream.addTFRunPollingTask(ctx, task)
 }
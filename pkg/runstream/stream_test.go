@@ -1,8 +1,11 @@
 package runstream
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/nats-io/nats-server/v2/server"
 	natstest "github.com/nats-io/nats-server/v2/test"
@@ -96,6 +99,39 @@ func Test_configureTFRunEventsStream(t *testing.T) {
 	}
 }
 
+func TestStopTFRunPollingTask(t *testing.T) {
+	s := RunServerOnPort(TEST_PORT)
+	defer s.Shutdown()
+
+	url := fmt.Sprintf("nats://127.0.0.1:%d", TEST_PORT)
+	nc := testConnect(t, url)
+	defer nc.Close()
+
+	js := testGetJetstreamContext(t, nc)
+	pollingKV, err := configureRunPollingKVStore(js)
+	if err != nil {
+		t.Fatal(err)
+	}
+	stream := &Stream{js: js, pollingKV: pollingKV}
+
+	task := stream.NewTFRunPollingTask(&TFRunMetadata{RunID: "run-1"}, time.Second)
+	if err := task.Schedule(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if err := stream.StopTFRunPollingTask("run-1"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := pollingKV.Get("run-1"); !errors.Is(err, nats.ErrKeyNotFound) {
+		t.Errorf("expected the polling task to be deleted, got %v", err)
+	}
+	if err := task.Reschedule(context.Background()); err == nil {
+		t.Error("expected a stopped polling task not to be rescheduled")
+	}
+	if err := stream.StopTFRunPollingTask("run-1"); err != nil {
+		t.Errorf("stopping a stopped polling task should succeed, got %v", err)
+	}
+}
+
 func testConnect(t *testing.T, url string) *nats.Conn {
 	nc, err := nats.Connect(url)
 	if err != nil {
@@ -0,0 +1,63 @@
+package tfc_api
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/hashicorp/go-tfe"
+	"github.com/zapier/tfbuddy/internal/config"
+)
+
+// Address returns the Terraform Cloud or Enterprise address TFBuddy talks to,
+// without a trailing slash. It defaults to HCP Terraform.
+func Address(cfg config.Config) string {
+	if cfg.TFCAddress == "" {
+		return tfe.DefaultAddress
+	}
+	return strings.TrimRight(cfg.TFCAddress, "/")
+}
+
+// RunURL returns the link to run runID of workspace in the web UI.
+func RunURL(cfg config.Config, org, workspace, runID string) string {
+	return fmt.Sprintf("%s/app/%s/workspaces/%s/runs/%s", Address(cfg), org, workspace, runID)
+}
+
+// tlsConfig returns the TLS configuration trusting the extra CAs of the
+// configured CA file, or nil when the system roots apply.
+func tlsConfig(cfg config.Config) (*tls.Config, error) {
+	if cfg.TFCCAFile == "" {
+		return nil, nil
+	}
+	b, err := os.ReadFile(cfg.TFCCAFile)
+	if err != nil {
+		return nil, fmt.Errorf("could not read TFC CA file. %w", err)
+	}
+	pool, err := x509.SystemCertPool()
+	if err != nil {
+		pool = x509.NewCertPool()
+	}
+	if !pool.AppendCertsFromPEM(b) {
+		return nil, fmt.Errorf("no certificates found in TFC CA file %s", cfg.TFCCAFile)
+	}
+	return &tls.Config{RootCAs: pool, MinVersion: tls.VersionTLS12}, nil
+}
+
+// NewTFEConfig returns the go-tfe configuration for token, pointing at the
+// configured address and trusting the configured CAs.
+func NewTFEConfig(cfg config.Config, token string) (*tfe.Config, error) {
+	tc, err := tlsConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+	tfeConfig := &tfe.Config{
+		Address: Address(cfg),
+		Token:   token,
+	}
+	if tc != nil {
+		tfeConfig.HTTPClient = newHTTPClient(nil, tc)
+	}
+	return tfeConfig, nil
+}
@@ -0,0 +1,68 @@
+package tfc_api
+
+import (
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/zapier/tfbuddy/internal/config"
+)
+
+func TestRunURL(t *testing.T) {
+	tests := []struct {
+		name    string
+		address string
+		want    string
+	}{
+		{name: "default", want: "https://app.terraform.io/app/zapier/workspaces/aws/runs/run-1"},
+		{name: "eu region", address: "https://app.eu.terraform.io", want: "https://app.eu.terraform.io/app/zapier/workspaces/aws/runs/run-1"},
+		{name: "enterprise with trailing slash", address: "https://tfe.example.com/", want: "https://tfe.example.com/app/zapier/workspaces/aws/runs/run-1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := RunURL(config.Config{TFCAddress: tt.address}, "zapier", "aws", "run-1"); got != tt.want {
+				t.Errorf("RunURL() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewTFEConfig_CAFile(t *testing.T) {
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	ca := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: srv.Certificate().Raw})
+	if err := os.WriteFile(caFile, ca, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	tfeConfig, err := NewTFEConfig(config.Config{TFCAddress: srv.URL, TFCCAFile: caFile}, "token")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tfeConfig.Address != srv.URL {
+		t.Errorf("Address = %s, want %s", tfeConfig.Address, srv.URL)
+	}
+	resp, err := tfeConfig.HTTPClient.Get(srv.URL)
+	if err != nil {
+		t.Fatalf("expected the CA file to be trusted: %v", err)
+	}
+	resp.Body.Close()
+
+	invalid := filepath.Join(t.TempDir(), "invalid.pem")
+	if err := os.WriteFile(invalid, []byte("not a certificate"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewTFEConfig(config.Config{TFCCAFile: invalid}, "token"); err == nil {
+		t.Error("expected an error for a CA file without certificates")
+	}
+	if _, err := NewTFEConfig(config.Config{TFCCAFile: filepath.Join(t.TempDir(), "missing.pem")}, "token"); err == nil {
+		t.Error("expected an error for a missing CA file")
+	}
+}
@@ -2,9 +2,12 @@ package tfc_api
 
 import (
 	"context"
+	"crypto/tls"
+	"errors"
 	"fmt"
 	"net/http"
 	"os"
+	"strings"
 
 	"github.com/hashicorp/go-tfe"
 	"github.com/rs/zerolog/log"
@@ -29,6 +32,9 @@ type rateLimitedTransport struct {
 }
 
 func (t *rateLimitedTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if t.limiter == nil {
+		return t.rt.RoundTrip(req)
+	}
 	if err := t.limiter.Wait(req.Context()); err != nil {
 		return nil, err
 	}
@@ -39,53 +45,114 @@ func (t *rateLimitedTransport) RoundTrip(req *http.Request) (*http.Response, err
 // ConfigurationVersions.Upload streams the repo and a fixed cap would
 // truncate slow uploads. Per-call deadlines flow through context.
 func newRateLimitedHTTPClient(limiter *rate.Limiter) *http.Client {
+	return newHTTPClient(limiter, nil)
+}
+
+// newHTTPClient returns a client limited by limiter, if set, and using the
+// TLS configuration tc, if set.
+func newHTTPClient(limiter *rate.Limiter, tc *tls.Config) *http.Client {
 	base, ok := http.DefaultTransport.(*http.Transport)
 	if !ok {
 		return &http.Client{Transport: &rateLimitedTransport{rt: http.DefaultTransport, limiter: limiter}}
 	}
-	return &http.Client{Transport: &rateLimitedTransport{rt: base.Clone(), limiter: limiter}}
+	transport := base.Clone()
+	if tc != nil {
+		transport.TLSClientConfig = tc
+	}
+	return &http.Client{Transport: &rateLimitedTransport{rt: transport, limiter: limiter}}
 }
 
 //go:generate mockgen -source api_client.go -destination=../mocks/mock_tfc_api.go -package=mocks github.com/zapier/tfbuddy/pkg/tfc_api
 type ApiClient interface {
 	GetPlanOutput(id string) ([]byte, error)
+	GetPlanLogs(ctx context.Context, planID string) ([]byte, error)
+	GetApplyLogs(ctx context.Context, applyID string) ([]byte, error)
 	GetRun(ctx context.Context, id string) (*tfe.Run, error)
+	ListSpeculativeRuns(ctx context.Context, workspaceID, messagePrefix string) ([]*tfe.Run, error)
+	CancelRun(ctx context.Context, runID, comment string) error
+	GetPolicyResults(ctx context.Context, runID string) (*PolicyResults, error)
+	ListRunsAwaitingPolicyOverride(ctx context.Context, workspaceID, messagePrefix string) ([]*tfe.Run, error)
+	OverridePolicy(ctx context.Context, runID, comment string) error
 	GetWorkspaceByName(ctx context.Context, org, name string) (*tfe.Workspace, error)
 	GetWorkspaceById(ctx context.Context, id string) (*tfe.Workspace, error)
+	CreateWorkspace(ctx context.Context, opts *ApiWorkspaceOptions) (*tfe.Workspace, error)
 	CreateRunFromSource(ctx context.Context, opts *ApiRunOptions) (*tfe.Run, error)
 	LockUnlockWorkspace(ctx context.Context, workspace string, reason string, tag string, lock bool) error
 	AddTags(ctx context.Context, workspace string, prefix string, value string) error
 	RemoveTagsByQuery(ctx context.Context, workspace string, query string) error
 	RemoveTagsByName(ctx context.Context, workspace string, names []string) error
 	GetTagsByQuery(ctx context.Context, workspace string, query string) ([]string, error)
+	ListWorkspaces(ctx context.Context, org string) ([]*tfe.Workspace, error)
+	EnsureNotificationConfiguration(ctx context.Context, workspaceID string, opts NotificationOptions) (NotificationSyncResult, error)
 }
 
 type TFCClient struct {
 	Client *tfe.Client
 }
 
-func NewTFCClient() ApiClient {
+// NewTFCClient creates the Terraform Cloud client. When tokens are configured
+// for individual organizations, it returns a MultiOrgClient using one client,
+// each with its own rate limiter, per token.
+func NewTFCClient(cfg config.Config) ApiClient {
+	orgTokens, err := loadOrganizationTokens(cfg, os.Environ())
+	if err != nil {
+		log.Fatal().Err(err).Msg("could not load TFC organization tokens")
+	}
 	token := os.Getenv("TFC_TOKEN")
-	if token == "" {
+	if token == "" && orgTokens.empty() {
 		log.Fatal().Msg("TFC_TOKEN not set")
 	}
 
 	rps := tfcRateLimitValue(config.KeyTFCRateLimitRPS, defaultTFCRateRPS)
 	burst := tfcRateLimitValue(config.KeyTFCRateLimitBurst, defaultTFCRateBurst)
-	limiter := rate.NewLimiter(rate.Limit(rps), burst)
 	log.Info().Int("rps", rps).Int("burst", burst).Msg("TFC client rate limit configured")
 
-	tfeConfig := &tfe.Config{
-		Token:      token,
-		HTTPClient: newRateLimitedHTTPClient(limiter),
-	}
-
-	tfcClient, err := tfe.NewClient(tfeConfig)
+	tc, err := tlsConfig(cfg)
 	if err != nil {
-		log.Fatal().Err(err)
+		log.Fatal().Err(err).Msg("could not configure TFC client")
+	}
+	newClient := func(token string) *TFCClient {
+		limiter := rate.NewLimiter(rate.Limit(rps), burst)
+		tfcClient, err := tfe.NewClient(&tfe.Config{
+			Address:    Address(cfg),
+			Token:      token,
+			HTTPClient: newHTTPClient(limiter, tc),
+		})
+		if err != nil {
+			log.Fatal().Err(err).Msg("could not create TFC client")
+		}
+		return &TFCClient{Client: tfcClient}
 	}
 
-	return &TFCClient{Client: tfcClient}
+	if orgTokens.empty() {
+		return newClient(token)
+	}
+	m := newMultiOrgClient()
+	byToken := map[string]*TFCClient{}
+	clientOf := func(token string) *TFCClient {
+		c, ok := byToken[token]
+		if !ok {
+			c = newClient(token)
+			byToken[token] = c
+		}
+		return c
+	}
+	switch {
+	case token != "" && cfg.TFCTokenFallback:
+		m.defaultClient = clientOf(token)
+		log.Info().Msg("TFC_TOKEN serves organizations without a token of their own")
+	case token != "":
+		log.Warn().Msgf("TFC_TOKEN is not used as tokens are configured for individual organizations, enable %s to use it for the other organizations", config.KeyTFCTokenFallback)
+	}
+	for org, orgToken := range orgTokens.byName {
+		m.byName[org] = clientOf(orgToken)
+		log.Info().Str("org", org).Msg("added TFC organization token")
+	}
+	for key, orgToken := range orgTokens.byEnvKey {
+		m.byEnvKey[key] = clientOf(orgToken)
+		log.Info().Str("org", key).Msg("added TFC organization token")
+	}
+	return m
 }
 
 // tfcRateLimitValue falls back when the configured value is invalid (zero or negative).
@@ -107,7 +174,7 @@ func (t *TFCClient) GetRun(ctx context.Context, id string) (*tfe.Run, error) {
 		ctx,
 		id,
 		&tfe.RunReadOptions{
-			Include: []tfe.RunIncludeOpt{tfe.RunPlan, tfe.RunWorkspace, tfe.RunConfigVer, tfe.RunApply},
+			Include: []tfe.RunIncludeOpt{tfe.RunPlan, tfe.RunWorkspace, tfe.RunConfigVer, tfe.RunApply, tfe.RunCostEstimate},
 		},
 	)
 	if err != nil {
@@ -118,6 +185,53 @@ func (t *TFCClient) GetRun(ctx context.Context, id string) (*tfe.Run, error) {
 	return run, nil
 }
 
+// ListSpeculativeRuns returns the recent plan only runs of the workspace whose
+// message starts with messagePrefix, newest first, with their cost estimate.
+func (t *TFCClient) ListSpeculativeRuns(ctx context.Context, workspaceID, messagePrefix string) ([]*tfe.Run, error) {
+	ctx, span := otel.Tracer("TFC").Start(ctx, "ListSpeculativeRuns", trace.WithAttributes(attribute.String("workspaceID", workspaceID)))
+	defer span.End()
+
+	list, err := t.Client.Runs.List(ctx, workspaceID, &tfe.RunListOptions{
+		ListOptions: tfe.ListOptions{PageSize: 100},
+		Operation:   string(tfe.RunOperationPlanOnly),
+		Include:     []tfe.RunIncludeOpt{tfe.RunCostEstimate},
+	})
+	if err != nil {
+		return nil, err
+	}
+	var runs []*tfe.Run
+	for _, run := range list.Items {
+		if strings.HasPrefix(run.Message, messagePrefix) {
+			runs = append(runs, run)
+		}
+	}
+	return runs, nil
+}
+
+// ErrRunNotCancelable is returned when a run can neither be canceled nor
+// discarded anymore.
+var ErrRunNotCancelable = errors.New("run can no longer be canceled")
+
+// CancelRun cancels the run if it is planning or applying, or discards it if
+// it is waiting to start or for confirmation. It returns ErrRunNotCancelable
+// when the run already finished.
+func (t *TFCClient) CancelRun(ctx context.Context, runID, comment string) error {
+	ctx, span := otel.Tracer("TFC").Start(ctx, "CancelRun", trace.WithAttributes(attribute.String("run_id", runID)))
+	defer span.End()
+
+	run, err := t.Client.Runs.Read(ctx, runID)
+	if err != nil {
+		return err
+	}
+	switch {
+	case run.Actions != nil && run.Actions.IsCancelable:
+		return t.Client.Runs.Cancel(ctx, runID, tfe.RunCancelOptions{Comment: tfe.String(comment)})
+	case run.Actions != nil && run.Actions.IsDiscardable:
+		return t.Client.Runs.Discard(ctx, runID, tfe.RunDiscardOptions{Comment: tfe.String(comment)})
+	}
+	return ErrRunNotCancelable
+}
+
 func (t *TFCClient) GetPlanOutput(id string) ([]byte, error) {
 	b, err := t.Client.Plans.ReadJSONOutput(
 		context.Background(),
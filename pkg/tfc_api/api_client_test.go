@@ -2,6 +2,8 @@ package tfc_api
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"strings"
@@ -10,6 +12,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/hashicorp/go-tfe"
 	"github.com/spf13/viper"
 	"golang.org/x/time/rate"
 )
@@ -176,3 +179,48 @@ func TestRateLimitedTransport_RoundTripIsConcurrencySafe(t *testing.T) {
 		t.Fatalf("expected %d successful round trips, got %d", goroutines, got)
 	}
 }
+
+func TestCancelRun(t *testing.T) {
+	tests := []struct {
+		name    string
+		actions string
+		want    string
+		wantErr error
+	}{
+		{name: "planning", actions: `{"is-cancelable":true,"is-discardable":false}`, want: "cancel"},
+		{name: "pending", actions: `{"is-cancelable":false,"is-discardable":true}`, want: "discard"},
+		{name: "finished", actions: `{"is-cancelable":false,"is-discardable":false}`, wantErr: ErrRunNotCancelable},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var action string
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/vnd.api+json")
+				switch {
+				case r.URL.Path == "/api/v2/ping":
+					w.WriteHeader(http.StatusNoContent)
+				case r.Method == http.MethodGet && r.URL.Path == "/api/v2/runs/run-1":
+					fmt.Fprintf(w, `{"data":{"id":"run-1","type":"runs","attributes":{"actions":%s}}}`, tt.actions)
+				case r.Method == http.MethodPost && strings.HasPrefix(r.URL.Path, "/api/v2/runs/run-1/actions/"):
+					action = strings.TrimPrefix(r.URL.Path, "/api/v2/runs/run-1/actions/")
+					w.WriteHeader(http.StatusAccepted)
+				default:
+					http.NotFound(w, r)
+				}
+			}))
+			defer srv.Close()
+			client, err := tfe.NewClient(&tfe.Config{Address: srv.URL, Token: "token", HTTPClient: srv.Client()})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			err = (&TFCClient{Client: client}).CancelRun(context.Background(), "run-1", "superseded")
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("CancelRun() error = %v, want %v", err, tt.wantErr)
+			}
+			if action != tt.want {
+				t.Errorf("action = %q, want %q", action, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,20 @@
+package tfc_api
+
+import (
+	"strconv"
+
+	"github.com/hashicorp/go-tfe"
+)
+
+// MonthlyCostDelta returns the change of the monthly cost estimated by TFC, in
+// USD, and false when TFC did not finish estimating the cost.
+func MonthlyCostDelta(ce *tfe.CostEstimate) (float64, bool) {
+	if ce == nil || ce.Status != tfe.CostEstimateFinished {
+		return 0, false
+	}
+	delta, err := strconv.ParseFloat(ce.DeltaMonthlyCost, 64)
+	if err != nil {
+		return 0, false
+	}
+	return delta, true
+}
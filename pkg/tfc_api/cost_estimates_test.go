@@ -0,0 +1,30 @@
+package tfc_api
+
+import (
+	"testing"
+
+	"github.com/hashicorp/go-tfe"
+)
+
+func TestMonthlyCostDelta(t *testing.T) {
+	tests := []struct {
+		name   string
+		ce     *tfe.CostEstimate
+		want   float64
+		wantOK bool
+	}{
+		{name: "no estimate"},
+		{name: "finished", ce: &tfe.CostEstimate{Status: tfe.CostEstimateFinished, DeltaMonthlyCost: "12.5"}, want: 12.5, wantOK: true},
+		{name: "decrease", ce: &tfe.CostEstimate{Status: tfe.CostEstimateFinished, DeltaMonthlyCost: "-3.25"}, want: -3.25, wantOK: true},
+		{name: "errored", ce: &tfe.CostEstimate{Status: tfe.CostEstimateErrored, DeltaMonthlyCost: "12.5"}},
+		{name: "skipped", ce: &tfe.CostEstimate{Status: tfe.CostEstimateSkippedDueToTargeting}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := MonthlyCostDelta(tt.ce)
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("MonthlyCostDelta() = %v, %v, want %v, %v", got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
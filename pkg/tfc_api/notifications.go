@@ -0,0 +1,174 @@
+package tfc_api
+
+import (
+	"context"
+	"slices"
+	"strings"
+
+	"github.com/hashicorp/go-tfe"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/zapier/tfbuddy/internal/config"
+)
+
+// NotificationConfigurationName names the notification configuration TFBuddy
+// manages on workspaces.
+const NotificationConfigurationName = "tfbuddy"
+
+// NotificationTriggers are the run events TFBuddy needs to follow runs.
+var NotificationTriggers = []tfe.NotificationTriggerType{
+	tfe.NotificationTriggerCreated,
+	tfe.NotificationTriggerPlanning,
+	tfe.NotificationTriggerErrored,
+	tfe.NotificationTriggerNeedsAttention,
+	tfe.NotificationTriggerApplying,
+	tfe.NotificationTriggerCompleted,
+}
+
+// NotificationSyncResult describes what was done to the notification
+// configuration of a workspace.
+type NotificationSyncResult string
+
+const (
+	NotificationCreated   NotificationSyncResult = "created"
+	NotificationUpdated   NotificationSyncResult = "updated"
+	NotificationUnchanged NotificationSyncResult = "unchanged"
+)
+
+type NotificationOptions struct {
+	// URL is the public URL of the TFBuddy notification hook.
+	URL string
+	// Token signs the notifications.
+	Token string
+	// Force updates an existing configuration that has not drifted. TFC never
+	// returns the token, so this is the only way to change it.
+	Force bool
+}
+
+// NewNotificationOptions returns the notification configuration set up by cfg.
+func NewNotificationOptions(cfg config.Config) NotificationOptions {
+	opts := NotificationOptions{URL: cfg.TFCNotificationURL}
+	if len(cfg.TFCNotificationTokens) > 0 {
+		opts.Token = cfg.TFCNotificationTokens[0]
+	}
+	return opts
+}
+
+// NotificationsManaged reports whether TFBuddy manages the notification
+// configuration of the workspace: a notification URL must be configured and
+// the workspace must not be in `tfc-notification-deny-list`.
+func NotificationsManaged(cfg config.Config, org, workspace string) bool {
+	if cfg.TFCNotificationURL == "" {
+		return false
+	}
+	fullName := strings.ToLower(org + "/" + workspace)
+	for _, denied := range cfg.TFCNotificationDenyList {
+		denied = strings.ToLower(strings.TrimSpace(denied))
+		if !strings.Contains(denied, "/") {
+			denied = strings.ToLower(cfg.DefaultTFCOrganization) + "/" + denied
+		}
+		if denied == fullName {
+			return false
+		}
+	}
+	return true
+}
+
+// EnsureNotificationConfiguration creates the TFBuddy notification
+// configuration of the workspace, or updates it when it has drifted.
+func (t *TFCClient) EnsureNotificationConfiguration(ctx context.Context, workspaceID string, opts NotificationOptions) (NotificationSyncResult, error) {
+	ctx, span := otel.Tracer("TFC").Start(ctx, "EnsureNotificationConfiguration", trace.WithAttributes(
+		attribute.String("workspaceID", workspaceID),
+	))
+	defer span.End()
+
+	nc, err := t.findNotificationConfiguration(ctx, workspaceID, opts.URL)
+	if err != nil {
+		return "", err
+	}
+	if nc == nil {
+		_, err := t.Client.NotificationConfigurations.Create(ctx, workspaceID, tfe.NotificationConfigurationCreateOptions{
+			DestinationType: tfe.NotificationDestination(tfe.NotificationDestinationTypeGeneric),
+			Enabled:         tfe.Bool(true),
+			Name:            tfe.String(NotificationConfigurationName),
+			Token:           tfe.String(opts.Token),
+			Triggers:        NotificationTriggers,
+			URL:             tfe.String(opts.URL),
+		})
+		if err != nil {
+			return "", err
+		}
+		return NotificationCreated, nil
+	}
+	if !opts.Force && !notificationDrifted(nc, opts) {
+		return NotificationUnchanged, nil
+	}
+	_, err = t.Client.NotificationConfigurations.Update(ctx, nc.ID, tfe.NotificationConfigurationUpdateOptions{
+		Enabled:  tfe.Bool(true),
+		Token:    tfe.String(opts.Token),
+		Triggers: NotificationTriggers,
+		URL:      tfe.String(opts.URL),
+	})
+	if err != nil {
+		return "", err
+	}
+	return NotificationUpdated, nil
+}
+
+// findNotificationConfiguration returns the generic notification
+// configuration of the workspace named for TFBuddy or sending to url.
+func (t *TFCClient) findNotificationConfiguration(ctx context.Context, workspaceID, url string) (*tfe.NotificationConfiguration, error) {
+	opts := &tfe.NotificationConfigurationListOptions{ListOptions: tfe.ListOptions{PageSize: 100}}
+	for {
+		list, err := t.Client.NotificationConfigurations.List(ctx, workspaceID, opts)
+		if err != nil {
+			return nil, err
+		}
+		for _, nc := range list.Items {
+			if nc.DestinationType != tfe.NotificationDestinationTypeGeneric {
+				continue
+			}
+			if nc.Name == NotificationConfigurationName || nc.URL == url {
+				return nc, nil
+			}
+		}
+		if list.Pagination == nil || list.NextPage == 0 {
+			return nil, nil
+		}
+		opts.PageNumber = list.NextPage
+	}
+}
+
+func notificationDrifted(nc *tfe.NotificationConfiguration, opts NotificationOptions) bool {
+	if !nc.Enabled || nc.URL != opts.URL {
+		return true
+	}
+	for _, trigger := range NotificationTriggers {
+		if !slices.Contains(nc.Triggers, string(trigger)) {
+			return true
+		}
+	}
+	return false
+}
+
+// ListWorkspaces returns all workspaces of the organization.
+func (t *TFCClient) ListWorkspaces(ctx context.Context, org string) ([]*tfe.Workspace, error) {
+	ctx, span := otel.Tracer("TFC").Start(ctx, "ListWorkspaces", trace.WithAttributes(attribute.String("org", org)))
+	defer span.End()
+
+	var workspaces []*tfe.Workspace
+	opts := &tfe.WorkspaceListOptions{ListOptions: tfe.ListOptions{PageSize: 100}}
+	for {
+		list, err := t.Client.Workspaces.List(ctx, org, opts)
+		if err != nil {
+			return nil, err
+		}
+		workspaces = append(workspaces, list.Items...)
+		if list.Pagination == nil || list.NextPage == 0 {
+			return workspaces, nil
+		}
+		opts.PageNumber = list.NextPage
+	}
+}
@@ -0,0 +1,114 @@
+package tfc_api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hashicorp/go-tfe"
+	"github.com/zapier/tfbuddy/internal/config"
+)
+
+func TestNotificationsManaged(t *testing.T) {
+	cfg := config.Config{
+		TFCNotificationURL:      "https://tfbuddy.example.com/hooks/tfc/notification",
+		DefaultTFCOrganization:  "zapier",
+		TFCNotificationDenyList: []string{"legacy", "other/Custom"},
+	}
+	tests := []struct {
+		org, workspace string
+		want           bool
+	}{
+		{org: "zapier", workspace: "aws", want: true},
+		{org: "zapier", workspace: "legacy"},
+		{org: "other", workspace: "custom"},
+		{org: "other", workspace: "legacy", want: true},
+	}
+	for _, tt := range tests {
+		if got := NotificationsManaged(cfg, tt.org, tt.workspace); got != tt.want {
+			t.Errorf("NotificationsManaged(%s/%s) = %v, want %v", tt.org, tt.workspace, got, tt.want)
+		}
+	}
+	if NotificationsManaged(config.Config{}, "zapier", "aws") {
+		t.Error("expected notifications not to be managed without a URL")
+	}
+}
+
+func TestEnsureNotificationConfiguration(t *testing.T) {
+	const url = "https://tfbuddy.example.com/hooks/tfc/notification"
+	allTriggers := `["run:created","run:planning","run:errored","run:needs_attention","run:applying","run:completed"]`
+	existing := func(name, url, triggers string, enabled bool) string {
+		return fmt.Sprintf(`{"id":"nc-1","type":"notification-configurations","attributes":{"name":%q,"destination-type":"generic","enabled":%v,"url":%q,"triggers":%s}}`, name, enabled, url, triggers)
+	}
+	tests := []struct {
+		name       string
+		existing   string
+		force      bool
+		want       NotificationSyncResult
+		wantMethod string
+	}{
+		{name: "missing", want: NotificationCreated, wantMethod: http.MethodPost},
+		{name: "up to date", existing: existing("tfbuddy", url, allTriggers, true), want: NotificationUnchanged},
+		{name: "forced", existing: existing("tfbuddy", url, allTriggers, true), force: true, want: NotificationUpdated, wantMethod: http.MethodPatch},
+		{name: "disabled", existing: existing("tfbuddy", url, allTriggers, false), want: NotificationUpdated, wantMethod: http.MethodPatch},
+		{name: "missing triggers", existing: existing("tfbuddy", url, `["run:completed"]`, true), want: NotificationUpdated, wantMethod: http.MethodPatch},
+		{name: "other name", existing: existing("hand made", url, `["run:completed"]`, true), want: NotificationUpdated, wantMethod: http.MethodPatch},
+		{name: "moved url", existing: existing("tfbuddy", "https://old.example.com", allTriggers, true), want: NotificationUpdated, wantMethod: http.MethodPatch},
+		{name: "unrelated", existing: existing("slack", "https://hooks.example.com", allTriggers, true), want: NotificationCreated, wantMethod: http.MethodPost},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotMethod string
+			var gotBody map[string]any
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/vnd.api+json")
+				switch {
+				case r.URL.Path == "/api/v2/ping":
+					w.WriteHeader(http.StatusNoContent)
+				case r.Method == http.MethodGet && r.URL.Path == "/api/v2/workspaces/ws-1/notification-configurations":
+					items := "[]"
+					if tt.existing != "" {
+						items = "[" + tt.existing + "]"
+					}
+					fmt.Fprintf(w, `{"data":%s,"meta":{"pagination":{"current-page":1,"total-pages":1}}}`, items)
+				case r.Method == http.MethodPost && r.URL.Path == "/api/v2/workspaces/ws-1/notification-configurations",
+					r.Method == http.MethodPatch && r.URL.Path == "/api/v2/notification-configurations/nc-1":
+					gotMethod = r.Method
+					b, _ := io.ReadAll(r.Body)
+					json.Unmarshal(b, &gotBody)
+					w.WriteHeader(http.StatusOK)
+					fmt.Fprintf(w, `{"data":%s}`, existing("tfbuddy", url, allTriggers, true))
+				default:
+					http.NotFound(w, r)
+				}
+			}))
+			defer srv.Close()
+			client, err := tfe.NewClient(&tfe.Config{Address: srv.URL, Token: "token", HTTPClient: srv.Client()})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := (&TFCClient{Client: client}).EnsureNotificationConfiguration(context.Background(), "ws-1", NotificationOptions{URL: url, Token: "secret", Force: tt.force})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("EnsureNotificationConfiguration() = %s, want %s", got, tt.want)
+			}
+			if gotMethod != tt.wantMethod {
+				t.Errorf("method = %s, want %s", gotMethod, tt.wantMethod)
+			}
+			if tt.wantMethod == "" {
+				return
+			}
+			attrs := gotBody["data"].(map[string]any)["attributes"].(map[string]any)
+			if attrs["url"] != url || attrs["token"] != "secret" || attrs["enabled"] != true {
+				t.Errorf("unexpected attributes %v", attrs)
+			}
+		})
+	}
+}
@@ -0,0 +1,420 @@
+package tfc_api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/hashicorp/go-tfe"
+	"gopkg.in/yaml.v2"
+
+	"github.com/zapier/tfbuddy/internal/config"
+)
+
+// ErrNoOrganizationCredentials is returned for organizations without a token.
+var ErrNoOrganizationCredentials = errors.New("no TFC credentials configured for organization")
+
+const orgTokenEnvPrefix = "TFC_TOKEN_"
+
+// maxKnownResources bounds the resource ID to client cache of a MultiOrgClient.
+const maxKnownResources = 10000
+
+var envOrgReplacer = regexp.MustCompile(`[^A-Z0-9]+`)
+
+// orgKey normalizes an organization name the way it is spelled in the name of
+// its token's environment variable, e.g. my-org becomes MY_ORG.
+func orgKey(org string) string {
+	return envOrgReplacer.ReplaceAllString(strings.ToUpper(org), "_")
+}
+
+// orgTokens holds the tokens configured for individual organizations.
+type orgTokens struct {
+	// byName holds the tokens of the tokens file, keyed by exact organization
+	// name.
+	byName map[string]string
+	// byEnvKey holds the TFC_TOKEN_<ORG> tokens, keyed by orgKey.
+	byEnvKey map[string]string
+}
+
+func (t orgTokens) empty() bool {
+	return len(t.byName) == 0 && len(t.byEnvKey) == 0
+}
+
+// loadOrganizationTokens returns the tokens configured for individual
+// organizations. Tokens are read from `tfc-tokens-file`, a YAML map of
+// organization names to tokens, and from TFC_TOKEN_<ORG> environment
+// variables, which take precedence. An environment variable matching several
+// organizations of the file is rejected, as it is unclear which one it is for.
+func loadOrganizationTokens(cfg config.Config, environ []string) (orgTokens, error) {
+	tokens := orgTokens{byName: map[string]string{}, byEnvKey: map[string]string{}}
+	if cfg.TFCTokensFile != "" {
+		b, err := os.ReadFile(cfg.TFCTokensFile)
+		if err != nil {
+			return tokens, fmt.Errorf("could not read TFC tokens file. %w", err)
+		}
+		fileTokens := map[string]string{}
+		if err := yaml.Unmarshal(b, &fileTokens); err != nil {
+			return tokens, fmt.Errorf("could not parse TFC tokens file %s. %w", cfg.TFCTokensFile, err)
+		}
+		for org, token := range fileTokens {
+			if token != "" {
+				tokens.byName[org] = token
+			}
+		}
+	}
+	fileOrgs := map[string][]string{}
+	for org := range tokens.byName {
+		fileOrgs[orgKey(org)] = append(fileOrgs[orgKey(org)], org)
+	}
+	for _, kv := range environ {
+		name, token, ok := strings.Cut(kv, "=")
+		if !ok || token == "" || !strings.HasPrefix(name, orgTokenEnvPrefix) {
+			continue
+		}
+		key := orgKey(strings.TrimPrefix(name, orgTokenEnvPrefix))
+		if key == "" {
+			continue
+		}
+		if orgs := fileOrgs[key]; len(orgs) > 1 {
+			sort.Strings(orgs)
+			return tokens, fmt.Errorf("%s matches the organizations %s of the TFC tokens file, set their tokens in the file only", name, strings.Join(orgs, ", "))
+		}
+		tokens.byEnvKey[key] = token
+	}
+	return tokens, nil
+}
+
+// CheckOrganization returns an error when client has no credentials for org.
+func CheckOrganization(client ApiClient, org string) error {
+	if m, ok := client.(*MultiOrgClient); ok {
+		_, err := m.clientFor(org)
+		return err
+	}
+	return nil
+}
+
+// ensure type complies with interface
+var _ ApiClient = (*MultiOrgClient)(nil)
+
+// MultiOrgClient serves several TFC organizations, each with its own token.
+// Calls naming an organization are routed to its client. Calls naming only a
+// workspace, run or plan ID go to the client that last returned that
+// resource, or are tried with every client until one can see it.
+type MultiOrgClient struct {
+	// defaultClient uses TFC_TOKEN and serves organizations without a token
+	// when `tfc-token-fallback` is enabled.
+	defaultClient *TFCClient
+	// byName holds the clients of the tokens file, keyed by exact
+	// organization name, byEnvKey those of TFC_TOKEN_<ORG>, keyed by orgKey.
+	byName   map[string]*TFCClient
+	byEnvKey map[string]*TFCClient
+
+	mu    sync.Mutex
+	owner map[string]*TFCClient
+}
+
+func newMultiOrgClient() *MultiOrgClient {
+	return &MultiOrgClient{
+		byName:   map[string]*TFCClient{},
+		byEnvKey: map[string]*TFCClient{},
+		owner:    map[string]*TFCClient{},
+	}
+}
+
+func (m *MultiOrgClient) clientFor(org string) (*TFCClient, error) {
+	if c, ok := m.byEnvKey[orgKey(org)]; ok {
+		return c, nil
+	}
+	if c, ok := m.byName[org]; ok {
+		return c, nil
+	}
+	if m.defaultClient == nil {
+		return nil, fmt.Errorf("%w %q, set %s%s", ErrNoOrganizationCredentials, org, orgTokenEnvPrefix, orgKey(org))
+	}
+	return m.defaultClient, nil
+}
+
+// candidates returns every distinct client, in a stable order.
+func (m *MultiOrgClient) candidates() []*TFCClient {
+	seen := map[*TFCClient]bool{}
+	var clients []*TFCClient
+	add := func(c *TFCClient) {
+		if c != nil && !seen[c] {
+			seen[c] = true
+			clients = append(clients, c)
+		}
+	}
+	for _, byOrg := range []map[string]*TFCClient{m.byEnvKey, m.byName} {
+		orgs := make([]string, 0, len(byOrg))
+		for org := range byOrg {
+			orgs = append(orgs, org)
+		}
+		sort.Strings(orgs)
+		for _, org := range orgs {
+			add(byOrg[org])
+		}
+	}
+	add(m.defaultClient)
+	return clients
+}
+
+func (m *MultiOrgClient) remember(c *TFCClient, ids ...string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if len(m.owner) >= maxKnownResources {
+		m.owner = map[string]*TFCClient{}
+	}
+	for _, id := range ids {
+		if id != "" {
+			m.owner[id] = c
+		}
+	}
+}
+
+// withOwner calls fn with the client that can access the resource id.
+func (m *MultiOrgClient) withOwner(id string, fn func(c *TFCClient) error) error {
+	m.mu.Lock()
+	c, ok := m.owner[id]
+	m.mu.Unlock()
+	if ok {
+		return fn(c)
+	}
+
+	var err error
+	for _, c := range m.candidates() {
+		err = fn(c)
+		// TFC answers with not found for resources of other organizations
+		if errors.Is(err, tfe.ErrResourceNotFound) || errors.Is(err, tfe.ErrUnauthorized) {
+			continue
+		}
+		if err == nil {
+			m.remember(c, id)
+		}
+		return err
+	}
+	return err
+}
+
+// rememberRun records the client owning run and its related resources.
+func (m *MultiOrgClient) rememberRun(c *TFCClient, run *tfe.Run) {
+	if run == nil {
+		return
+	}
+	ids := []string{run.ID}
+	if run.Workspace != nil {
+		ids = append(ids, run.Workspace.ID)
+	}
+	if run.Plan != nil {
+		ids = append(ids, run.Plan.ID)
+	}
+	if run.Apply != nil {
+		ids = append(ids, run.Apply.ID)
+	}
+	m.remember(c, ids...)
+}
+
+func (m *MultiOrgClient) GetPlanOutput(id string) ([]byte, error) {
+	var b []byte
+	err := m.withOwner(id, func(c *TFCClient) (err error) {
+		b, err = c.GetPlanOutput(id)
+		return err
+	})
+	return b, err
+}
+
+func (m *MultiOrgClient) CancelRun(ctx context.Context, runID, comment string) error {
+	return m.withOwner(runID, func(c *TFCClient) error {
+		return c.CancelRun(ctx, runID, comment)
+	})
+}
+
+func (m *MultiOrgClient) GetPlanLogs(ctx context.Context, planID string) ([]byte, error) {
+	var b []byte
+	err := m.withOwner(planID, func(c *TFCClient) (err error) {
+		b, err = c.GetPlanLogs(ctx, planID)
+		return err
+	})
+	return b, err
+}
+
+func (m *MultiOrgClient) GetApplyLogs(ctx context.Context, applyID string) ([]byte, error) {
+	var b []byte
+	err := m.withOwner(applyID, func(c *TFCClient) (err error) {
+		b, err = c.GetApplyLogs(ctx, applyID)
+		return err
+	})
+	return b, err
+}
+
+func (m *MultiOrgClient) GetRun(ctx context.Context, id string) (*tfe.Run, error) {
+	var run *tfe.Run
+	var owner *TFCClient
+	err := m.withOwner(id, func(c *TFCClient) (err error) {
+		run, err = c.GetRun(ctx, id)
+		owner = c
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	m.rememberRun(owner, run)
+	return run, nil
+}
+
+func (m *MultiOrgClient) ListSpeculativeRuns(ctx context.Context, workspaceID, messagePrefix string) ([]*tfe.Run, error) {
+	var runs []*tfe.Run
+	var owner *TFCClient
+	err := m.withOwner(workspaceID, func(c *TFCClient) (err error) {
+		runs, err = c.ListSpeculativeRuns(ctx, workspaceID, messagePrefix)
+		owner = c
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, run := range runs {
+		m.remember(owner, run.ID)
+	}
+	return runs, nil
+}
+
+func (m *MultiOrgClient) GetPolicyResults(ctx context.Context, runID string) (*PolicyResults, error) {
+	var results *PolicyResults
+	err := m.withOwner(runID, func(c *TFCClient) (err error) {
+		results, err = c.GetPolicyResults(ctx, runID)
+		return err
+	})
+	return results, err
+}
+
+func (m *MultiOrgClient) ListRunsAwaitingPolicyOverride(ctx context.Context, workspaceID, messagePrefix string) ([]*tfe.Run, error) {
+	var runs []*tfe.Run
+	var owner *TFCClient
+	err := m.withOwner(workspaceID, func(c *TFCClient) (err error) {
+		runs, err = c.ListRunsAwaitingPolicyOverride(ctx, workspaceID, messagePrefix)
+		owner = c
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, run := range runs {
+		m.remember(owner, run.ID)
+	}
+	return runs, nil
+}
+
+func (m *MultiOrgClient) OverridePolicy(ctx context.Context, runID, comment string) error {
+	return m.withOwner(runID, func(c *TFCClient) error {
+		return c.OverridePolicy(ctx, runID, comment)
+	})
+}
+
+func (m *MultiOrgClient) GetWorkspaceByName(ctx context.Context, org, name string) (*tfe.Workspace, error) {
+	c, err := m.clientFor(org)
+	if err != nil {
+		return nil, err
+	}
+	ws, err := c.GetWorkspaceByName(ctx, org, name)
+	if err != nil {
+		return nil, err
+	}
+	m.remember(c, ws.ID)
+	return ws, nil
+}
+
+func (m *MultiOrgClient) GetWorkspaceById(ctx context.Context, id string) (*tfe.Workspace, error) {
+	var ws *tfe.Workspace
+	err := m.withOwner(id, func(c *TFCClient) (err error) {
+		ws, err = c.GetWorkspaceById(ctx, id)
+		return err
+	})
+	return ws, err
+}
+
+func (m *MultiOrgClient) CreateWorkspace(ctx context.Context, opts *ApiWorkspaceOptions) (*tfe.Workspace, error) {
+	c, err := m.clientFor(opts.Organization)
+	if err != nil {
+		return nil, err
+	}
+	ws, err := c.CreateWorkspace(ctx, opts)
+	if ws != nil {
+		m.remember(c, ws.ID)
+	}
+	return ws, err
+}
+
+func (m *MultiOrgClient) CreateRunFromSource(ctx context.Context, opts *ApiRunOptions) (*tfe.Run, error) {
+	c, err := m.clientFor(opts.Organization)
+	if err != nil {
+		return nil, err
+	}
+	run, err := c.CreateRunFromSource(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	m.rememberRun(c, run)
+	return run, nil
+}
+
+func (m *MultiOrgClient) LockUnlockWorkspace(ctx context.Context, workspace string, reason string, tag string, lock bool) error {
+	return m.withOwner(workspace, func(c *TFCClient) error {
+		return c.LockUnlockWorkspace(ctx, workspace, reason, tag, lock)
+	})
+}
+
+func (m *MultiOrgClient) AddTags(ctx context.Context, workspace string, prefix string, value string) error {
+	return m.withOwner(workspace, func(c *TFCClient) error {
+		return c.AddTags(ctx, workspace, prefix, value)
+	})
+}
+
+func (m *MultiOrgClient) RemoveTagsByQuery(ctx context.Context, workspace string, query string) error {
+	return m.withOwner(workspace, func(c *TFCClient) error {
+		return c.RemoveTagsByQuery(ctx, workspace, query)
+	})
+}
+
+func (m *MultiOrgClient) RemoveTagsByName(ctx context.Context, workspace string, names []string) error {
+	return m.withOwner(workspace, func(c *TFCClient) error {
+		return c.RemoveTagsByName(ctx, workspace, names)
+	})
+}
+
+func (m *MultiOrgClient) GetTagsByQuery(ctx context.Context, workspace string, query string) ([]string, error) {
+	var tags []string
+	err := m.withOwner(workspace, func(c *TFCClient) (err error) {
+		tags, err = c.GetTagsByQuery(ctx, workspace, query)
+		return err
+	})
+	return tags, err
+}
+
+func (m *MultiOrgClient) ListWorkspaces(ctx context.Context, org string) ([]*tfe.Workspace, error) {
+	c, err := m.clientFor(org)
+	if err != nil {
+		return nil, err
+	}
+	workspaces, err := c.ListWorkspaces(ctx, org)
+	if err != nil {
+		return nil, err
+	}
+	for _, ws := range workspaces {
+		m.remember(c, ws.ID)
+	}
+	return workspaces, nil
+}
+
+func (m *MultiOrgClient) EnsureNotificationConfiguration(ctx context.Context, workspaceID string, opts NotificationOptions) (NotificationSyncResult, error) {
+	var result NotificationSyncResult
+	err := m.withOwner(workspaceID, func(c *TFCClient) (err error) {
+		result, err = c.EnsureNotificationConfiguration(ctx, workspaceID, opts)
+		return err
+	})
+	return result, err
+}
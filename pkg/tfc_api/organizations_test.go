@@ -0,0 +1,150 @@
+package tfc_api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/hashicorp/go-tfe"
+	"github.com/zapier/tfbuddy/internal/config"
+)
+
+func TestLoadOrganizationTokens(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "tokens.yaml")
+	if err := os.WriteFile(file, []byte("acme-prod: file-prod\nacme-dev: file-dev\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	got, err := loadOrganizationTokens(config.Config{TFCTokensFile: file}, []string{
+		"TFC_TOKEN=default",
+		"TFC_TOKEN_ACME_DEV=env-dev",
+		"TFC_TOKEN_ACME_STAGING=env-staging",
+		"TFC_TOKEN_EMPTY=",
+		"PATH=/usr/bin",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := orgTokens{
+		byName:   map[string]string{"acme-prod": "file-prod", "acme-dev": "file-dev"},
+		byEnvKey: map[string]string{"ACME_DEV": "env-dev", "ACME_STAGING": "env-staging"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("loadOrganizationTokens() = %+v, want %+v", got, want)
+	}
+
+	// organizations of the file are told apart by their exact name
+	if err := os.WriteFile(file, []byte("my-org: token-dash\nmy_org: token-underscore\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	got, err = loadOrganizationTokens(config.Config{TFCTokensFile: file}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want = orgTokens{byName: map[string]string{"my-org": "token-dash", "my_org": "token-underscore"}, byEnvKey: map[string]string{}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("loadOrganizationTokens() = %+v, want %+v", got, want)
+	}
+	_, err = loadOrganizationTokens(config.Config{TFCTokensFile: file}, []string{"TFC_TOKEN_MY_ORG=env"})
+	if err == nil || !strings.Contains(err.Error(), "my-org, my_org") {
+		t.Errorf("expected an ambiguous TFC_TOKEN_MY_ORG to be rejected, got %v", err)
+	}
+
+	if _, err := loadOrganizationTokens(config.Config{TFCTokensFile: filepath.Join(t.TempDir(), "missing.yaml")}, nil); err == nil {
+		t.Error("expected an error for a missing tokens file")
+	}
+}
+
+func TestMultiOrgClient_Routing(t *testing.T) {
+	// each token can only see the workspace of its organization
+	workspaces := map[string]string{"token-prod": "ws-prod", "token-dev": "ws-dev"}
+	var mu sync.Mutex
+	requests := map[string]int{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if r.URL.Path == "/api/v2/ping" {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		mu.Lock()
+		requests[token]++
+		mu.Unlock()
+		w.Header().Set("Content-Type", "application/vnd.api+json")
+		id := workspaces[token]
+		switch r.URL.Path {
+		case "/api/v2/workspaces/" + id, "/api/v2/organizations/acme-" + strings.TrimPrefix(id, "ws-") + "/workspaces/" + id:
+			fmt.Fprintf(w, `{"data":{"id":%q,"type":"workspaces","attributes":{"name":%q}}}`, id, id)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"errors":[{"status":"404","title":"not found"}]}`)
+		}
+	}))
+	defer srv.Close()
+
+	newClient := func(token string) *TFCClient {
+		c, err := tfe.NewClient(&tfe.Config{Address: srv.URL, Token: token, HTTPClient: srv.Client()})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return &TFCClient{Client: c}
+	}
+	m := newMultiOrgClient()
+	m.byEnvKey[orgKey("acme-prod")] = newClient("token-prod")
+	m.byEnvKey[orgKey("acme-dev")] = newClient("token-dev")
+	ctx := context.Background()
+
+	ws, err := m.GetWorkspaceByName(ctx, "acme-dev", "ws-dev")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ws.ID != "ws-dev" || requests["token-dev"] != 1 || requests["token-prod"] != 0 {
+		t.Fatalf("expected the dev token to be used, got %s and requests %v", ws.ID, requests)
+	}
+
+	// the workspace is unknown, so every token is tried until one can see it
+	if _, err := m.GetWorkspaceById(ctx, "ws-prod"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := m.GetWorkspaceById(ctx, "ws-prod"); err != nil {
+		t.Fatal(err)
+	}
+	if requests["token-prod"] != 2 || requests["token-dev"] != 2 {
+		t.Errorf("expected the owner of ws-prod to be remembered, got requests %v", requests)
+	}
+
+	if _, err := m.GetWorkspaceById(ctx, "ws-other"); !errors.Is(err, tfe.ErrResourceNotFound) {
+		t.Errorf("expected not found, got %v", err)
+	}
+
+	_, err = m.GetWorkspaceByName(ctx, "other-org", "ws")
+	if !errors.Is(err, ErrNoOrganizationCredentials) || !strings.Contains(err.Error(), "TFC_TOKEN_OTHER_ORG") {
+		t.Errorf("expected missing credentials, got %v", err)
+	}
+	if err := CheckOrganization(m, "other-org"); !errors.Is(err, ErrNoOrganizationCredentials) {
+		t.Errorf("CheckOrganization() = %v", err)
+	}
+	if err := CheckOrganization(m, "Acme-Prod"); err != nil {
+		t.Errorf("CheckOrganization() = %v", err)
+	}
+
+	// tokens file entries only serve their exact organization
+	m.byName["my-org"] = newClient("token-my-org")
+	if err := CheckOrganization(m, "my-org"); err != nil {
+		t.Errorf("CheckOrganization() = %v", err)
+	}
+	if err := CheckOrganization(m, "my_org"); !errors.Is(err, ErrNoOrganizationCredentials) {
+		t.Errorf("expected my_org to have no credentials, got %v", err)
+	}
+
+	m.defaultClient = newClient("token-default")
+	if err := CheckOrganization(m, "other-org"); err != nil {
+		t.Errorf("expected TFC_TOKEN to serve other organizations, got %v", err)
+	}
+}
@@ -0,0 +1,262 @@
+package tfc_api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/go-tfe"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// ErrNoPolicyOverride is returned when a run has no failed policy that can be
+// overridden.
+var ErrNoPolicyOverride = errors.New("no policy check awaiting an override")
+
+// Policy outcome statuses, shared by Sentinel policy checks and policy
+// evaluations.
+const (
+	PolicyOutcomePassed  = "passed"
+	PolicyOutcomeFailed  = "failed"
+	PolicyOutcomeErrored = "errored"
+)
+
+// PolicyOutcome is the result of a single Sentinel or OPA policy for a run.
+type PolicyOutcome struct {
+	PolicySet        string
+	Policy           string
+	EnforcementLevel string
+	Status           string
+	Message          string
+}
+
+// PolicyResults are the policy outcomes of a run, from both the policy checks
+// of Sentinel and the policy evaluations of its task stages.
+type PolicyResults struct {
+	Outcomes []PolicyOutcome
+	// Overridable is true when failed policies can be overridden.
+	Overridable bool
+}
+
+// Failed returns the outcomes that did not pass.
+func (r *PolicyResults) Failed() []PolicyOutcome {
+	var failed []PolicyOutcome
+	for _, o := range r.Outcomes {
+		if o.Status != PolicyOutcomePassed {
+			failed = append(failed, o)
+		}
+	}
+	return failed
+}
+
+// GetPolicyResults returns the outcomes of every policy evaluated for the run.
+func (t *TFCClient) GetPolicyResults(ctx context.Context, runID string) (*PolicyResults, error) {
+	ctx, span := otel.Tracer("TFC").Start(ctx, "GetPolicyResults", trace.WithAttributes(attribute.String("run_id", runID)))
+	defer span.End()
+
+	results := &PolicyResults{}
+	checks, err := t.Client.PolicyChecks.List(ctx, runID, nil)
+	if err != nil {
+		return nil, fmt.Errorf("could not list policy checks. %w", err)
+	}
+	for _, pc := range checks.Items {
+		if pc.Result != nil {
+			results.Outcomes = append(results.Outcomes, sentinelOutcomes(pc.Result.Sentinel)...)
+		}
+		if pc.Status == tfe.PolicySoftFailed && pc.Actions != nil && pc.Actions.IsOverridable {
+			results.Overridable = true
+		}
+	}
+
+	stages, err := t.listTaskStages(ctx, runID)
+	if err != nil {
+		return nil, err
+	}
+	for _, stage := range stages {
+		if stage.Status == tfe.TaskStageAwaitingOverride {
+			results.Overridable = true
+		}
+		for _, pe := range stage.PolicyEvaluations {
+			outcomes, err := t.Client.PolicySetOutcomes.List(ctx, pe.ID, nil)
+			if err != nil {
+				return nil, fmt.Errorf("could not list policy set outcomes. %w", err)
+			}
+			for _, pso := range outcomes.Items {
+				for _, o := range pso.Outcomes {
+					results.Outcomes = append(results.Outcomes, PolicyOutcome{
+						PolicySet:        pso.PolicySetName,
+						Policy:           o.PolicyName,
+						EnforcementLevel: string(o.EnforcementLevel),
+						Status:           o.Status,
+						Message:          o.Description,
+					})
+				}
+				if pso.Error != "" {
+					results.Outcomes = append(results.Outcomes, PolicyOutcome{
+						PolicySet: pso.PolicySetName,
+						Status:    PolicyOutcomeErrored,
+						Message:   pso.Error,
+					})
+				}
+			}
+		}
+	}
+	return results, nil
+}
+
+// listTaskStages returns the task stages of the run. Terraform Enterprise
+// releases without task stages answer with not found.
+func (t *TFCClient) listTaskStages(ctx context.Context, runID string) ([]*tfe.TaskStage, error) {
+	stages, err := t.Client.TaskStages.List(ctx, runID, nil)
+	if errors.Is(err, tfe.ErrResourceNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("could not list task stages. %w", err)
+	}
+	return stages.Items, nil
+}
+
+// sentinelResult is the part of the Sentinel result of a policy check that
+// describes its policies, keyed by policy set name.
+type sentinelResult struct {
+	Data map[string]struct {
+		Policies []struct {
+			Policy           string `json:"policy"`
+			Result           bool   `json:"result"`
+			Error            any    `json:"error"`
+			EnforcementLevel string `json:"enforcement-level"`
+			AllowedFailure   bool   `json:"allowed-failure"`
+			Trace            struct {
+				Description string `json:"description"`
+				Print       string `json:"print"`
+			} `json:"trace"`
+		} `json:"policies"`
+	} `json:"data"`
+}
+
+func sentinelOutcomes(raw any) []PolicyOutcome {
+	if raw == nil {
+		return nil
+	}
+	b, err := json.Marshal(raw)
+	if err != nil {
+		return nil
+	}
+	var result sentinelResult
+	if err := json.Unmarshal(b, &result); err != nil {
+		return nil
+	}
+	sets := make([]string, 0, len(result.Data))
+	for set := range result.Data {
+		sets = append(sets, set)
+	}
+	sort.Strings(sets)
+
+	var outcomes []PolicyOutcome
+	for _, set := range sets {
+		for _, p := range result.Data[set].Policies {
+			o := PolicyOutcome{
+				PolicySet:        set,
+				Policy:           strings.TrimPrefix(p.Policy, set+"/"),
+				EnforcementLevel: p.EnforcementLevel,
+				Status:           PolicyOutcomePassed,
+				Message:          strings.TrimSpace(firstLine(p.Trace.Print, p.Trace.Description)),
+			}
+			if o.EnforcementLevel == "" {
+				o.EnforcementLevel = string(tfe.EnforcementMandatory)
+				if p.AllowedFailure {
+					o.EnforcementLevel = string(tfe.EnforcementAdvisory)
+				}
+			}
+			switch {
+			case p.Error != nil:
+				o.Status = PolicyOutcomeErrored
+			case !p.Result:
+				o.Status = PolicyOutcomeFailed
+			}
+			outcomes = append(outcomes, o)
+		}
+	}
+	return outcomes
+}
+
+func firstLine(values ...string) string {
+	for _, v := range values {
+		if v = strings.TrimSpace(v); v != "" {
+			line, _, _ := strings.Cut(v, "\n")
+			return line
+		}
+	}
+	return ""
+}
+
+// ListRunsAwaitingPolicyOverride returns the runs of the workspace whose
+// message starts with messagePrefix and that wait for their failed policies
+// to be overridden, newest first.
+func (t *TFCClient) ListRunsAwaitingPolicyOverride(ctx context.Context, workspaceID, messagePrefix string) ([]*tfe.Run, error) {
+	ctx, span := otel.Tracer("TFC").Start(ctx, "ListRunsAwaitingPolicyOverride", trace.WithAttributes(attribute.String("workspaceID", workspaceID)))
+	defer span.End()
+
+	list, err := t.Client.Runs.List(ctx, workspaceID, &tfe.RunListOptions{
+		ListOptions: tfe.ListOptions{PageSize: 100},
+		Status:      strings.Join([]string{string(tfe.RunPolicySoftFailed), string(tfe.RunPostPlanAwaitingDecision)}, ","),
+	})
+	if err != nil {
+		return nil, err
+	}
+	var runs []*tfe.Run
+	for _, run := range list.Items {
+		if strings.HasPrefix(run.Message, messagePrefix) {
+			runs = append(runs, run)
+		}
+	}
+	return runs, nil
+}
+
+// OverridePolicy overrides the soft failed policy checks and the policy
+// evaluations awaiting an override of the run, so that it can be applied.
+func (t *TFCClient) OverridePolicy(ctx context.Context, runID, comment string) error {
+	ctx, span := otel.Tracer("TFC").Start(ctx, "OverridePolicy", trace.WithAttributes(attribute.String("run_id", runID)))
+	defer span.End()
+
+	overridden := false
+	checks, err := t.Client.PolicyChecks.List(ctx, runID, nil)
+	if err != nil {
+		return fmt.Errorf("could not list policy checks. %w", err)
+	}
+	for _, pc := range checks.Items {
+		if pc.Status != tfe.PolicySoftFailed || pc.Actions == nil || !pc.Actions.IsOverridable {
+			continue
+		}
+		if _, err := t.Client.PolicyChecks.Override(ctx, pc.ID); err != nil {
+			return fmt.Errorf("could not override policy check %s. %w", pc.ID, err)
+		}
+		overridden = true
+	}
+
+	stages, err := t.listTaskStages(ctx, runID)
+	if err != nil {
+		return err
+	}
+	for _, stage := range stages {
+		if stage.Status != tfe.TaskStageAwaitingOverride {
+			continue
+		}
+		_, err := t.Client.TaskStages.Override(ctx, stage.ID, tfe.TaskStageOverrideOptions{Comment: tfe.String(comment)})
+		if err != nil {
+			return fmt.Errorf("could not override task stage %s. %w", stage.ID, err)
+		}
+		overridden = true
+	}
+
+	if !overridden {
+		return ErrNoPolicyOverride
+	}
+	return nil
+}
@@ -0,0 +1,123 @@
+package tfc_api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/go-tfe"
+)
+
+const (
+	testPolicyChecks = `{"data":[{"id":"polchk-1","type":"policy-checks","attributes":{"status":"soft_failed","actions":{"is-overridable":true},
+"result":{"passed":1,"soft-failed":1,"sentinel":{"data":{"networking":{"policies":[
+{"policy":"networking/deny-public-ssh","result":false,"enforcement-level":"soft-mandatory","trace":{"print":"port 22 is open to 0.0.0.0/0\nsecond line"}},
+{"policy":"networking/require-tags","result":true,"enforcement-level":"hard-mandatory"}]}}}}}}],"meta":{"pagination":{"current-page":1,"total-pages":1}}}`
+	testTaskStages = `{"data":[{"id":"ts-1","type":"task-stages","attributes":{"stage":"post_plan","status":"%s"},
+"relationships":{"policy-evaluations":{"data":[{"id":"poleval-1","type":"policy-evaluations"}]}}}],"meta":{"pagination":{"current-page":1,"total-pages":1}}}`
+	testPolicySetOutcomes = `{"data":[{"id":"psout-1","type":"policy-set-outcomes","attributes":{"policy-set-name":"opa-cost",
+"outcomes":[{"policy_name":"max-instances","enforcement_level":"mandatory","status":"failed","description":"too many instances"}]}}],"meta":{"pagination":{"current-page":1,"total-pages":1}}}`
+)
+
+func newPolicyTestServer(t *testing.T, stageStatus string, overridden *[]string) *TFCClient {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/vnd.api+json")
+		switch {
+		case r.URL.Path == "/api/v2/ping":
+			w.WriteHeader(http.StatusNoContent)
+		case r.URL.Path == "/api/v2/runs/run-1/policy-checks":
+			fmt.Fprint(w, testPolicyChecks)
+		case r.URL.Path == "/api/v2/runs/run-1/task-stages":
+			fmt.Fprintf(w, testTaskStages, stageStatus)
+		case r.URL.Path == "/api/v2/policy-evaluations/poleval-1/policy-set-outcomes":
+			fmt.Fprint(w, testPolicySetOutcomes)
+		case r.Method == http.MethodPost && r.URL.Path == "/api/v2/policy-checks/polchk-1/actions/override":
+			*overridden = append(*overridden, "polchk-1")
+			fmt.Fprint(w, `{"data":{"id":"polchk-1","type":"policy-checks","attributes":{"status":"overridden"}}}`)
+		case r.Method == http.MethodPost && r.URL.Path == "/api/v2/task-stages/ts-1/actions/override":
+			*overridden = append(*overridden, "ts-1")
+			fmt.Fprint(w, `{"data":{"id":"ts-1","type":"task-stages","attributes":{"status":"passed"}}}`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	client, err := tfe.NewClient(&tfe.Config{Address: srv.URL, Token: "token", HTTPClient: srv.Client()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &TFCClient{Client: client}
+}
+
+func TestGetPolicyResults(t *testing.T) {
+	client := newPolicyTestServer(t, "awaiting_override", nil)
+	results, err := client.GetPolicyResults(context.Background(), "run-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []PolicyOutcome{
+		{PolicySet: "networking", Policy: "deny-public-ssh", EnforcementLevel: "soft-mandatory", Status: PolicyOutcomeFailed, Message: "port 22 is open to 0.0.0.0/0"},
+		{PolicySet: "networking", Policy: "require-tags", EnforcementLevel: "hard-mandatory", Status: PolicyOutcomePassed},
+		{PolicySet: "opa-cost", Policy: "max-instances", EnforcementLevel: "mandatory", Status: PolicyOutcomeFailed, Message: "too many instances"},
+	}
+	if !reflect.DeepEqual(results.Outcomes, want) {
+		t.Errorf("Outcomes = %+v, want %+v", results.Outcomes, want)
+	}
+	if len(results.Failed()) != 2 {
+		t.Errorf("expected 2 failed outcomes, got %+v", results.Failed())
+	}
+	if !results.Overridable {
+		t.Error("expected the results to be overridable")
+	}
+}
+
+func TestOverridePolicy(t *testing.T) {
+	tests := []struct {
+		name        string
+		stageStatus string
+		want        []string
+	}{
+		{name: "policy check and task stage", stageStatus: "awaiting_override", want: []string{"polchk-1", "ts-1"}},
+		{name: "policy check only", stageStatus: "passed", want: []string{"polchk-1"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var overridden []string
+			client := newPolicyTestServer(t, tt.stageStatus, &overridden)
+			if err := client.OverridePolicy(context.Background(), "run-1", "ok"); err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(overridden, tt.want) {
+				t.Errorf("overridden = %v, want %v", overridden, tt.want)
+			}
+		})
+	}
+}
+
+func TestOverridePolicy_NothingToOverride(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/vnd.api+json")
+		switch r.URL.Path {
+		case "/api/v2/ping":
+			w.WriteHeader(http.StatusNoContent)
+		case "/api/v2/runs/run-1/policy-checks":
+			fmt.Fprint(w, `{"data":[],"meta":{"pagination":{"current-page":1,"total-pages":1}}}`)
+		default:
+			// Terraform Enterprise without task stages
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+	client, err := tfe.NewClient(&tfe.Config{Address: srv.URL, Token: "token", HTTPClient: srv.Client()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = (&TFCClient{Client: client}).OverridePolicy(context.Background(), "run-1", "ok")
+	if !errors.Is(err, ErrNoPolicyOverride) {
+		t.Errorf("expected ErrNoPolicyOverride, got %v", err)
+	}
+}
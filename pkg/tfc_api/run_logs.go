@@ -0,0 +1,177 @@
+package tfc_api
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// maxRunLogSize bounds how much of a plan or apply log is read. Terraform
+// prints its errors at the end of the log, so the tail is kept.
+const maxRunLogSize = 10 << 20
+
+// GetPlanLogs returns the log of the plan, in the raw or structured (JSON)
+// format Terraform wrote it.
+func (t *TFCClient) GetPlanLogs(ctx context.Context, planID string) ([]byte, error) {
+	ctx, span := otel.Tracer("TFC").Start(ctx, "GetPlanLogs", trace.WithAttributes(attribute.String("plan_id", planID)))
+	defer span.End()
+
+	r, err := t.Client.Plans.Logs(ctx, planID)
+	if err != nil {
+		return nil, fmt.Errorf("could not get the logs of plan %s. %w", planID, err)
+	}
+	return readLogTail(r, maxRunLogSize)
+}
+
+// GetApplyLogs returns the log of the apply, in the raw or structured (JSON)
+// format Terraform wrote it.
+func (t *TFCClient) GetApplyLogs(ctx context.Context, applyID string) ([]byte, error) {
+	ctx, span := otel.Tracer("TFC").Start(ctx, "GetApplyLogs", trace.WithAttributes(attribute.String("apply_id", applyID)))
+	defer span.End()
+
+	r, err := t.Client.Applies.Logs(ctx, applyID)
+	if err != nil {
+		return nil, fmt.Errorf("could not get the logs of apply %s. %w", applyID, err)
+	}
+	return readLogTail(r, maxRunLogSize)
+}
+
+// readLogTail reads r and keeps at most the last limit bytes of it in memory.
+func readLogTail(r io.Reader, limit int) ([]byte, error) {
+	var tail []byte
+	chunk := make([]byte, 32<<10)
+	for {
+		n, err := r.Read(chunk)
+		tail = append(tail, chunk[:n]...)
+		if len(tail) > 2*limit {
+			// drop the head once the window doubled, to copy rarely
+			tail = append(tail[:0], tail[len(tail)-limit:]...)
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	if len(tail) > limit {
+		tail = tail[len(tail)-limit:]
+	}
+	return tail, nil
+}
+
+var ansiEscape = regexp.MustCompile(`\x1b\[[0-9;]*[A-Za-z]`)
+
+// logDiagnostic is the part of a structured Terraform log message describing
+// a diagnostic.
+type logDiagnostic struct {
+	Type       string `json:"type"`
+	Diagnostic *struct {
+		Severity string `json:"severity"`
+		Summary  string `json:"summary"`
+		Detail   string `json:"detail"`
+		Range    *struct {
+			Filename string `json:"filename"`
+			Start    struct {
+				Line int `json:"line"`
+			} `json:"start"`
+		} `json:"range"`
+		Snippet *struct {
+			Context   *string `json:"context"`
+			Code      string  `json:"code"`
+			StartLine int     `json:"start_line"`
+		} `json:"snippet"`
+	} `json:"diagnostic"`
+}
+
+// ErrorDiagnostics extracts the `Error:` diagnostics of a plan or apply log,
+// with their file and line references, formatted like the Terraform CLI
+// prints them.
+func ErrorDiagnostics(log []byte) []string {
+	var diags []string
+	var current []string
+	flush := func() {
+		if len(current) > 0 {
+			diags = append(diags, strings.TrimSpace(strings.Join(current, "\n")))
+			current = nil
+		}
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(log))
+	scanner.Buffer(make([]byte, 0, 64*1024), maxRunLogSize)
+	for scanner.Scan() {
+		line := strings.TrimRight(ansiEscape.ReplaceAllString(scanner.Text(), ""), " \r")
+		if strings.HasPrefix(line, "{") && json.Valid([]byte(line)) {
+			// structured run output, one JSON message per line
+			if diag, ok := structuredDiagnostic(line); ok {
+				flush()
+				diags = append(diags, diag)
+			}
+			continue
+		}
+
+		// Terraform draws a box around each diagnostic
+		trimmed := strings.TrimLeft(line, " ")
+		switch {
+		case strings.HasPrefix(trimmed, "╷"):
+			flush()
+			continue
+		case strings.HasPrefix(trimmed, "╵"):
+			flush()
+			continue
+		case strings.HasPrefix(trimmed, "│"):
+			line = strings.TrimPrefix(strings.TrimPrefix(trimmed, "│"), " ")
+		}
+
+		switch {
+		case strings.HasPrefix(line, "Error: "):
+			flush()
+			current = []string{line}
+		case strings.HasPrefix(line, "Warning: "):
+			flush()
+		case current != nil:
+			current = append(current, line)
+		}
+	}
+	flush()
+	return diags
+}
+
+func structuredDiagnostic(line string) (string, bool) {
+	var msg logDiagnostic
+	if err := json.Unmarshal([]byte(line), &msg); err != nil {
+		return "", false
+	}
+	if msg.Type != "diagnostic" || msg.Diagnostic == nil || msg.Diagnostic.Severity != "error" {
+		return "", false
+	}
+	d := msg.Diagnostic
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "Error: %s", d.Summary)
+	if d.Range != nil && d.Range.Filename != "" {
+		fmt.Fprintf(&b, "\n\n  on %s line %d", d.Range.Filename, d.Range.Start.Line)
+		if d.Snippet != nil && d.Snippet.Context != nil {
+			fmt.Fprintf(&b, ", in %s", *d.Snippet.Context)
+		}
+		b.WriteString(":")
+		if d.Snippet != nil {
+			for i, code := range strings.Split(d.Snippet.Code, "\n") {
+				fmt.Fprintf(&b, "\n  %d: %s", d.Snippet.StartLine+i, code)
+			}
+		}
+	}
+	if d.Detail != "" {
+		fmt.Fprintf(&b, "\n\n%s", d.Detail)
+	}
+	return b.String(), true
+}
@@ -0,0 +1,75 @@
+package tfc_api
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+func TestErrorDiagnostics(t *testing.T) {
+	tests := []struct {
+		name string
+		log  string
+		want []string
+	}{
+		{
+			name: "raw log",
+			log: "Terraform v1.6.0\n\x1b[31m╷\x1b[0m\x1b[0m\n\x1b[31m│\x1b[0m \x1b[0m\x1b[1m\x1b[31mError: \x1b[0m\x1b[0m\x1b[1mUnsupported argument\x1b[0m\n" +
+				"│ \n│   on main.tf line 3, in resource \"aws_s3_bucket\" \"logs\":\n│    3:   acl_typo = \"private\"\n│ \n" +
+				"│ An argument named \"acl_typo\" is not expected here.\n╵\n╷\n│ Warning: Deprecated attribute\n│ \n│ Use something else.\n╵\n" +
+				"╷\n│ Error: No valid credential sources found\n╵\nOperation failed: failed running terraform plan (exit 1)\n",
+			want: []string{
+				"Error: Unsupported argument\n\n  on main.tf line 3, in resource \"aws_s3_bucket\" \"logs\":\n   3:   acl_typo = \"private\"\n\nAn argument named \"acl_typo\" is not expected here.",
+				"Error: No valid credential sources found",
+			},
+		},
+		{
+			name: "structured log",
+			log: `{"@level":"info","@message":"Terraform 1.6.0","type":"version"}
+{"@level":"error","@message":"Error: Unsupported argument","type":"diagnostic","diagnostic":{"severity":"error","summary":"Unsupported argument","detail":"An argument named \"acl_typo\" is not expected here.","range":{"filename":"main.tf","start":{"line":3}},"snippet":{"context":"resource \"aws_s3_bucket\" \"logs\"","code":"  acl_typo = \"private\"","start_line":3}}}
+{"@level":"warn","@message":"Warning: Deprecated attribute","type":"diagnostic","diagnostic":{"severity":"warning","summary":"Deprecated attribute"}}
+`,
+			want: []string{
+				"Error: Unsupported argument\n\n  on main.tf line 3, in resource \"aws_s3_bucket\" \"logs\":\n  3:   acl_typo = \"private\"\n\nAn argument named \"acl_typo\" is not expected here.",
+			},
+		},
+		{
+			name: "no errors",
+			log:  "Plan: 1 to add, 0 to change, 0 to destroy.\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ErrorDiagnostics([]byte(tt.log)); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ErrorDiagnostics() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReadLogTail(t *testing.T) {
+	log := strings.Repeat("plan output\n", 10000) + "Error: boom\n"
+	tests := []struct {
+		name  string
+		limit int
+		want  string
+	}{
+		{name: "shorter than limit", limit: len(log) + 1, want: log},
+		{name: "longer than limit", limit: 12, want: "Error: boom\n"},
+		{name: "window doubled", limit: 1000, want: log[len(log)-1000:]},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// read in small pieces to exercise the window
+			got, err := readLogTail(iotest.HalfReader(bytes.NewBufferString(log)), tt.limit)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("readLogTail() returned %d bytes ending in %q, want %d bytes", len(got), got[max(0, len(got)-20):], len(tt.want))
+			}
+		})
+	}
+}
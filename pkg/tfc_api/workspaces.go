@@ -0,0 +1,150 @@
+package tfc_api
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/go-tfe"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type ApiWorkspaceOptions struct {
+	// Organization is the Terraform Cloud organization name
+	Organization string
+	// Name is the Terraform Cloud workspace name
+	Name string
+	// Project is the name of the project of the workspace, the organization's
+	// default project if empty
+	Project string
+	// Terraform Version, the latest version if empty
+	TerraformVersion string
+	// ExecutionMode is remote, local or agent
+	ExecutionMode string
+	// AgentPoolID is the agent pool of workspaces executed by agents
+	AgentPoolID string
+	// WorkingDirectory is the directory Terraform runs in
+	WorkingDirectory string
+	// Tags of the workspace
+	Tags []string
+	// VariableSets are the names of the variable sets attached to the workspace
+	VariableSets []string
+}
+
+// MissingVariableSetsError lists the variable sets requested for a new
+// workspace that do not exist in its organization.
+type MissingVariableSetsError struct {
+	Organization string
+	Names        []string
+}
+
+func (e *MissingVariableSetsError) Error() string {
+	return fmt.Sprintf("variable sets %q do not exist in organization %s", strings.Join(e.Names, `", "`), e.Organization)
+}
+
+// CreateWorkspace creates a workspace and attaches its variable sets. The
+// project and variable sets are looked up first, so that a typo does not leave
+// a half configured workspace behind, and the workspace is deleted again when
+// a variable set cannot be attached.
+func (t *TFCClient) CreateWorkspace(ctx context.Context, opts *ApiWorkspaceOptions) (*tfe.Workspace, error) {
+	ctx, span := otel.Tracer("TFC").Start(ctx, "CreateWorkspace", trace.WithAttributes(
+		attribute.String("org", opts.Organization),
+		attribute.String("name", opts.Name),
+	))
+	defer span.End()
+
+	createOpts := tfe.WorkspaceCreateOptions{Name: tfe.String(opts.Name)}
+	if opts.Project != "" {
+		project, err := t.findProject(ctx, opts.Organization, opts.Project)
+		if err != nil {
+			return nil, err
+		}
+		createOpts.Project = project
+	}
+	var variableSets []*tfe.VariableSet
+	var missing []string
+	for _, name := range opts.VariableSets {
+		vs, err := t.findVariableSet(ctx, opts.Organization, name)
+		if err != nil {
+			return nil, err
+		}
+		if vs == nil {
+			missing = append(missing, name)
+			continue
+		}
+		variableSets = append(variableSets, vs)
+	}
+	if len(missing) > 0 {
+		return nil, &MissingVariableSetsError{Organization: opts.Organization, Names: missing}
+	}
+	if opts.TerraformVersion != "" {
+		createOpts.TerraformVersion = tfe.String(opts.TerraformVersion)
+	}
+	if opts.ExecutionMode != "" {
+		createOpts.ExecutionMode = tfe.String(opts.ExecutionMode)
+	}
+	if opts.AgentPoolID != "" {
+		createOpts.AgentPoolID = tfe.String(opts.AgentPoolID)
+	}
+	if opts.WorkingDirectory != "" {
+		createOpts.WorkingDirectory = tfe.String(opts.WorkingDirectory)
+	}
+	for _, tag := range opts.Tags {
+		createOpts.Tags = append(createOpts.Tags, &tfe.Tag{Name: tag})
+	}
+
+	ws, err := t.Client.Workspaces.Create(ctx, opts.Organization, createOpts)
+	if err != nil {
+		return nil, fmt.Errorf("could not create workspace %s/%s. %w", opts.Organization, opts.Name, err)
+	}
+	for _, vs := range variableSets {
+		err := t.Client.VariableSets.ApplyToWorkspaces(ctx, vs.ID, &tfe.VariableSetApplyToWorkspacesOptions{
+			Workspaces: []*tfe.Workspace{ws},
+		})
+		if err != nil {
+			err = fmt.Errorf("could not attach variable set %s to workspace %s/%s. %w", vs.Name, opts.Organization, opts.Name, err)
+			if delErr := t.Client.Workspaces.DeleteByID(ctx, ws.ID); delErr != nil {
+				return nil, fmt.Errorf("%w. The workspace could not be deleted either and must be removed by hand. %v", err, delErr)
+			}
+			return nil, err
+		}
+	}
+	return ws, nil
+}
+
+func (t *TFCClient) findProject(ctx context.Context, org, name string) (*tfe.Project, error) {
+	list, err := t.Client.Projects.List(ctx, org, &tfe.ProjectListOptions{Name: name})
+	if err != nil {
+		return nil, fmt.Errorf("could not list projects of %s. %w", org, err)
+	}
+	for _, p := range list.Items {
+		if p.Name == name {
+			return p, nil
+		}
+	}
+	return nil, fmt.Errorf("project %q does not exist in organization %s", name, org)
+}
+
+// findVariableSet returns the variable set of org named name, or nil when
+// there is none.
+func (t *TFCClient) findVariableSet(ctx context.Context, org, name string) (*tfe.VariableSet, error) {
+	// the query matches partial names, so look for the exact name in every page
+	opts := &tfe.VariableSetListOptions{ListOptions: tfe.ListOptions{PageSize: 100}, Query: name}
+	for {
+		list, err := t.Client.VariableSets.List(ctx, org, opts)
+		if err != nil {
+			return nil, fmt.Errorf("could not list variable sets of %s. %w", org, err)
+		}
+		for _, vs := range list.Items {
+			if vs.Name == name {
+				return vs, nil
+			}
+		}
+		if list.Pagination == nil || list.NextPage == 0 {
+			return nil, nil
+		}
+		opts.PageNumber = list.NextPage
+	}
+}
@@ -0,0 +1,114 @@
+package tfc_api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/go-tfe"
+)
+
+func TestCreateWorkspace(t *testing.T) {
+	tests := []struct {
+		name         string
+		variableSets []string
+		attachFails  bool
+		wantErr      bool
+		wantMissing  []string
+		wantDeleted  bool
+	}{
+		{name: "created", variableSets: []string{"aws-credentials"}},
+		{name: "unknown variable sets", variableSets: []string{"aws", "aws-credentials", "gcp"}, wantErr: true, wantMissing: []string{"aws", "gcp"}},
+		{name: "attach fails", variableSets: []string{"aws-credentials"}, attachFails: true, wantErr: true, wantDeleted: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var created map[string]any
+			var appliedTo string
+			var deleted bool
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/vnd.api+json")
+				switch {
+				case r.URL.Path == "/api/v2/ping":
+					w.WriteHeader(http.StatusNoContent)
+				case r.Method == http.MethodGet && r.URL.Path == "/api/v2/organizations/zapier/projects":
+					fmt.Fprint(w, `{"data":[{"id":"prj-1","type":"projects","attributes":{"name":"services"}}],"meta":{"pagination":{"current-page":1,"total-pages":1}}}`)
+				case r.Method == http.MethodGet && r.URL.Path == "/api/v2/organizations/zapier/varsets":
+					// partial matches are returned too
+					fmt.Fprint(w, `{"data":[{"id":"varset-1","type":"varsets","attributes":{"name":"aws-credentials"}}],"meta":{"pagination":{"current-page":1,"total-pages":1}}}`)
+				case r.Method == http.MethodPost && r.URL.Path == "/api/v2/organizations/zapier/workspaces":
+					b, _ := io.ReadAll(r.Body)
+					json.Unmarshal(b, &created)
+					w.WriteHeader(http.StatusCreated)
+					fmt.Fprint(w, `{"data":{"id":"ws-1","type":"workspaces","attributes":{"name":"infra"}}}`)
+				case r.Method == http.MethodPost && r.URL.Path == "/api/v2/varsets/varset-1/relationships/workspaces":
+					if tt.attachFails {
+						w.WriteHeader(http.StatusInternalServerError)
+						return
+					}
+					b, _ := io.ReadAll(r.Body)
+					appliedTo = string(b)
+					w.WriteHeader(http.StatusNoContent)
+				case r.Method == http.MethodDelete && r.URL.Path == "/api/v2/workspaces/ws-1":
+					deleted = true
+					w.WriteHeader(http.StatusNoContent)
+				default:
+					http.NotFound(w, r)
+				}
+			}))
+			defer srv.Close()
+			client, err := tfe.NewClient(&tfe.Config{Address: srv.URL, Token: "token", HTTPClient: srv.Client()})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			ws, err := (&TFCClient{Client: client}).CreateWorkspace(context.Background(), &ApiWorkspaceOptions{
+				Organization:  "zapier",
+				Name:          "infra",
+				Project:       "services",
+				ExecutionMode: "remote",
+				Tags:          []string{"team:sre"},
+				VariableSets:  tt.variableSets,
+			})
+			if tt.wantErr {
+				if err == nil || ws != nil {
+					t.Fatalf("expected no workspace, got %v, %v", ws, err)
+				}
+				var missing *MissingVariableSetsError
+				if errors.As(err, &missing) != (tt.wantMissing != nil) || (missing != nil && !reflect.DeepEqual(missing.Names, tt.wantMissing)) {
+					t.Errorf("unexpected error %v", err)
+				}
+				if tt.wantMissing != nil && created != nil {
+					t.Error("expected the workspace not to be created")
+				}
+				if deleted != tt.wantDeleted {
+					t.Errorf("expected deleted=%v, got %v", tt.wantDeleted, deleted)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if ws.ID != "ws-1" {
+				t.Errorf("unexpected workspace %s", ws.ID)
+			}
+			data := created["data"].(map[string]any)
+			if attrs := data["attributes"].(map[string]any); attrs["name"] != "infra" || attrs["execution-mode"] != "remote" {
+				t.Errorf("unexpected attributes %v", attrs)
+			}
+			project := data["relationships"].(map[string]any)["project"].(map[string]any)["data"].(map[string]any)
+			if project["id"] != "prj-1" {
+				t.Errorf("unexpected project %v", project)
+			}
+			if appliedTo == "" {
+				t.Error("expected the variable set to be attached")
+			}
+		})
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
 	"time"
 
@@ -12,6 +13,7 @@ import (
 	"github.com/labstack/echo/v4"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/rs/zerolog/log"
+	"github.com/zapier/tfbuddy/internal/config"
 	"github.com/zapier/tfbuddy/pkg/runstream"
 	"github.com/zapier/tfbuddy/pkg/tfc_api"
 	"go.opentelemetry.io/otel"
@@ -39,24 +41,54 @@ var (
 		Name: "tfbuddy_tfc_notifications_failed",
 		Help: "Count of all TFC Notifications that could not be processed",
 	}, commonLabels)
+	tfcNotificationsRejected = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "tfbuddy_tfc_notifications_rejected",
+		Help: "Count of all TFC notification webhooks rejected because of their signature",
+	},
+		[]string{
+			"reason",
+		},
+	)
 )
 
+// notificationSignatureHeader holds the HMAC-SHA512 of notification payloads.
+const notificationSignatureHeader = "X-Tfe-Notification-Signature"
+
+// notificationTriggerVerification is the trigger of the payload TFC sends to
+// check a notification configuration.
+const notificationTriggerVerification = "verification"
+
 func init() {
 	r := prometheus.DefaultRegisterer
 	r.MustRegister(tfcNotificationsReceived)
 	r.MustRegister(tfcNotificationPublishSuccess)
 	r.MustRegister(tfcNotificationPublishFailed)
+	r.MustRegister(tfcNotificationsRejected)
 }
 
 type NotificationHandler struct {
 	api    tfc_api.ApiClient
 	stream runstream.StreamClient
+	// tokens are the tokens of the notification configurations, any of them
+	// may sign a notification.
+	tokens []string
+	// allowUnsigned accepts every notification when no token is configured.
+	allowUnsigned bool
 }
 
-func NewNotificationHandler(api tfc_api.ApiClient, stream runstream.StreamClient) *NotificationHandler {
+func NewNotificationHandler(cfg config.Config, api tfc_api.ApiClient, stream runstream.StreamClient) *NotificationHandler {
 	h := &NotificationHandler{
-		api:    api,
-		stream: stream,
+		api:           api,
+		stream:        stream,
+		tokens:        cfg.TFCNotificationTokens,
+		allowUnsigned: cfg.TFCNotificationUnsigned,
+	}
+	if len(h.tokens) == 0 {
+		if h.allowUnsigned {
+			log.Warn().Msgf("%s is not set and %s is enabled, TFC notifications are not verified", config.KeyTFCNotificationTokens, config.KeyTFCNotificationUnsigned)
+		} else {
+			log.Warn().Msgf("%s is not set, all TFC notifications are rejected", config.KeyTFCNotificationTokens)
+		}
 	}
 	// subscribe to Run Polling Tasks queue
 	_, err := stream.SubscribeTFRunPollingTasks(h.pollingStreamCallback)
@@ -75,18 +107,32 @@ func (h *NotificationHandler) Handler() func(c echo.Context) error {
 		labels := prometheus.Labels{
 			"status": "processed",
 		}
-		event := NotificationPayload{}
+		body, err := io.ReadAll(c.Request().Body)
+		if err != nil {
+			return err
+		}
+		if reason := h.verify(c.Request().Header.Get(notificationSignatureHeader), body); reason != "" {
+			log.Warn().Str("reason", reason).Msg("rejecting TFC notification")
+			tfcNotificationsRejected.With(prometheus.Labels{"reason": reason}).Inc()
+			return c.String(http.StatusUnauthorized, "invalid signature")
+		}
 
-		if err := (&echo.DefaultBinder{}).BindBody(c, &event); err != nil {
+		event := NotificationPayload{}
+		if err := json.Unmarshal(body, &event); err != nil {
 			log.Error().Err(err).Msg("failed to unmarshall event payload")
 			labels["status"] = "error"
 			tfcNotificationsReceived.With(labels).Inc()
-			return err
+			return c.String(http.StatusBadRequest, "invalid payload")
 		}
 		log.Debug().Str("event", pretty.Sprint(event))
 
-		// do something with event
-		h.processNotification(ctx, &event)
+		if event.isVerification() {
+			log.Info().Str("notification_configuration_id", event.NotificationConfigurationId).
+				Msg("received TFC notification verification")
+			labels["status"] = notificationTriggerVerification
+		} else {
+			h.processNotification(ctx, &event)
+		}
 
 		tfcNotificationsReceived.With(labels).Inc()
 		return c.String(http.StatusOK, "OK")
@@ -113,12 +159,36 @@ type NotificationPayload struct {
 	} `json:"notifications"`
 }
 
+// verify returns why a notification signed with signature should be rejected,
+// or an empty string when it is signed with one of the tokens.
+func (h *NotificationHandler) verify(signature string, body []byte) string {
+	if len(h.tokens) == 0 {
+		if h.allowUnsigned {
+			return ""
+		}
+		return "no-tokens-configured"
+	}
+	if signature == "" {
+		return "missing-signature"
+	}
+	for _, token := range h.tokens {
+		if verifySignature(token, signature, body) {
+			return ""
+		}
+	}
+	return "invalid-signature"
+}
+
+func (p *NotificationPayload) isVerification() bool {
+	return len(p.Notifications) > 0 && p.Notifications[0].Trigger == notificationTriggerVerification
+}
+
 func (h *NotificationHandler) processNotification(ctx context.Context, n *NotificationPayload) {
 	ctx, span := otel.Tracer("TFBuddy").Start(ctx, "ProcessNotification")
 	defer span.End()
 
 	log.Debug().Interface("NotificationPayload", *n).Msg("processNotification()")
-	if n.RunId == "" {
+	if n.RunId == "" || len(n.Notifications) == 0 {
 		return
 	}
 	run, err := h.api.GetRun(ctx, n.RunId)
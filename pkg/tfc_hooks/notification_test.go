@@ -2,11 +2,20 @@ package tfc_hooks
 
 import (
 	"context"
+	"crypto/hmac"
+	"crypto/sha512"
+	"encoding/hex"
 	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/hashicorp/go-tfe"
+	"github.com/labstack/echo/v4"
+	"github.com/zapier/tfbuddy/pkg/mocks"
 	"github.com/zapier/tfbuddy/pkg/tfc_api"
+	"go.uber.org/mock/gomock"
 )
 
 //
@@ -280,3 +289,56 @@ const runData = `{
         "self": "/api/v2/runs/run-bWSq4YeYpfrW4mx7"
     }
 }`
+
+// signSHA512 returns the hex encoded HMAC-SHA512 of body, as TFC signs it.
+func signSHA512(key, body string) string {
+	mac := hmac.New(sha512.New, []byte(key))
+	mac.Write([]byte(body))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func TestNotificationHandler(t *testing.T) {
+	tests := []struct {
+		name          string
+		tokens        []string
+		allowUnsigned bool
+		payload       string
+		signature     string
+		want          int
+		wantPublish   bool
+	}{
+		{name: "signed", tokens: []string{"token"}, payload: NotificationRunPlanningPayload, signature: signSHA512("token", NotificationRunPlanningPayload), want: http.StatusOK, wantPublish: true},
+		{name: "signed with rotated token", tokens: []string{"old", "new"}, payload: NotificationRunPlanningPayload, signature: signSHA512("new", NotificationRunPlanningPayload), want: http.StatusOK, wantPublish: true},
+		{name: "wrong token", tokens: []string{"token"}, payload: NotificationRunPlanningPayload, signature: signSHA512("other", NotificationRunPlanningPayload), want: http.StatusUnauthorized},
+		{name: "unsigned", tokens: []string{"token"}, payload: NotificationRunPlanningPayload, want: http.StatusUnauthorized},
+		{name: "verification", tokens: []string{"token"}, payload: NotificationVerificationPayload, signature: signSHA512("token", NotificationVerificationPayload), want: http.StatusOK},
+		{name: "no tokens configured", payload: NotificationRunPlanningPayload, want: http.StatusUnauthorized},
+		{name: "no tokens configured, unsigned allowed", allowUnsigned: true, payload: NotificationRunPlanningPayload, want: http.StatusOK, wantPublish: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			api := mocks.NewMockApiClient(mockCtrl)
+			stream := mocks.NewMockStreamClient(mockCtrl)
+			if tt.wantPublish {
+				api.EXPECT().GetRun(gomock.Any(), "run-Vy7sSoyhizTafW8f").Return(&tfe.Run{ID: "run-Vy7sSoyhizTafW8f"}, nil)
+				stream.EXPECT().PublishTFRunEvent(gomock.Any(), gomock.Any()).Return(nil)
+			}
+			h := &NotificationHandler{api: api, stream: stream, tokens: tt.tokens, allowUnsigned: tt.allowUnsigned}
+
+			req := httptest.NewRequest(http.MethodPost, "/hooks/tfc/notification", strings.NewReader(tt.payload))
+			if tt.signature != "" {
+				req.Header.Set(notificationSignatureHeader, tt.signature)
+			}
+			rec := httptest.NewRecorder()
+			if err := h.Handler()(echo.New().NewContext(req, rec)); err != nil {
+				t.Fatal(err)
+			}
+			if rec.Code != tt.want {
+				t.Errorf("status = %d, want %d", rec.Code, tt.want)
+			}
+		})
+	}
+}
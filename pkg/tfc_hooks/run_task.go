@@ -1,59 +1,222 @@
 package tfc_hooks
 
 import (
-	"github.com/kr/pretty"
+	"context"
+	"crypto/hmac"
+	"crypto/sha512"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/cenkalti/backoff/v4"
+	"github.com/hashicorp/go-tfe"
 	"github.com/labstack/echo/v4"
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/rs/zerolog/log"
-	"net/http"
-	"time"
+	"github.com/zapier/tfbuddy/internal/config"
+	"github.com/zapier/tfbuddy/pkg/runstream"
+	"github.com/zapier/tfbuddy/pkg/tfc_api"
+	"github.com/zapier/tfbuddy/pkg/tfc_trigger"
+	"github.com/zapier/tfbuddy/pkg/vcs"
+	"go.opentelemetry.io/otel"
 )
 
-func RunTaskHandler(c echo.Context) error {
-	event := RunTaskEvent{}
+// runTaskSignatureHeader holds the HMAC-SHA512 of run task requests.
+const runTaskSignatureHeader = "X-Tfc-Task-Signature"
+
+// runTaskTestToken is the access token TFC sends when a run task is created or
+// updated, to check that the endpoint is reachable.
+const runTaskTestToken = "test-token"
+
+// runMetadataBackOff paces the lookups of the metadata of a run.
+var runMetadataBackOff = func() backoff.BackOff {
+	return backoff.WithMaxRetries(backoff.NewExponentialBackOff(), 3)
+}
+
+var tfcRunTasksProcessed = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Name: "tfbuddy_tfc_run_tasks_processed",
+	Help: "Count of all TFC run task requests processed, by outcome",
+}, commonLabels)
+
+func init() {
+	prometheus.DefaultRegisterer.MustRegister(tfcRunTasksProcessed)
+}
+
+// RunTaskHandler makes TFBuddy a TFC run task. It enforces the merge request
+// workspace locks of TFBuddy on runs, whoever created them.
+type RunTaskHandler struct {
+	cfg    config.Config
+	api    tfc_api.ApiClient
+	stream runstream.StreamClient
+	// vcs are the VCS clients by provider name, used to report failed runs.
+	vcs map[string]vcs.GitClient
+	// callback sends the result to TFC, it is replaced in tests.
+	callback func(ctx context.Context, req *tfe.RunTaskRequest, opts tfe.TaskResultCallbackRequestOptions) error
+}
+
+func NewRunTaskHandler(cfg config.Config, api tfc_api.ApiClient, stream runstream.StreamClient, vcsClients map[string]vcs.GitClient) *RunTaskHandler {
+	h := &RunTaskHandler{
+		cfg:    cfg,
+		api:    api,
+		stream: stream,
+		vcs:    vcsClients,
+	}
+	h.callback = h.sendCallback
+	if cfg.TFCRunTaskHMACKey == "" {
+		log.Warn().Msgf("%s is not set, all TFC run task requests are rejected", config.KeyTFCRunTaskHMACKey)
+	}
+	return h
+}
+
+func (h *RunTaskHandler) Handler() func(c echo.Context) error {
+	return func(c echo.Context) error {
+		ctx, span := otel.Tracer("TFBuddy").Start(c.Request().Context(), "RunTaskHandler")
+		defer span.End()
+
+		body, err := io.ReadAll(c.Request().Body)
+		if err != nil {
+			return err
+		}
+		if !verifySignature(h.cfg.TFCRunTaskHMACKey, c.Request().Header.Get(runTaskSignatureHeader), body) {
+			log.Warn().Msg("rejecting run task request with an invalid signature")
+			return c.String(http.StatusUnauthorized, "invalid signature")
+		}
+		req := &tfe.RunTaskRequest{}
+		if err := json.Unmarshal(body, req); err != nil {
+			log.Error().Err(err).Msg("failed to unmarshall event payload")
+			return c.String(http.StatusBadRequest, "invalid payload")
+		}
+		if req.AccessToken == runTaskTestToken {
+			log.Info().Msg("received run task verification request")
+			return c.String(http.StatusOK, "OK")
+		}
+
+		// TFC expects an answer within 10 seconds, the result is sent to the
+		// callback URL once the run has been checked.
+		go h.processRunTask(context.WithoutCancel(ctx), req)
+		return c.String(http.StatusOK, "OK")
+	}
+}
+
+// verifySignature reports whether signature is the hex encoded HMAC-SHA512 of
+// body. Nothing is signed by an empty key.
+func verifySignature(key, signature string, body []byte) bool {
+	if key == "" {
+		return false
+	}
+	got, err := hex.DecodeString(signature)
+	if err != nil {
+		return false
+	}
+	mac := hmac.New(sha512.New, []byte(key))
+	mac.Write(body)
+	return hmac.Equal(got, mac.Sum(nil))
+}
+
+func (h *RunTaskHandler) processRunTask(ctx context.Context, req *tfe.RunTaskRequest) {
+	ctx, span := otel.Tracer("TFBuddy").Start(ctx, "ProcessRunTask")
+	defer span.End()
+
+	log := log.With().Str("runID", req.RunID).Str("workspace", req.WorkspaceName).Str("stage", req.Stage).Logger()
+	md := h.runMetadata(req.RunID)
+	status, message := h.evaluate(ctx, req, md)
+	log.Info().Str("status", string(status)).Msg(message)
+	tfcRunTasksProcessed.With(prometheus.Labels{
+		"organization": req.OrganizationName,
+		"workspace":    req.WorkspaceName,
+		"status":       string(status),
+	}).Inc()
+
+	if err := h.callback(ctx, req, tfe.TaskResultCallbackRequestOptions{Status: status, Message: message}); err != nil {
+		span.RecordError(err)
+		log.Error().Err(err).Msg("could not send run task result to TFC")
+	}
+	if status != tfe.TaskFailed || md == nil {
+		return
+	}
+	gl, ok := h.vcs[md.GetVcsProvider()]
+	if !ok {
+		log.Warn().Str("vcs", md.GetVcsProvider()).Msg("no VCS client to report run task result")
+		return
+	}
+	comment := fmt.Sprintf(":no_entry: TFC run [%s](%s) on workspace `%s/%s` was stopped by the TFBuddy run task: %s",
+		req.RunID, req.RunAppURL, req.OrganizationName, req.WorkspaceName, message)
+	if err := gl.CreateMergeRequestComment(ctx, md.GetMRInternalID(), md.GetMRProjectNameWithNamespace(), comment); err != nil {
+		log.Error().Err(err).Msg("could not post run task result to merge request")
+	}
+}
+
+// runMetadata returns the metadata TFBuddy stored for the run, or nil for runs
+// TFBuddy did not create.
+func (h *RunTaskHandler) runMetadata(runID string) runstream.RunMetadata {
+	// the run task may be called before TFBuddy stored the metadata of a run
+	// it has just created
+	md, err := backoff.RetryWithData(func() (runstream.RunMetadata, error) {
+		return h.stream.GetRunMeta(runID)
+	}, runMetadataBackOff())
+	if err != nil {
+		return nil
+	}
+	return md
+}
 
-	if err := (&echo.DefaultBinder{}).BindBody(c, &event); err != nil {
-		log.Error().Err(err).Msg("failed to unmarshall event payload")
+// evaluate checks the run against the policies of TFBuddy: runs that can apply
+// must come from the merge request locking the workspace, if any, and runs
+// not created by TFBuddy are refused when configured.
+func (h *RunTaskHandler) evaluate(ctx context.Context, req *tfe.RunTaskRequest, md runstream.RunMetadata) (tfe.TaskResultStatus, string) {
+	if md == nil && h.cfg.TFCRunTaskRejectExternal {
+		return tfe.TaskFailed, "the run was not created by TFBuddy, runs of this workspace must be started from a merge request"
+	}
+	if !req.IsSpeculative {
+		tags, err := h.api.GetTagsByQuery(ctx, req.WorkspaceID, tfc_trigger.LockTagPrefix)
+		if err != nil {
+			return tfe.TaskFailed, fmt.Sprintf("could not read the locks of the workspace: %s", err)
+		}
+		thisMR := ""
+		if md != nil {
+			thisMR = strconv.Itoa(md.GetMRInternalID())
+		}
+		if lockingMR := tfc_trigger.FindLockingMR(ctx, tags, thisMR); lockingMR != "" {
+			return tfe.TaskFailed, fmt.Sprintf("the workspace is locked by merge request %s", lockingMR)
+		}
+	}
+	if md == nil {
+		return tfe.TaskPassed, "the run was not created by TFBuddy, and the workspace is not locked by a merge request"
+	}
+	return tfe.TaskPassed, fmt.Sprintf("the run was created by TFBuddy for merge request %d of %s", md.GetMRInternalID(), md.GetMRProjectNameWithNamespace())
+}
+
+// sendCallback sends the run task result to TFC, using the access token of
+// the request.
+func (h *RunTaskHandler) sendCallback(ctx context.Context, req *tfe.RunTaskRequest, opts tfe.TaskResultCallbackRequestOptions) error {
+	// never send the access token anywhere but to TFC
+	if !sameOrigin(req.TaskResultCallbackURL, tfc_api.Address(h.cfg)) {
+		return fmt.Errorf("callback url %q is not on %s", req.TaskResultCallbackURL, tfc_api.Address(h.cfg))
+	}
+	tfeConfig, err := tfc_api.NewTFEConfig(h.cfg, req.AccessToken)
+	if err != nil {
 		return err
 	}
+	client, err := tfe.NewClient(tfeConfig)
+	if err != nil {
+		return err
+	}
+	return client.RunTasksIntegration.Callback(ctx, req.TaskResultCallbackURL, req.AccessToken, opts)
+}
 
-	log.Debug().Str("event", pretty.Sprint(event))
-
-	// do something with event
-
-	// send ack to TFC RunAppUrl
-
-	return c.String(http.StatusOK, "OK")
-}
-
-type RunTaskEvent struct {
-	PayloadVersion             int         `json:"payload_version"`
-	AccessToken                string      `json:"access_token"`
-	TaskResultId               string      `json:"task_result_id"`
-	TaskResultEnforcementLevel string      `json:"task_result_enforcement_level"`
-	TaskResultCallbackUrl      string      `json:"task_result_callback_url"`
-	RunAppUrl                  string      `json:"run_app_url"`
-	RunId                      string      `json:"run_id"`
-	RunMessage                 string      `json:"run_message"`
-	RunCreatedAt               time.Time   `json:"run_created_at"`
-	RunCreatedBy               string      `json:"run_created_by"`
-	WorkspaceId                string      `json:"workspace_id"`
-	WorkspaceName              string      `json:"workspace_name"`
-	WorkspaceAppUrl            string      `json:"workspace_app_url"`
-	OrganizationName           string      `json:"organization_name"`
-	PlanJsonApiUrl             string      `json:"plan_json_api_url"`
-	VcsRepoUrl                 string      `json:"vcs_repo_url"`
-	VcsBranch                  string      `json:"vcs_branch"`
-	VcsPullRequestUrl          interface{} `json:"vcs_pull_request_url"`
-	VcsCommitUrl               string      `json:"vcs_commit_url"`
-}
-
-type RunTaskCallback struct {
-	Data struct {
-		Type       string `json:"type"`
-		Attributes struct {
-			Status  string `json:"status"`
-			Message string `json:"message"`
-			Url     string `json:"url"`
-		} `json:"attributes"`
-	} `json:"data"`
+func sameOrigin(rawURL, address string) bool {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return false
+	}
+	a, err := url.Parse(address)
+	if err != nil {
+		return false
+	}
+	return strings.EqualFold(u.Scheme, a.Scheme) && strings.EqualFold(u.Host, a.Host)
 }
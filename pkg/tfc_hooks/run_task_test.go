@@ -0,0 +1,135 @@
+package tfc_hooks
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/cenkalti/backoff/v4"
+	"github.com/hashicorp/go-tfe"
+	"github.com/labstack/echo/v4"
+	"github.com/zapier/tfbuddy/internal/config"
+	"github.com/zapier/tfbuddy/pkg/mocks"
+	"github.com/zapier/tfbuddy/pkg/runstream"
+	"github.com/zapier/tfbuddy/pkg/vcs"
+	"go.uber.org/mock/gomock"
+)
+
+func init() {
+	runMetadataBackOff = func() backoff.BackOff { return &backoff.StopBackOff{} }
+}
+
+func TestProcessRunTask(t *testing.T) {
+	mrMetadata := func(iid int) runstream.RunMetadata {
+		return &runstream.TFRunMetadata{
+			RunID:                                "run-1",
+			MergeRequestIID:                      iid,
+			MergeRequestProjectNameWithNamespace: "zapier/infra",
+			VcsProvider:                          "gitlab",
+		}
+	}
+	tests := []struct {
+		name          string
+		md            runstream.RunMetadata
+		speculative   bool
+		rejectExt     bool
+		tags          []string
+		wantStatus    tfe.TaskResultStatus
+		wantMRComment bool
+	}{
+		{name: "locking merge request", md: mrMetadata(7), tags: []string{"tfbuddylock-7"}, wantStatus: tfe.TaskPassed},
+		{name: "other merge request", md: mrMetadata(5), tags: []string{"tfbuddylock-7"}, wantStatus: tfe.TaskFailed, wantMRComment: true},
+		{name: "plan of other merge request", md: mrMetadata(5), speculative: true, wantStatus: tfe.TaskPassed},
+		{name: "external run", wantStatus: tfe.TaskPassed},
+		{name: "external run on locked workspace", tags: []string{"tfbuddylock-7"}, wantStatus: tfe.TaskFailed},
+		{name: "external runs rejected", rejectExt: true, wantStatus: tfe.TaskFailed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			stream := mocks.NewMockStreamClient(mockCtrl)
+			if tt.md != nil {
+				stream.EXPECT().GetRunMeta("run-1").Return(tt.md, nil)
+			} else {
+				stream.EXPECT().GetRunMeta("run-1").Return(nil, errors.New("nats: key not found"))
+			}
+			api := mocks.NewMockApiClient(mockCtrl)
+			api.EXPECT().GetTagsByQuery(gomock.Any(), "ws-1", "tfbuddylock").Return(tt.tags, nil).AnyTimes()
+			gl := mocks.NewMockGitClient(mockCtrl)
+			if tt.wantMRComment {
+				gl.EXPECT().CreateMergeRequestComment(gomock.Any(), 5, "zapier/infra", gomock.Any()).
+					DoAndReturn(func(_ context.Context, _ int, _ string, comment string) error {
+						if !strings.Contains(comment, "locked by merge request 7") {
+							t.Errorf("unexpected comment %q", comment)
+						}
+						return nil
+					})
+			}
+
+			h := NewRunTaskHandler(config.Config{TFCRunTaskRejectExternal: tt.rejectExt}, api, stream, map[string]vcs.GitClient{"gitlab": gl})
+			var got *tfe.TaskResultCallbackRequestOptions
+			h.callback = func(_ context.Context, _ *tfe.RunTaskRequest, opts tfe.TaskResultCallbackRequestOptions) error {
+				got = &opts
+				return nil
+			}
+			h.processRunTask(context.Background(), &tfe.RunTaskRequest{
+				RunID:         "run-1",
+				WorkspaceID:   "ws-1",
+				WorkspaceName: "infra",
+				IsSpeculative: tt.speculative,
+			})
+			if got == nil {
+				t.Fatal("expected a callback")
+			}
+			if got.Status != tt.wantStatus {
+				t.Errorf("status = %s (%s), want %s", got.Status, got.Message, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func TestRunTaskHandler(t *testing.T) {
+	verification := `{"payload_version":1,"access_token":"test-token","task_result_callback_url":"https://app.terraform.io/api/v2/task-results/tr-1/callback"}`
+	tests := []struct {
+		name      string
+		key       string
+		signature string
+		want      int
+	}{
+		{name: "valid signature", key: "secret", signature: signSHA512("secret", verification), want: http.StatusOK},
+		{name: "invalid signature", key: "secret", signature: signSHA512("secret", "other"), want: http.StatusUnauthorized},
+		{name: "missing signature", key: "secret", want: http.StatusUnauthorized},
+		{name: "no key configured", signature: signSHA512("", verification), want: http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// verification requests are answered without checking any run
+			h := NewRunTaskHandler(config.Config{TFCRunTaskHMACKey: tt.key}, nil, nil, nil)
+			req := httptest.NewRequest(http.MethodPost, "/hooks/tfc/run_task", strings.NewReader(verification))
+			req.Header.Set(runTaskSignatureHeader, tt.signature)
+			rec := httptest.NewRecorder()
+			if err := h.Handler()(echo.New().NewContext(req, rec)); err != nil {
+				t.Fatal(err)
+			}
+			if rec.Code != tt.want {
+				t.Errorf("status = %d, want %d", rec.Code, tt.want)
+			}
+		})
+	}
+}
+
+func TestSendCallback_OtherHost(t *testing.T) {
+	h := NewRunTaskHandler(config.Config{}, nil, nil, nil)
+	err := h.sendCallback(context.Background(), &tfe.RunTaskRequest{
+		AccessToken:           "token",
+		TaskResultCallbackURL: "https://attacker.example.com/callback",
+	}, tfe.TaskResultCallbackRequestOptions{Status: tfe.TaskPassed})
+	if err == nil {
+		t.Error("expected the access token not to be sent outside TFC")
+	}
+}
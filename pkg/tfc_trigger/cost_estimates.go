@@ -0,0 +1,55 @@
+package tfc_trigger
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/go-tfe"
+	"github.com/rs/zerolog/log"
+	"github.com/zapier/tfbuddy/pkg/tfc_api"
+)
+
+// checkCostDelta refuses to apply ws when the latest plan of the commit being
+// applied raises the monthly cost by more than `tfc-cost-delta-threshold`,
+// or when that plan or its cost estimate is missing, unless the comment passed
+// --accept-cost.
+func (t *TFCTrigger) checkCostDelta(ctx context.Context, ws *tfe.Workspace) error {
+	threshold := t.appCfg.TFCCostDeltaThreshold
+	if threshold <= 0 || t.cfg.AcceptCost {
+		return nil
+	}
+	runs, err := t.tfc.ListSpeculativeRuns(ctx, ws.ID, runMessagePrefix(t.GetMergeRequestIID()))
+	if err != nil {
+		return fmt.Errorf("could not get the cost estimate of the latest plan. %w", err)
+	}
+	run := t.latestPlanOfCommit(runs)
+	if run == nil {
+		log.Debug().Str("workspace", ws.Name).Str("commit", t.GetCommitSHA()).Msg("no plan found to check the cost estimate of")
+		return fmt.Errorf("no plan of commit %s was found to check its cost estimate against the $%d threshold. Comment `tfc apply --accept-cost` to apply anyway", t.GetCommitSHA(), threshold)
+	}
+	delta, ok := tfc_api.MonthlyCostDelta(run.CostEstimate)
+	if !ok {
+		return fmt.Errorf("the cost estimate of plan %s is not available, it cannot be checked against the $%d threshold. Comment `tfc apply --accept-cost` to apply anyway", run.ID, threshold)
+	}
+	if delta <= float64(threshold) {
+		return nil
+	}
+	return fmt.Errorf("the latest plan raises the monthly cost by $%.2f, more than the $%d threshold. Comment `tfc apply --accept-cost` to apply anyway", delta, threshold)
+}
+
+// latestPlanOfCommit returns the newest of runs that TFBuddy started for this
+// project, merge request and commit, according to their metadata.
+func (t *TFCTrigger) latestPlanOfCommit(runs []*tfe.Run) *tfe.Run {
+	for _, run := range runs {
+		rmd, err := t.runstream.GetRunMeta(run.ID)
+		if err != nil || rmd == nil {
+			continue
+		}
+		if rmd.GetMRProjectNameWithNamespace() == t.GetProjectNameWithNamespace() &&
+			rmd.GetMRInternalID() == t.GetMergeRequestIID() &&
+			rmd.GetCommitSHA() == t.GetCommitSHA() {
+			return run
+		}
+	}
+	return nil
+}
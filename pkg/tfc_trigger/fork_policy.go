@@ -0,0 +1,61 @@
+package tfc_trigger
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+	"github.com/zapier/tfbuddy/pkg/vcs"
+)
+
+var (
+	ErrForkMRsDisabled   = errors.New("merge requests from forks are disabled on this TFBuddy instance")
+	ErrForkNotMaintainer = errors.New("runs for merge requests from forks must be started by a project maintainer")
+	ErrForkHeadMoved     = errors.New("the fork has new commits since the run was requested, please review them and comment again")
+	ErrForkNoCommit      = errors.New("runs for merge requests from forks must name the reviewed head commit, e.g. `tfc plan <sha>`")
+)
+
+// isFork reports whether the source branch of mr lives in a fork. Providers
+// that cannot tell are treated as same-project merge requests.
+func isFork(mr vcs.MR) bool {
+	f, ok := mr.(vcs.ForkMR)
+	return ok && f.IsFromFork()
+}
+
+// checkForkPolicy decides whether code from a fork may be uploaded to TFC.
+// Fork code can change providers and read workspace variables, so it only
+// runs when forks are enabled and a maintainer asked for it in a comment, and
+// only for the commit the maintainer saw. The head is read when the comment
+// is processed, after the fork may have pushed again, so the commit the
+// maintainer saw has to come from the comment itself.
+func (t *TFCTrigger) checkForkPolicy(ctx context.Context, mr vcs.MR) error {
+	if !t.appCfg.AllowForkMRs {
+		return ErrForkMRsDisabled
+	}
+	// the maintainer check is the gate, providers without it refuse forks
+	m, ok := t.gl.(vcs.Maintainers)
+	if !ok {
+		return vcs.ErrForkNotSupported
+	}
+	if t.GetTriggerSource() != CommentTrigger || t.cfg.Commenter == "" {
+		return ErrForkNotMaintainer
+	}
+	maintainer, err := m.IsMaintainer(ctx, t.GetProjectNameWithNamespace(), t.cfg.Commenter)
+	if err != nil {
+		return fmt.Errorf("could not check the project role of %s: %w", t.cfg.Commenter, err)
+	}
+	if !maintainer {
+		log.Info().Str("commenter", t.cfg.Commenter).Msg("refusing run for fork merge request requested by non-maintainer")
+		return ErrForkNotMaintainer
+	}
+	sha := t.cfg.CommentSHA
+	if sha == "" {
+		return ErrForkNoCommit
+	}
+	if !strings.HasPrefix(mr.(vcs.ForkMR).GetHeadSHA(), sha) {
+		return ErrForkHeadMoved
+	}
+	return nil
+}
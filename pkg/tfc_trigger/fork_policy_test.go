@@ -0,0 +1,111 @@
+package tfc_trigger_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/zapier/tfbuddy/internal/config"
+	"github.com/zapier/tfbuddy/pkg/mocks"
+	"github.com/zapier/tfbuddy/pkg/tfc_trigger"
+	"github.com/zapier/tfbuddy/pkg/vcs"
+	"go.uber.org/mock/gomock"
+)
+
+// maintainerGitClient is a GitClient that can look up project maintainers.
+type maintainerGitClient struct {
+	*mocks.MockGitClient
+	*mocks.MockMaintainers
+}
+
+// forkMR is a merge request from a fork.
+type forkMR struct {
+	*mocks.MockDetailedMR
+	*mocks.MockForkMR
+}
+
+func TestTFCEvents_ForkPolicy(t *testing.T) {
+	const (
+		project = "zapier/tfbuddy"
+		mrIID   = 101
+		headSHA = "forkhead1234"
+	)
+	tests := []struct {
+		name          string
+		allowForks    bool
+		source        tfc_trigger.TriggerSource
+		commenter     string
+		commentSHA    string
+		maintainer    bool
+		noMaintainers bool
+		wantErrored   string
+		wantEvaluated bool
+	}{
+		{name: "merge request event", allowForks: true, source: tfc_trigger.MergeRequestEventTrigger},
+		{name: "forks disabled", source: tfc_trigger.CommentTrigger, commenter: "maintainer", wantErrored: tfc_trigger.ErrForkMRsDisabled.Error()},
+		{name: "provider without maintainers", allowForks: true, source: tfc_trigger.CommentTrigger, commenter: "maintainer", noMaintainers: true, wantErrored: vcs.ErrForkNotSupported.Error()},
+		{name: "not a maintainer", allowForks: true, source: tfc_trigger.CommentTrigger, commenter: "contributor", wantErrored: tfc_trigger.ErrForkNotMaintainer.Error()},
+		{name: "no commit named", allowForks: true, source: tfc_trigger.CommentTrigger, commenter: "maintainer", maintainer: true, wantErrored: tfc_trigger.ErrForkNoCommit.Error()},
+		// the fork pushed between the comment and its processing
+		{name: "head moved", allowForks: true, source: tfc_trigger.CommentTrigger, commenter: "maintainer", commentSHA: "olderhead", maintainer: true, wantErrored: tfc_trigger.ErrForkHeadMoved.Error()},
+		{name: "maintainer", allowForks: true, source: tfc_trigger.CommentTrigger, commenter: "maintainer", commentSHA: headSHA, maintainer: true, wantEvaluated: true},
+		{name: "abbreviated commit", allowForks: true, source: tfc_trigger.CommentTrigger, commenter: "maintainer", commentSHA: headSHA[:7], maintainer: true, wantEvaluated: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			mr := &forkMR{mocks.NewMockDetailedMR(mockCtrl), mocks.NewMockForkMR(mockCtrl)}
+			mr.MockForkMR.EXPECT().IsFromFork().Return(true).AnyTimes()
+			mr.MockForkMR.EXPECT().GetHeadSHA().Return(headSHA).AnyTimes()
+			mr.MockDetailedMR.EXPECT().GetInternalID().Return(mrIID).AnyTimes()
+			mr.MockDetailedMR.EXPECT().GetTargetBranch().Return("main").AnyTimes()
+
+			gl := &maintainerGitClient{mocks.NewMockGitClient(mockCtrl), mocks.NewMockMaintainers(mockCtrl)}
+			gl.MockGitClient.EXPECT().GetMergeRequest(gomock.Any(), mrIID, project).Return(mr, nil)
+			if tt.allowForks && tt.source == tfc_trigger.CommentTrigger && !tt.noMaintainers {
+				gl.MockMaintainers.EXPECT().IsMaintainer(gomock.Any(), project, tt.commenter).Return(tt.maintainer, nil)
+			}
+			if tt.wantEvaluated {
+				gl.MockGitClient.EXPECT().GetMergeRequestModifiedFiles(gomock.Any(), mrIID, project).Return([]string{"README.md"}, nil)
+				// the configuration is read from the target branch, never the fork
+				gl.MockGitClient.EXPECT().GetRepoFile(gomock.Any(), project, ".tfbuddy.yaml", "main").
+					Return([]byte("workspaces:\n  - name: service-tfbuddy\n    organization: zapier-test\n    dir: terraform/\n"), nil)
+				gl.MockGitClient.EXPECT().CreateMergeRequestComment(gomock.Any(), mrIID, project, tfc_trigger.ErrNoChangesDetected.Error())
+			}
+			gl.MockGitClient.EXPECT().CloneMergeRequest(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+			tCfg, _ := tfc_trigger.NewTFCTriggerConfig(&tfc_trigger.TFCTriggerOptions{
+				Action:                   tfc_trigger.PlanAction,
+				Branch:                   "fork-branch",
+				CommitSHA:                headSHA,
+				CommentSHA:               tt.commentSHA,
+				ProjectNameWithNamespace: project,
+				MergeRequestIID:          mrIID,
+				TriggerSource:            tt.source,
+				Commenter:                tt.commenter,
+			})
+			var client vcs.GitClient = gl
+			if tt.noMaintainers {
+				client = gl.MockGitClient
+			}
+			trigger := tfc_trigger.NewTFCTrigger(config.Config{AllowForkMRs: tt.allowForks}, client, nil, nil, tCfg)
+			triggered, err := trigger.TriggerTFCEvents(context.Background())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(triggered.Executed) != 0 {
+				t.Fatalf("expected no workspaces to run, got %v", triggered.Executed)
+			}
+			if tt.wantErrored == "" {
+				if len(triggered.Errored) != 0 {
+					t.Fatalf("expected no errors, got %+v", triggered.Errored[0])
+				}
+				return
+			}
+			if len(triggered.Errored) != 1 || triggered.Errored[0].Error != tt.wantErrored {
+				t.Fatalf("expected error %q, got %+v", tt.wantErrored, triggered.Errored)
+			}
+		})
+	}
+}
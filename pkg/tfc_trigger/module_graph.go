@@ -0,0 +1,201 @@
+package tfc_trigger
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclparse"
+	"github.com/rs/zerolog/log"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// moduleBlockSchema only decodes what we need from a Terraform file: the
+// `module` blocks and their `source` attribute. Everything else is ignored.
+var moduleBlockSchema = &hcl.BodySchema{
+	Blocks: []hcl.BlockHeaderSchema{
+		{Type: "module", LabelNames: []string{"name"}},
+	},
+}
+
+var moduleSourceSchema = &hcl.BodySchema{
+	Attributes: []hcl.AttributeSchema{
+		{Name: "source"},
+	},
+}
+
+// isLocalModuleSource reports whether a module source refers to a directory
+// in the same repository. Terraform only treats sources starting with "./" or
+// "../" as local paths; everything else is a registry or remote address.
+func isLocalModuleSource(src string) bool {
+	return strings.HasPrefix(src, "./") || strings.HasPrefix(src, "../")
+}
+
+// localModuleSources parses the Terraform files in dir (relative to repoRoot)
+// and returns the repo-relative directories of every local module it calls.
+// Sources that escape the repository root are dropped.
+func localModuleSources(repoRoot, dir string) ([]string, error) {
+	absDir := filepath.Join(repoRoot, dir)
+	entries, err := os.ReadDir(absDir)
+	if err != nil {
+		return nil, fmt.Errorf("could not read directory %s. %w", dir, err)
+	}
+
+	parser := hclparse.NewParser()
+	var sources []string
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		var file *hcl.File
+		var diags hcl.Diagnostics
+		switch {
+		case strings.HasSuffix(entry.Name(), ".tf"):
+			file, diags = parser.ParseHCLFile(filepath.Join(absDir, entry.Name()))
+		case strings.HasSuffix(entry.Name(), ".tf.json"):
+			file, diags = parser.ParseJSONFile(filepath.Join(absDir, entry.Name()))
+		default:
+			continue
+		}
+		if diags.HasErrors() {
+			log.Debug().Str("file", path.Join(dir, entry.Name())).Str("diags", diags.Error()).Msg("could not parse Terraform file, skipping")
+			continue
+		}
+
+		content, _, _ := file.Body.PartialContent(moduleBlockSchema)
+		for _, block := range content.Blocks {
+			attrs, _, _ := block.Body.PartialContent(moduleSourceSchema)
+			attr, ok := attrs.Attributes["source"]
+			if !ok {
+				continue
+			}
+			val, diags := attr.Expr.Value(nil)
+			if diags.HasErrors() || !val.Type().Equals(cty.String) || val.IsNull() {
+				continue
+			}
+			src := val.AsString()
+			if !isLocalModuleSource(src) {
+				continue
+			}
+			modDir := path.Clean(path.Join(dir, src))
+			if modDir == ".." || strings.HasPrefix(modDir, "../") {
+				log.Debug().Str("dir", dir).Str("source", src).Msg("module source escapes repository root, ignoring")
+				continue
+			}
+			sources = append(sources, modDir)
+		}
+	}
+	return sources, nil
+}
+
+// moduleGraph lazily resolves and caches the local module calls of each
+// directory so several workspaces sharing modules only parse them once.
+type moduleGraph struct {
+	repoRoot string
+	edges    map[string][]string
+}
+
+func newModuleGraph(repoRoot string) *moduleGraph {
+	return &moduleGraph{
+		repoRoot: repoRoot,
+		edges:    map[string][]string{},
+	}
+}
+
+// transitiveModules returns every repo-relative module directory reachable
+// from dir through local `module` blocks. dir itself is not included.
+func (g *moduleGraph) transitiveModules(dir string) []string {
+	start := path.Clean(strings.TrimPrefix(dir, "/"))
+	if start == "" {
+		start = "."
+	}
+
+	visited := map[string]struct{}{start: {}}
+	queue := []string{start}
+	var result []string
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+
+		children, ok := g.edges[current]
+		if !ok {
+			var err error
+			children, err = localModuleSources(g.repoRoot, current)
+			if err != nil {
+				log.Debug().Err(err).Str("dir", current).Msg("could not resolve local modules")
+			}
+			g.edges[current] = children
+		}
+		for _, child := range children {
+			if _, seen := visited[child]; seen {
+				continue
+			}
+			visited[child] = struct{}{}
+			result = append(result, child)
+			queue = append(queue, child)
+		}
+	}
+	return result
+}
+
+// modifiesAnyDir reports whether any of the modified files lives inside one
+// of the given repo-relative directories.
+func modifiesAnyDir(dirs []string, modifiedFiles []string) bool {
+	for _, dir := range dirs {
+		prefix := dir + "/"
+		if dir == "." {
+			prefix = ""
+		}
+		for _, mf := range modifiedFiles {
+			if prefix == "" {
+				if path.Dir(mf) == "." {
+					return true
+				}
+				continue
+			}
+			if strings.HasPrefix(mf, prefix) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// workspacesForModuleChanges returns the workspaces with autoDetectModules
+// enabled whose transitive local modules contain one of the modified files.
+func (cfg *ProjectConfig) workspacesForModuleChanges(repoRoot string, modifiedFiles []string) []*TFCWorkspace {
+	graph := newModuleGraph(repoRoot)
+	var result []*TFCWorkspace
+	for _, ws := range cfg.Workspaces {
+		if !ws.AutoDetectModules {
+			continue
+		}
+		var files []string
+		for _, mf := range modifiedFiles {
+			if !ws.isExcluded(mf) {
+				files = append(files, mf)
+			}
+		}
+		modules := graph.transitiveModules(ws.Dir)
+		if modifiesAnyDir(modules, files) {
+			log.Debug().Str("ws", ws.Name).Strs("modules", modules).Msg("workspace triggered by local module change")
+			result = append(result, ws)
+		}
+	}
+	return result
+}
+
+// hasModuleDetection reports whether any workspace opted into
+// autoDetectModules, meaning the repository must be cloned before we can
+// decide which workspaces an MR touches.
+func (cfg *ProjectConfig) hasModuleDetection() bool {
+	for _, ws := range cfg.Workspaces {
+		if ws.AutoDetectModules {
+			return true
+		}
+	}
+	return false
+}
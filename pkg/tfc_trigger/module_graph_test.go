@@ -0,0 +1,150 @@
+package tfc_trigger
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func writeTestRepo(t *testing.T, files map[string]string) string {
+	t.Helper()
+	root := t.TempDir()
+	for name, content := range files {
+		p := filepath.Join(root, name)
+		if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return root
+}
+
+const testModuleRepoWorkspaceTF = `
+module "db" {
+  source = "../../modules/db"
+}
+
+module "vpc" {
+  source  = "terraform-aws-modules/vpc/aws"
+  version = "5.0.0"
+}
+`
+
+const testModuleRepoDbTF = `
+module "kms" {
+  source = "../kms"
+}
+`
+
+const testModuleRepoKmsTF = `
+module "self" {
+  source = "../db"
+}
+`
+
+func TestModuleGraph_transitiveModules(t *testing.T) {
+	root := writeTestRepo(t, map[string]string{
+		"terraform/dev/main.tf":    testModuleRepoWorkspaceTF,
+		"terraform/dev/vars.tf":    `variable "foo" {}`,
+		"terraform/broken/main.tf": `module "x" {`,
+		"modules/db/main.tf":       testModuleRepoDbTF,
+		"modules/kms/main.tf":      testModuleRepoKmsTF,
+		"escape/main.tf":           `module "x" { source = "../../outside" }`,
+	})
+
+	tests := []struct {
+		name string
+		dir  string
+		want []string
+	}{
+		{
+			name: "transitive local modules with cycle",
+			dir:  "terraform/dev/",
+			want: []string{"modules/db", "modules/kms"},
+		},
+		{
+			name: "unparseable file",
+			dir:  "terraform/broken",
+			want: nil,
+		},
+		{
+			name: "missing directory",
+			dir:  "terraform/missing",
+			want: nil,
+		},
+		{
+			name: "source outside repository",
+			dir:  "escape",
+			want: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := newModuleGraph(root).transitiveModules(tt.dir)
+			sort.Strings(got)
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("transitiveModules() - %s", diff)
+			}
+		})
+	}
+}
+
+func TestProjectConfig_workspacesForModuleChanges(t *testing.T) {
+	root := writeTestRepo(t, map[string]string{
+		"terraform/dev/main.tf":     testModuleRepoWorkspaceTF,
+		"terraform/staging/main.tf": testModuleRepoWorkspaceTF,
+		"modules/db/main.tf":        testModuleRepoDbTF,
+		"modules/kms/main.tf":       testModuleRepoKmsTF,
+	})
+	cfg := testLoadConfig(t, tfbuddyYamlAutoDetectModules)
+
+	tests := []struct {
+		name          string
+		modifiedFiles []string
+		want          []string
+	}{
+		{
+			name:          "direct module change",
+			modifiedFiles: []string{"modules/db/main.tf"},
+			want:          []string{"service-dev"},
+		},
+		{
+			name:          "transitive module change",
+			modifiedFiles: []string{"modules/kms/main.tf"},
+			want:          []string{"service-dev"},
+		},
+		{
+			name:          "unrelated change",
+			modifiedFiles: []string{"modules/other/main.tf", "README.md"},
+			want:          nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, ws := range cfg.workspacesForModuleChanges(root, tt.modifiedFiles) {
+				got = append(got, ws.Name)
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("workspacesForModuleChanges() - %s", diff)
+			}
+		})
+	}
+}
+
+const tfbuddyYamlAutoDetectModules = `
+---
+workspaces:
+  - name: service-dev
+    organization: foo-corp
+    dir: terraform/dev/
+    autoDetectModules: true
+  - name: service-staging
+    organization: foo-corp
+    dir: terraform/staging/
+`
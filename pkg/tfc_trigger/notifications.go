@@ -0,0 +1,33 @@
+package tfc_trigger
+
+import (
+	"context"
+	"sync"
+
+	"github.com/hashicorp/go-tfe"
+	"github.com/rs/zerolog/log"
+	"github.com/zapier/tfbuddy/pkg/tfc_api"
+)
+
+// notificationsEnsured holds the IDs of the workspaces whose notification
+// configuration this process has already checked.
+var notificationsEnsured sync.Map
+
+// ensureNotifications makes sure TFC notifies TFBuddy of the runs of ws, so
+// their updates reach the merge request. Each workspace is checked once per
+// process, and failures do not stop the run.
+func (t *TFCTrigger) ensureNotifications(ctx context.Context, org string, ws *tfe.Workspace) {
+	if !tfc_api.NotificationsManaged(t.appCfg, org, ws.Name) {
+		return
+	}
+	if _, ok := notificationsEnsured.Load(ws.ID); ok {
+		return
+	}
+	result, err := t.tfc.EnsureNotificationConfiguration(ctx, ws.ID, tfc_api.NewNotificationOptions(t.appCfg))
+	if err != nil {
+		log.Warn().Err(err).Str("workspace", ws.Name).Msg("could not ensure TFC notification configuration")
+		return
+	}
+	notificationsEnsured.Store(ws.ID, struct{}{})
+	log.Info().Str("workspace", ws.Name).Str("result", string(result)).Msg("ensured TFC notification configuration")
+}
@@ -0,0 +1,87 @@
+package tfc_trigger
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/go-tfe"
+	"github.com/rs/zerolog/log"
+	"github.com/zapier/tfbuddy/pkg/vcs"
+)
+
+var (
+	ErrPolicyOverrideNotAllowed = errors.New("you are not allowed to override policies, ask a project maintainer")
+	ErrNoRunAwaitingOverride    = errors.New("no apply of this merge request is waiting for a policy override")
+)
+
+// checkPolicyOverrideAllowed decides whether the commenter may override
+// policies: users in `tfc-policy-override-users` may, or project maintainers
+// when that list is empty.
+func (t *TFCTrigger) checkPolicyOverrideAllowed(ctx context.Context) error {
+	commenter := t.cfg.Commenter
+	if commenter == "" {
+		return ErrPolicyOverrideNotAllowed
+	}
+	if len(t.appCfg.TFCPolicyOverrideUsers) > 0 {
+		for _, user := range t.appCfg.TFCPolicyOverrideUsers {
+			if strings.EqualFold(strings.TrimSpace(user), commenter) {
+				return nil
+			}
+		}
+		return ErrPolicyOverrideNotAllowed
+	}
+	m, ok := t.gl.(vcs.Maintainers)
+	if !ok {
+		return ErrPolicyOverrideNotAllowed
+	}
+	maintainer, err := m.IsMaintainer(ctx, t.GetProjectNameWithNamespace(), commenter)
+	if err != nil {
+		return fmt.Errorf("could not check the project role of %s: %w", commenter, err)
+	}
+	if !maintainer {
+		return ErrPolicyOverrideNotAllowed
+	}
+	return nil
+}
+
+// overridePolicy overrides the failed policies of the apply of the merge
+// request that is waiting for it on ws.
+func (t *TFCTrigger) overridePolicy(ctx context.Context, ws *tfe.Workspace, mr vcs.DetailedMR) error {
+	if err := t.checkPolicyOverrideAllowed(ctx); err != nil {
+		log.Info().Str("commenter", t.cfg.Commenter).Str("workspace", ws.Name).Msg("refusing policy override")
+		return err
+	}
+	run, err := t.findRunAwaitingPolicyOverride(ctx, ws)
+	if err != nil {
+		return err
+	}
+	comment := fmt.Sprintf("Overridden by %s from merge request !%d", t.cfg.Commenter, mr.GetInternalID())
+	if err := t.tfc.OverridePolicy(ctx, run.ID, comment); err != nil {
+		return fmt.Errorf("could not override the policies of run %s. %w", run.ID, err)
+	}
+	log.Info().Str("commenter", t.cfg.Commenter).Str("workspace", ws.Name).Str("run", run.ID).Msg("overrode failed policies")
+	return t.postUpdate(ctx, fmt.Sprintf(":unlock: %s overrode the failed policies of run `%s` for Workspace `%s`, the apply continues.", t.cfg.Commenter, run.ID, ws.Name))
+}
+
+// findRunAwaitingPolicyOverride returns the latest run of ws waiting for a
+// policy override that TFBuddy started for this project and merge request.
+// The run message only holds the MR number, so the run metadata confirms it.
+func (t *TFCTrigger) findRunAwaitingPolicyOverride(ctx context.Context, ws *tfe.Workspace) (*tfe.Run, error) {
+	runs, err := t.tfc.ListRunsAwaitingPolicyOverride(ctx, ws.ID, runMessagePrefix(t.GetMergeRequestIID()))
+	if err != nil {
+		return nil, fmt.Errorf("could not find the run to override. %w", err)
+	}
+	for _, run := range runs {
+		rmd, err := t.runstream.GetRunMeta(run.ID)
+		if err != nil || rmd == nil {
+			log.Debug().Err(err).Str("run", run.ID).Msg("no metadata for run awaiting policy override")
+			continue
+		}
+		if rmd.GetMRProjectNameWithNamespace() == t.GetProjectNameWithNamespace() && rmd.GetMRInternalID() == t.GetMergeRequestIID() {
+			return run, nil
+		}
+	}
+	return nil, ErrNoRunAwaitingOverride
+}
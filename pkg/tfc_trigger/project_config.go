@@ -21,7 +21,8 @@ import (
 const ProjectConfigFilename = `.tfbuddy.yaml`
 
 type ProjectConfig struct {
-	Workspaces []*TFCWorkspace `yaml:"workspaces"`
+	Workspaces []*TFCWorkspace `yaml:"workspaces" description:"Terraform Cloud workspaces managed by TFBuddy for this repository."`
+	PlanDrafts bool            `yaml:"planDrafts" description:"Plan draft MRs when they are opened or updated. By default drafts are planned once they are marked ready, or by an explicit comment."`
 }
 
 // Finds the workspace with the deepest matching directory suffix.
@@ -82,12 +83,12 @@ func (cfg *ProjectConfig) triggeredWorkspaces(modifiedFiles []string) []*TFCWork
 	for _, mf := range modifiedFiles {
 		dir := path.Dir(mf)
 		ws := cfg.workspaceForDir(dir)
-		if ws != nil {
+		if ws != nil && !ws.isExcluded(mf) {
 			triggeredMap[ws.Dir] = ws
 		}
 
 		for _, trig := range cfg.workspacesForTriggerDir(dir) {
-			if trig != nil {
+			if trig != nil && !trig.isExcluded(mf) {
 				triggeredMap[trig.Dir] = trig
 			}
 		}
@@ -114,6 +115,9 @@ func hasChangesForWorkspace(ws *TFCWorkspace, modifiedFiles []string) bool {
 	isRootWS := wsDir == "" || wsDir == "/"
 
 	for _, mf := range modifiedFiles {
+		if ws.isExcluded(mf) {
+			continue
+		}
 		fileDir := path.Dir(mf)
 
 		if isRootWS {
@@ -154,20 +158,57 @@ func hasChangesForWorkspace(ws *TFCWorkspace, modifiedFiles []string) bool {
 	return false
 }
 
+// TFCWorkspace is a single entry of .tfbuddy.yaml. The description tags are
+// used to generate the published JSON Schema (see ProjectConfigSchema).
 type TFCWorkspace struct {
-	Name         string   `yaml:"name" validate:"empty=false"`
-	Organization string   `yaml:"organization" validate:"empty=false"`
-	Dir          string   `yaml:"dir"`
-	Mode         string   `yaml:"mode" default:"apply-before-merge" validate:"one_of=apply-before-merge,merge-before-apply,tfc-vcs-repo"`
-	TriggerDirs  []string `yaml:"triggerDirs"`
-	AutoMerge    bool     `yaml:"autoMerge" default:"true"`
+	Name              string              `yaml:"name" validate:"empty=false" description:"Name of the Terraform Cloud workspace."`
+	Organization      string              `yaml:"organization" validate:"empty=false" description:"Terraform Cloud organization. Defaults to TFBUDDY_DEFAULT_TFC_ORGANIZATION."`
+	Dir               string              `yaml:"dir" description:"Directory (relative to the repository root) containing the workspace's Terraform code."`
+	Mode              string              `yaml:"mode" default:"apply-before-merge" validate:"one_of=apply-before-merge,merge-before-apply,tfc-vcs-repo" description:"Workflow used for this workspace."`
+	TriggerDirs       []string            `yaml:"triggerDirs" description:"Additional doublestar globs of directories or files that trigger this workspace."`
+	AutoMerge         bool                `yaml:"autoMerge" default:"true" description:"Merge the MR once all of its workspaces have been applied."`
+	AutoDetectModules bool                `yaml:"autoDetectModules" description:"Trigger the workspace when a local module it calls (directly or transitively) is modified."`
+	AutoPlan          bool                `yaml:"autoPlan" default:"true" description:"Plan the workspace when an MR is opened or updated. When false, only an explicit comment plans it."`
+	ExcludePaths      []string            `yaml:"excludePaths" description:"Doublestar globs of files that never trigger this workspace."`
+	TerraformVersion  string              `yaml:"terraformVersion" description:"Terraform version used when the comment does not set one."`
+	Target            string              `yaml:"target" description:"Comma-separated resource targets used when the comment does not set any."`
+	AllowEmptyRun     bool                `yaml:"allowEmptyRun" description:"Allow empty applies when the comment does not set it."`
+	Create            *TFCWorkspaceCreate `yaml:"create" description:"Create the workspace on its first plan if it does not exist in Terraform Cloud. Only allowed in the organizations of TFBUDDY_TFC_WORKSPACE_CREATE_ORGANIZATIONS."`
+}
+
+// TFCWorkspaceCreate describes the Terraform Cloud workspace TFBuddy creates
+// when a workspace of .tfbuddy.yaml does not exist yet.
+type TFCWorkspaceCreate struct {
+	Project          string   `yaml:"project" description:"Project of the workspace. Defaults to the organization's default project."`
+	TerraformVersion string   `yaml:"terraformVersion" description:"Terraform version of the workspace. Defaults to the latest version."`
+	ExecutionMode    string   `yaml:"executionMode" description:"Execution mode of the workspace: remote, local or agent. Defaults to the organization's default execution mode."`
+	AgentPoolID      string   `yaml:"agentPoolID" description:"ID of the agent pool running the workspace, required by the agent execution mode."`
+	WorkingDirectory string   `yaml:"workingDirectory" description:"Directory Terraform runs in, relative to the repository root. When set, the whole repository is uploaded instead of dir."`
+	Tags             []string `yaml:"tags" description:"Tags of the workspace."`
+	VariableSets     []string `yaml:"variableSets" description:"Names of the variable sets attached to the workspace."`
+}
+
+// isExcluded reports whether a modified file matches one of the workspace's
+// excludePaths globs.
+func (ws *TFCWorkspace) isExcluded(file string) bool {
+	for _, pattern := range ws.ExcludePaths {
+		match, err := doublestar.Match(pattern, file)
+		if err != nil {
+			log.Warn().Err(err).Str("excludePath", pattern).Str("file", file).Msg("invalid excludePaths glob pattern, ignoring")
+			continue
+		}
+		if match {
+			return true
+		}
+	}
+	return false
 }
 
 func getProjectConfigFile(ctx context.Context, gl vcs.GitClient, trigger *TFCTrigger) (*ProjectConfig, error) {
 	ctx, span := otel.Tracer(trigger.tracerName()).Start(ctx, "getProjectConfigFile")
 	defer span.End()
 
-	branches := []string{trigger.GetBranch(), "master", "main"}
+	branches := []string{firstNonEmpty(trigger.configBranch, trigger.GetBranch()), "master", "main"}
 	for _, branch := range branches {
 		log.Debug().Msg(fmt.Sprintf("considering branch %s", branch))
 		b, err := gl.GetRepoFile(ctx, trigger.GetProjectNameWithNamespace(), ProjectConfigFilename, branch)
@@ -0,0 +1,113 @@
+package tfc_trigger
+
+//go:generate go run ../../hack/gen-config-schema
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// ProjectConfigSchemaID is the $id of the generated schema, and the path it
+// is served at by the hooks server.
+const ProjectConfigSchemaID = "/schema/tfbuddy.json"
+
+// schemaOptionalFields are validated as non-empty but may be omitted from the
+// file because loadProjectConfig fills them in from the server config.
+var schemaOptionalFields = map[string]bool{
+	"organization": true,
+}
+
+// ProjectConfigSchema returns a JSON Schema (draft-07) describing
+// .tfbuddy.yaml. It is derived from the ProjectConfig struct tags so it
+// can't drift from what loadProjectConfig accepts: `yaml` names the
+// properties, `default` and `description` are copied verbatim and the
+// `one_of` / `empty=false` validators become enum / required constraints.
+func ProjectConfigSchema() ([]byte, error) {
+	root := schemaForStruct(reflect.TypeOf(ProjectConfig{}))
+	root["$schema"] = "http://json-schema.org/draft-07/schema#"
+	root["$id"] = ProjectConfigSchemaID
+	root["title"] = ProjectConfigFilename
+
+	b, err := json.MarshalIndent(root, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("could not marshal project config schema. %w", err)
+	}
+	return append(b, '\n'), nil
+}
+
+func schemaForStruct(t reflect.Type) map[string]any {
+	properties := map[string]any{}
+	var required []string
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name := strings.Split(field.Tag.Get("yaml"), ",")[0]
+		if name == "" || name == "-" {
+			continue
+		}
+
+		prop := schemaForType(field.Type)
+		if desc := field.Tag.Get("description"); desc != "" {
+			prop["description"] = desc
+		}
+		if def, ok := field.Tag.Lookup("default"); ok {
+			prop["default"] = schemaDefault(field.Type, def)
+		}
+		for _, rule := range strings.Split(field.Tag.Get("validate"), " ") {
+			switch {
+			case strings.HasPrefix(rule, "one_of="):
+				prop["enum"] = strings.Split(strings.TrimPrefix(rule, "one_of="), ",")
+			case rule == "empty=false":
+				if !schemaOptionalFields[name] {
+					required = append(required, name)
+				}
+				prop["minLength"] = 1
+			}
+		}
+		properties[name] = prop
+	}
+
+	s := map[string]any{
+		"type":                 "object",
+		"properties":           properties,
+		"additionalProperties": false,
+	}
+	if len(required) > 0 {
+		s["required"] = required
+	}
+	return s
+}
+
+func schemaForType(t reflect.Type) map[string]any {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	switch t.Kind() {
+	case reflect.Struct:
+		return schemaForStruct(t)
+	case reflect.Slice:
+		return map[string]any{"type": "array", "items": schemaForType(t.Elem())}
+	case reflect.Bool:
+		return map[string]any{"type": "boolean"}
+	case reflect.Int, reflect.Int64, reflect.Int32:
+		return map[string]any{"type": "integer"}
+	default:
+		return map[string]any{"type": "string"}
+	}
+}
+
+func schemaDefault(t reflect.Type, def string) any {
+	switch t.Kind() {
+	case reflect.Bool:
+		if b, err := strconv.ParseBool(def); err == nil {
+			return b
+		}
+	case reflect.Int, reflect.Int64, reflect.Int32:
+		if n, err := strconv.Atoi(def); err == nil {
+			return n
+		}
+	}
+	return def
+}
@@ -0,0 +1,63 @@
+package tfc_trigger
+
+import (
+	"encoding/json"
+	"os"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestProjectConfigSchema(t *testing.T) {
+	b, err := ProjectConfigSchema()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var schema struct {
+		Properties struct {
+			Workspaces struct {
+				Items struct {
+					Required   []string                  `json:"required"`
+					Properties map[string]map[string]any `json:"properties"`
+				} `json:"items"`
+			} `json:"workspaces"`
+		} `json:"properties"`
+	}
+	if err := json.Unmarshal(b, &schema); err != nil {
+		t.Fatal(err)
+	}
+	ws := schema.Properties.Workspaces.Items
+
+	if diff := cmp.Diff([]string{"name"}, ws.Required); diff != "" {
+		t.Errorf("required - %s", diff)
+	}
+	if diff := cmp.Diff([]any{"apply-before-merge", "merge-before-apply", "tfc-vcs-repo"}, ws.Properties["mode"]["enum"]); diff != "" {
+		t.Errorf("mode enum - %s", diff)
+	}
+	if got := ws.Properties["mode"]["default"]; got != "apply-before-merge" {
+		t.Errorf("mode default = %v", got)
+	}
+	if got := ws.Properties["autoMerge"]["default"]; got != true {
+		t.Errorf("autoMerge default = %v", got)
+	}
+	if got := ws.Properties["triggerDirs"]["type"]; got != "array" {
+		t.Errorf("triggerDirs type = %v", got)
+	}
+}
+
+// TestProjectConfigSchema_UpToDate fails when the TFCWorkspace struct changed
+// without re-running `go generate ./pkg/tfc_trigger`.
+func TestProjectConfigSchema_UpToDate(t *testing.T) {
+	want, err := ProjectConfigSchema()
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := os.ReadFile("../../docs/tfbuddy.schema.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(string(want), string(got)); diff != "" {
+		t.Errorf("docs/tfbuddy.schema.json is out of date, run `go generate ./pkg/tfc_trigger` - %s", diff)
+	}
+}
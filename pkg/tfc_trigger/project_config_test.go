@@ -282,6 +282,17 @@ func TestProjectConfig_triggeredWorkspaces(t *testing.T) {
 			},
 			want: testLoadConfig(t, tfbuddyYamlNoTriggerDirs).Workspaces,
 		},
+		{
+			name:    "excluded-files-only",
+			cfgYaml: tfbuddyYamlWorkspaceRunDefaults,
+			args: args{
+				modifiedFiles: []string{
+					"terraform/dev/README.md",
+					"terraform/dev/docs/usage.md",
+				},
+			},
+			want: []*TFCWorkspace{},
+		},
 		{
 			name:    "trigger-dir-match",
 			cfgYaml: tfbuddyYamlDoublestarTriggerDir,
@@ -476,6 +487,7 @@ func Test_loadProjectConfig(t *testing.T) {
 					Mode:         "apply-before-merge",
 					TriggerDirs:  nil,
 					AutoMerge:    true,
+					AutoPlan:     true,
 				},
 			}},
 			wantErr: false,
@@ -491,6 +503,7 @@ func Test_loadProjectConfig(t *testing.T) {
 					Mode:         "apply-before-merge",
 					TriggerDirs:  nil,
 					AutoMerge:    false,
+					AutoPlan:     true,
 				},
 			}},
 			wantErr: false,
@@ -520,6 +533,7 @@ func Test_loadProjectConfig(t *testing.T) {
 					Mode:         "apply-before-merge",
 					TriggerDirs:  nil,
 					AutoMerge:    true,
+					AutoPlan:     true,
 				},
 			}},
 			wantErr: false,
@@ -534,6 +548,7 @@ func Test_loadProjectConfig(t *testing.T) {
 					Dir:          "terraform/dev/",
 					Mode:         "apply-before-merge",
 					AutoMerge:    true,
+					AutoPlan:     true,
 					TriggerDirs: []string{
 						"modules/**",
 					},
@@ -551,6 +566,7 @@ func Test_loadProjectConfig(t *testing.T) {
 					Dir:          "terraform/dev/",
 					Mode:         "apply-before-merge",
 					AutoMerge:    true,
+					AutoPlan:     true,
 					TriggerDirs: []string{
 						"modules/database/",
 					},
@@ -568,6 +584,26 @@ func Test_loadProjectConfig(t *testing.T) {
 					Dir:          "terraform/dev/",
 					Mode:         "apply-before-merge",
 					AutoMerge:    true,
+					AutoPlan:     true,
+				},
+			}},
+			wantErr: false,
+		},
+		{
+			name: "workspace run defaults",
+			args: args{b: []byte(tfbuddyYamlWorkspaceRunDefaults)},
+			want: &ProjectConfig{Workspaces: []*TFCWorkspace{
+				{
+					Name:             "service-tfbuddy-dev",
+					Organization:     "foo-corp",
+					Dir:              "terraform/dev/",
+					Mode:             "apply-before-merge",
+					AutoMerge:        true,
+					AutoPlan:         false,
+					ExcludePaths:     []string{"**/*.md"},
+					TerraformVersion: "1.9.8",
+					Target:           "module.db",
+					AllowEmptyRun:    true,
 				},
 			}},
 			wantErr: false,
@@ -588,6 +624,7 @@ func Test_loadProjectConfig(t *testing.T) {
 					Dir:          "terraform/dev/",
 					Mode:         "apply-before-merge",
 					AutoMerge:    true,
+					AutoPlan:     true,
 				},
 				{
 					Name:         "service-tfbuddy-tooling",
@@ -595,6 +632,7 @@ func Test_loadProjectConfig(t *testing.T) {
 					Dir:          "terraform/tooling/",
 					Mode:         "apply-before-merge",
 					AutoMerge:    true,
+					AutoPlan:     true,
 				},
 			}},
 			wantErr: false,
@@ -767,6 +805,26 @@ func TestHasChangesForWorkspace(t *testing.T) {
 			modifiedFiles: []string{"modules/database/rds/main.tf"},
 			want:          true,
 		},
+		{
+			name: "only excluded files in workspace dir",
+			ws: &TFCWorkspace{
+				Name:         "service-a-prod",
+				Dir:          "services/a/production/",
+				ExcludePaths: []string{"**/*.md"},
+			},
+			modifiedFiles: []string{"services/a/production/README.md"},
+			want:          false,
+		},
+		{
+			name: "excluded and included files in workspace dir",
+			ws: &TFCWorkspace{
+				Name:         "service-a-prod",
+				Dir:          "services/a/production/",
+				ExcludePaths: []string{"**/*.md"},
+			},
+			modifiedFiles: []string{"services/a/production/README.md", "services/a/production/main.tf"},
+			want:          true,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -964,3 +1022,17 @@ workspaces:
     dir: workspaces
 
 `
+
+const tfbuddyYamlWorkspaceRunDefaults = `
+---
+workspaces:
+  - name: service-tfbuddy-dev
+    organization: foo-corp
+    dir: terraform/dev/
+    autoPlan: false
+    excludePaths:
+    - "**/*.md"
+    terraformVersion: 1.9.8
+    target: module.db
+    allowEmptyRun: true
+`
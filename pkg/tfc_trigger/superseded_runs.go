@@ -0,0 +1,75 @@
+package tfc_trigger
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/hashicorp/go-tfe"
+	"github.com/rs/zerolog/log"
+	"github.com/zapier/tfbuddy/pkg/tfc_api"
+)
+
+// cancelSupersededRuns cancels the speculative plans that TFBuddy started
+// before newRun for older commits of the merge request on ws and are still
+// running, stops polling them and marks their discussions as superseded.
+func (t *TFCTrigger) cancelSupersededRuns(ctx context.Context, ws *tfe.Workspace, newRun *tfe.Run) {
+	if !t.appCfg.TFCCancelSupersededPlans {
+		return
+	}
+	runs, err := t.tfc.ListSpeculativeRuns(ctx, ws.ID, runMessagePrefix(t.GetMergeRequestIID()))
+	if err != nil {
+		log.Warn().Err(err).Str("workspace", ws.Name).Msg("could not list the plans to supersede")
+		return
+	}
+	for _, run := range runs {
+		if run.ID == newRun.ID || !isRunActive(run) || !run.CreatedAt.Before(newRun.CreatedAt) {
+			continue
+		}
+		// the message prefix only holds the MR number, the metadata confirms
+		// TFBuddy started the run for this project and MR
+		rmd, err := t.runstream.GetRunMeta(run.ID)
+		if err != nil || rmd == nil {
+			log.Debug().Err(err).Str("run", run.ID).Msg("no metadata for run, not superseding it")
+			continue
+		}
+		if rmd.GetMRProjectNameWithNamespace() != t.GetProjectNameWithNamespace() || rmd.GetMRInternalID() != t.GetMergeRequestIID() {
+			continue
+		}
+		if rmd.GetCommitSHA() == t.GetCommitSHA() {
+			// planned again for the same commit, not superseded
+			continue
+		}
+
+		comment := fmt.Sprintf("Superseded by run %s for commit %s", newRun.ID, t.GetCommitSHA())
+		if err := t.tfc.CancelRun(ctx, run.ID, comment); err != nil {
+			if !errors.Is(err, tfc_api.ErrRunNotCancelable) {
+				log.Warn().Err(err).Str("run", run.ID).Msg("could not cancel superseded run")
+			}
+			continue
+		}
+		if err := t.runstream.StopTFRunPollingTask(run.ID); err != nil {
+			log.Warn().Err(err).Str("run", run.ID).Msg("could not stop polling superseded run")
+		}
+		log.Info().Str("workspace", ws.Name).Str("run", run.ID).Str("newRun", newRun.ID).Msg("canceled superseded plan")
+
+		if rmd.GetDiscussionID() == "" {
+			continue
+		}
+		project := t.GetProjectNameWithNamespace()
+		_, err = t.gl.AddMergeRequestDiscussionReply(ctx, t.GetMergeRequestIID(), project, rmd.GetDiscussionID(),
+			fmt.Sprintf(":fast_forward: Superseded by a newer commit, run `%s` was canceled in favor of run `%s` for commit `%s`.", run.ID, newRun.ID, t.GetCommitSHA()))
+		if err != nil {
+			log.Warn().Err(err).Str("run", run.ID).Msg("could not mark the discussion of the superseded run")
+			continue
+		}
+		if err := t.gl.ResolveMergeRequestDiscussion(ctx, project, t.GetMergeRequestIID(), rmd.GetDiscussionID()); err != nil {
+			log.Debug().Err(err).Str("run", run.ID).Msg("could not resolve the discussion of the superseded run")
+		}
+	}
+}
+
+// isRunActive reports whether the run is waiting to start or still running.
+func isRunActive(run *tfe.Run) bool {
+	return run.Actions != nil && (run.Actions.IsCancelable || run.Actions.IsDiscardable)
+}
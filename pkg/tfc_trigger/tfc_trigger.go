@@ -35,11 +35,15 @@ const (
 	PlanAction
 	RefreshAction
 	UnlockAction
+	OverridePolicyAction
 	InvalidAction
 )
 
 const tfPrefix = "tfbuddylock"
 
+// LockTagPrefix prefixes the workspace tag naming the merge request that locks it.
+const LockTagPrefix = tfPrefix
+
 var tagRegex = regexp.MustCompile(fmt.Sprintf("%s\\-(\\d+)", tfPrefix))
 
 func (a TriggerAction) String() string {
@@ -56,6 +60,8 @@ func (a TriggerAction) String() string {
 		return "refresh"
 	case UnlockAction:
 		return "unlock"
+	case OverridePolicyAction:
+		return "override-policy"
 	default:
 		return "invalid"
 	}
@@ -75,6 +81,8 @@ func CheckTriggerAction(action string) TriggerAction {
 		return UnlockAction
 	case "refresh":
 		return RefreshAction
+	case "override-policy":
+		return OverridePolicyAction
 	default:
 		return InvalidAction
 	}
@@ -94,6 +102,9 @@ type TFCTrigger struct {
 	// workspaceStream, when set, fans out one message per workspace instead
 	// of running them inline. Nil keeps the legacy synchronous behavior.
 	workspaceStream WorkspacePublisher
+	// configBranch, when set, is the branch .tfbuddy.yaml is read from
+	// instead of the source branch.
+	configBranch string
 }
 
 type WorkspacePublisher interface {
@@ -117,11 +128,19 @@ type TFCTriggerOptions struct {
 	// DeliveryID is the upstream webhook delivery ID (X-GitHub-Delivery /
 	// X-Gitlab-Event-UUID). Used as the JetStream dedup anchor so retriggers
 	// are not silently dropped within the dedup window.
-	DeliveryID    string
+	DeliveryID string
+	// Commenter is the username of the author of the comment that triggered
+	// a CommentTrigger.
+	Commenter string
+	// CommentSHA is the head commit the commenter saw, either named in the
+	// comment (`tfc plan <sha>`) or carried by the comment event. Runs for
+	// forks are pinned to it.
+	CommentSHA    string
 	Workspace     string `short:"w" long:"workspace" description:"A specific terraform Workspace to use" required:"false"`
 	TFVersion     string `short:"v" long:"tf_version" description:"A specific terraform version to use" required:"false"`
 	Target        string `short:"t" long:"target" description:"A specific terraform target to use" required:"false"`
 	AllowEmptyRun bool   `short:"e" long:"allow_empty_run" description:"A specific terraform AllowEmptyRun" required:"false"`
+	AcceptCost    bool   `long:"accept-cost" description:"Apply even if the plan raises the monthly cost above the threshold" required:"false"`
 }
 
 func NewTFCTriggerConfig(opts *TFCTriggerOptions) (*TFCTriggerOptions, error) {
@@ -222,6 +241,12 @@ func (t *TFCTrigger) tracerName() string {
 		return "GithubHandler"
 	case "gitlab":
 		return "GitlabHandler"
+	case "bitbucket":
+		return "BitbucketHandler"
+	case "azuredevops":
+		return "AzureDevOpsHandler"
+	case "gitea":
+		return "GiteaHandler"
 	default:
 		return "TFCTrigger"
 	}
@@ -281,6 +306,11 @@ func (t *TFCTrigger) postUpdate(ctx context.Context, msg string) error {
 
 }
 
+// runMessagePrefix starts the message of the TFC runs of a merge request.
+func runMessagePrefix(mrIID int) string {
+	return fmt.Sprintf("MR [!%d]: ", mrIID)
+}
+
 func (t *TFCTrigger) getLockingMR(ctx context.Context, workspace string) string {
 	ctx, span := otel.Tracer("TFC").Start(ctx, "getLockingMR")
 	defer span.End()
@@ -297,7 +327,7 @@ func (t *TFCTrigger) getLockingMR(ctx context.Context, workspace string) string
 	return lockingMR
 }
 
-func (t *TFCTrigger) getTriggeredWorkspaces(ctx context.Context, modifiedFiles []string) ([]*TFCWorkspace, error) {
+func (t *TFCTrigger) getTriggeredWorkspaces(ctx context.Context, modifiedFiles []string, repo *lazyRepo) ([]*TFCWorkspace, error) {
 	ctx, span := otel.Tracer(t.tracerName()).Start(ctx, "getTriggeredWorkspaces")
 	defer span.End()
 
@@ -328,10 +358,41 @@ func (t *TFCTrigger) getTriggeredWorkspaces(ctx context.Context, modifiedFiles [
 	} else {
 		// check the MR modified files list against the .tfbuddy.yaml configured directories
 		triggeredWorkspaces = cfg.triggeredWorkspaces(modifiedFiles)
+		if repo != nil && cfg.hasModuleDetection() {
+			triggeredWorkspaces = t.addModuleTriggeredWorkspaces(ctx, cfg, triggeredWorkspaces, modifiedFiles, repo)
+		}
 	}
 	return triggeredWorkspaces, nil
 }
 
+// addModuleTriggeredWorkspaces extends the triggered workspaces with those
+// whose local Terraform modules were modified. Module detection needs the
+// repository source, so failures here are logged and the directory-based
+// result is returned unchanged.
+func (t *TFCTrigger) addModuleTriggeredWorkspaces(ctx context.Context, cfg *ProjectConfig, triggered []*TFCWorkspace, modifiedFiles []string, repo *lazyRepo) []*TFCWorkspace {
+	ctx, span := otel.Tracer(t.tracerName()).Start(ctx, "addModuleTriggeredWorkspaces")
+	defer span.End()
+
+	r, err := repo.get(ctx)
+	if err != nil {
+		log.Warn().Err(err).Msg("could not clone repository for module detection, falling back to directory matching")
+		return triggered
+	}
+
+	seen := make(map[string]struct{}, len(triggered))
+	for _, ws := range triggered {
+		seen[ws.Dir] = struct{}{}
+	}
+	for _, ws := range cfg.workspacesForModuleChanges(r.GetLocalDirectory(), modifiedFiles) {
+		if _, ok := seen[ws.Dir]; ok {
+			continue
+		}
+		seen[ws.Dir] = struct{}{}
+		triggered = append(triggered, ws)
+	}
+	return triggered
+}
+
 type ErroredWorkspace struct {
 	Name  string
 	Error string
@@ -341,6 +402,20 @@ type TriggeredTFCWorkspaces struct {
 	Executed []string
 }
 
+// Reactions returns the reactions acknowledging the outcome of a command
+// that triggered these workspaces, or failed with err: a rocket when runs
+// were started and a thumbs down when any workspace could not be run.
+func (t *TriggeredTFCWorkspaces) Reactions(err error) []vcs.Reaction {
+	var reactions []vcs.Reaction
+	if t != nil && len(t.Executed) > 0 {
+		reactions = append(reactions, vcs.ReactionDispatched)
+	}
+	if err != nil || (t != nil && len(t.Errored) > 0) {
+		reactions = append(reactions, vcs.ReactionRefused)
+	}
+	return reactions
+}
+
 func (t *TFCTrigger) getModifiedWorkspacesOnTargetBranch(ctx context.Context, mr vcs.MR, repo vcs.GitRepo, triggeredWorkspaces []*TFCWorkspace) (map[string]struct{}, error) {
 	ctx, span := otel.Tracer(t.tracerName()).Start(ctx, "getModifiedWorkspacesOnTargetBranch")
 	defer span.End()
@@ -352,7 +427,12 @@ func (t *TFCTrigger) getModifiedWorkspacesOnTargetBranch(ctx context.Context, mr
 		return modifiedWSMap, fmt.Errorf("could not fetch target branch %s. %w", mr.GetTargetBranch(), err)
 	}
 	// find merge base. This is the common commit between the source branch and the target branch. This is usually the commit a branch was forked from.
-	commonSHA, err := repo.GetMergeBase(mr.GetSourceBranch(), mr.GetTargetBranch())
+	// forks are checked out at their head commit, there is no source branch.
+	sourceRev := mr.GetSourceBranch()
+	if isFork(mr) {
+		sourceRev = "HEAD"
+	}
+	commonSHA, err := repo.GetMergeBase(sourceRev, mr.GetTargetBranch())
 	if err != nil {
 		return nil, fmt.Errorf("could not find merge base. %w", err)
 	}
@@ -370,16 +450,22 @@ func (t *TFCTrigger) getModifiedWorkspacesOnTargetBranch(ctx context.Context, mr
 		// target branch fall within that workspace's Dir (prefix match) or match
 		// its TriggerDirs (glob match). This avoids the suffix-based matching in
 		// workspaceForDir which can produce false positives across services.
+		graph := newModuleGraph(repo.GetLocalDirectory())
 		for _, ws := range triggeredWorkspaces {
 			if hasChangesForWorkspace(ws, targetModifiedFiles) {
 				log.Debug().Str("ws", ws.Name).Str("dir", ws.Dir).Msg("workspace has changes on target branch")
 				modifiedWSMap[ws.Name] = struct{}{}
+				continue
+			}
+			if ws.AutoDetectModules && modifiesAnyDir(graph.transitiveModules(ws.Dir), targetModifiedFiles) {
+				log.Debug().Str("ws", ws.Name).Str("dir", ws.Dir).Msg("workspace modules have changes on target branch")
+				modifiedWSMap[ws.Name] = struct{}{}
 			}
 		}
 	}
 	return modifiedWSMap, err
 }
-func (t *TFCTrigger) getTriggeredWorkspacesForRequest(ctx context.Context, mr vcs.MR) ([]*TFCWorkspace, error) {
+func (t *TFCTrigger) getTriggeredWorkspacesForRequest(ctx context.Context, mr vcs.MR, repo *lazyRepo) ([]*TFCWorkspace, error) {
 	ctx, span := otel.Tracer(t.tracerName()).Start(ctx, "getTriggeredWorkspacesForRequest")
 	defer span.End()
 
@@ -388,7 +474,7 @@ func (t *TFCTrigger) getTriggeredWorkspacesForRequest(ctx context.Context, mr vc
 		return nil, fmt.Errorf("failed to get a list of modified files. %w", err)
 	}
 	log.Debug().Str("project", t.GetProjectNameWithNamespace()).Int("mergeRequestID", mr.GetInternalID()).Strs("modifiedFiles", mrModifiedFiles).Msg("modified files")
-	return t.getTriggeredWorkspaces(ctx, mrModifiedFiles)
+	return t.getTriggeredWorkspaces(ctx, mrModifiedFiles, repo)
 
 }
 
@@ -409,6 +495,38 @@ func (t *TFCTrigger) cloneGitRepo(ctx context.Context, mr vcs.MR) (vcs.GitRepo,
 	return repo, nil
 }
 
+// lazyRepo clones the MR source at most once, on first use. Module detection
+// and run dispatch share the same checkout.
+type lazyRepo struct {
+	t    *TFCTrigger
+	mr   vcs.MR
+	repo vcs.GitRepo
+	err  error
+	done bool
+}
+
+func (t *TFCTrigger) newLazyRepo(mr vcs.MR) *lazyRepo {
+	return &lazyRepo{t: t, mr: mr}
+}
+
+func (l *lazyRepo) get(ctx context.Context) (vcs.GitRepo, error) {
+	if !l.done {
+		l.repo, l.err = l.t.cloneGitRepo(ctx, l.mr)
+		l.done = true
+	}
+	return l.repo, l.err
+}
+
+// cleanup removes the cloned directory, if a clone was made.
+func (l *lazyRepo) cleanup() {
+	if l.repo == nil {
+		return
+	}
+	if err := os.RemoveAll(l.repo.GetLocalDirectory()); err != nil {
+		log.Error().Err(err).Str("path", l.repo.GetLocalDirectory()).Msg("could not remove cloned repository directory")
+	}
+}
+
 // TriggerTFCEvents dispatches one run per touched workspace. The clone and
 // target-branch evaluation happen once per delivery so the fan-out path
 // doesn't redo MR-level work in every worker.
@@ -420,10 +538,33 @@ func (t *TFCTrigger) TriggerTFCEvents(ctx context.Context) (*TriggeredTFCWorkspa
 	if err != nil {
 		return nil, fmt.Errorf("could not read MergeRequest data from VCS API: %w", err)
 	}
-	triggeredWorkspaces, err := t.getTriggeredWorkspacesForRequest(ctx, mr)
+	if isFork(mr) {
+		if err := t.checkForkPolicy(ctx, mr); err != nil {
+			if t.GetTriggerSource() == MergeRequestEventTrigger {
+				log.Info().Err(err).Msg("not planning merge request from a fork")
+				return &TriggeredTFCWorkspaces{}, nil
+			}
+			return &TriggeredTFCWorkspaces{
+				Errored: []*ErroredWorkspace{{Name: t.GetProjectNameWithNamespace(), Error: err.Error()}},
+			}, nil
+		}
+		// the fork must not be able to change which workspaces it runs in
+		t.configBranch = mr.GetTargetBranch()
+	}
+	if t.skipDraft(ctx, mr) {
+		log.Debug().Int("mergeRequestID", mr.GetInternalID()).Msg("not planning draft merge request")
+		return &TriggeredTFCWorkspaces{}, nil
+	}
+	lazy := t.newLazyRepo(mr)
+	defer lazy.cleanup()
+
+	triggeredWorkspaces, err := t.getTriggeredWorkspacesForRequest(ctx, mr, lazy)
 	if err != nil {
 		return nil, fmt.Errorf("could not read triggered workspaces. %w", err)
 	}
+	if t.GetTriggerSource() == MergeRequestEventTrigger {
+		triggeredWorkspaces = autoPlanWorkspaces(triggeredWorkspaces)
+	}
 	if len(triggeredWorkspaces) == 0 {
 		if t.GetTriggerSource() == CommentTrigger {
 			log.Error().Err(ErrNoChangesDetected).Msg("No Terraform changes found in changeset.")
@@ -434,15 +575,10 @@ func (t *TFCTrigger) TriggerTFCEvents(ctx context.Context) (*TriggeredTFCWorkspa
 		return &TriggeredTFCWorkspaces{}, nil
 	}
 
-	repo, err := t.cloneGitRepo(ctx, mr)
+	repo, err := lazy.get(ctx)
 	if err != nil {
 		return nil, err
 	}
-	defer func() {
-		if err := os.RemoveAll(repo.GetLocalDirectory()); err != nil {
-			log.Error().Err(err).Str("path", repo.GetLocalDirectory()).Msg("could not remove cloned repository directory")
-		}
-	}()
 
 	blocked, err := t.getModifiedWorkspacesOnTargetBranch(ctx, mr, repo, triggeredWorkspaces)
 	if err != nil {
@@ -458,6 +594,44 @@ func (t *TFCTrigger) TriggerTFCEvents(ctx context.Context) (*TriggeredTFCWorkspa
 	return t.dispatchWorkspaces(ctx, triggeredWorkspaces, blocked, dispatch), nil
 }
 
+// autoPlanWorkspaces drops workspaces that opted out of planning on MR events.
+func autoPlanWorkspaces(workspaces []*TFCWorkspace) []*TFCWorkspace {
+	result := make([]*TFCWorkspace, 0, len(workspaces))
+	for _, ws := range workspaces {
+		if !ws.AutoPlan {
+			log.Debug().Str("ws", ws.Name).Msg("skipping workspace with autoPlan disabled")
+			continue
+		}
+		result = append(result, ws)
+	}
+	return result
+}
+
+// skipDraft reports whether a merge request event for mr should not plan
+// because mr is a draft. Drafts are pushed often, so they only plan when the
+// repository sets planDrafts; comments always run.
+func (t *TFCTrigger) skipDraft(ctx context.Context, mr vcs.MR) bool {
+	d, ok := mr.(vcs.DraftMR)
+	if !ok || !d.IsDraft() || t.GetTriggerSource() != MergeRequestEventTrigger {
+		return false
+	}
+	cfg, err := getProjectConfigFile(ctx, t.gl, t)
+	if err != nil {
+		log.Error().Err(err).Str("project", t.GetProjectNameWithNamespace()).Int("mergeRequestID", t.GetMergeRequestIID()).Msg("could not read project config, not planning draft merge request")
+		return true
+	}
+	return !cfg.PlanDrafts
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
 // workspaceDispatchFn runs (inline) or enqueues (fan-out) a single workspace.
 // Returning an error puts the workspace into status.Errored verbatim.
 type workspaceDispatchFn func(ctx context.Context, ws *TFCWorkspace) error
@@ -476,6 +650,11 @@ func (t *TFCTrigger) dispatchWorkspaces(ctx context.Context, workspaces []*TFCWo
 			})
 			continue
 		}
+		if err := tfc_api.CheckOrganization(t.tfc, ws.Organization); err != nil {
+			log.Warn().Str("ws", ws.Name).Str("org", ws.Organization).Msg("Ignoring workspace, no credentials for its organization.")
+			status.Errored = append(status.Errored, &ErroredWorkspace{Name: ws.Name, Error: err.Error()})
+			continue
+		}
 		if _, ok := blocked[ws.Name]; ok {
 			log.Info().Str("ws", ws.Name).Str("dir", ws.Dir).Strs("triggerDirs", ws.TriggerDirs).
 				Msg("Blocking workspace: relevant paths modified on target branch.")
@@ -521,7 +700,13 @@ func (t *TFCTrigger) TriggerCleanupEvent(ctx context.Context) error {
 	if err != nil {
 		return fmt.Errorf("could not read MergeRequest data from VCS API: %w", err)
 	}
-	triggeredWorkspaces, err := t.getTriggeredWorkspacesForRequest(ctx, mr)
+	if isFork(mr) {
+		t.configBranch = mr.GetTargetBranch()
+	}
+	lazy := t.newLazyRepo(mr)
+	defer lazy.cleanup()
+
+	triggeredWorkspaces, err := t.getTriggeredWorkspacesForRequest(ctx, mr, lazy)
 	if err != nil {
 		return fmt.Errorf("could not determine workspaces for merge cleanup. %w", err)
 	}
@@ -611,6 +796,12 @@ func (t *TFCTrigger) triggerRunForWorkspace(ctx context.Context, cfgWS *TFCWorks
 
 	// retrieve TFC workspace details, so we can sanity check this request.
 	ws, err := t.tfc.GetWorkspaceByName(ctx, org, wsName)
+	if errors.Is(err, tfe.ErrResourceNotFound) && cfgWS.Create != nil && t.GetAction() == PlanAction {
+		ws, err = t.createWorkspace(ctx, cfgWS)
+		if err != nil {
+			return fmt.Errorf("could not create Workspace. %w", err)
+		}
+	}
 	if err != nil {
 		return fmt.Errorf("could not get Workspace from TFC API. %w", err)
 	}
@@ -645,6 +836,10 @@ func (t *TFCTrigger) triggerRunForWorkspace(ctx context.Context, cfgWS *TFCWorks
 		return nil
 	}
 
+	if t.GetAction() == OverridePolicyAction {
+		return t.overridePolicy(ctx, ws, mr)
+	}
+
 	pkgDir := filepath.Join(cloneDir, cfgWS.Dir)
 	if ws.WorkingDirectory != "" {
 		// The TFC workspace is configured with a working directory, so we need to send it the whole repo.
@@ -657,9 +852,13 @@ func (t *TFCTrigger) triggerRunForWorkspace(ctx context.Context, cfgWS *TFCWorks
 	} else if t.GetAction() != PlanAction {
 		return fmt.Errorf("run action was not apply or plan. %w", err)
 	}
+	t.ensureNotifications(ctx, org, ws)
 	// If the workspace is locked tell the user and don't queue a run
 	// Otherwise, TFC wil queue an apply, which might put them out of order
 	if isApply {
+		if err := t.checkCostDelta(ctx, ws); err != nil {
+			return err
+		}
 		lockingMR := t.getLockingMR(ctx, ws.ID)
 		if ws.Locked {
 			// Surface the tag-based locking MR too if we have one, so the user
@@ -725,12 +924,12 @@ func (t *TFCTrigger) triggerRunForWorkspace(ctx context.Context, cfgWS *TFCWorks
 	run, err := t.tfc.CreateRunFromSource(ctx, &tfc_api.ApiRunOptions{
 		IsApply:       isApply,
 		Path:          pkgDir,
-		Message:       fmt.Sprintf("MR [!%d]: %s", t.GetMergeRequestIID(), mr.GetTitle()),
+		Message:       runMessagePrefix(t.GetMergeRequestIID()) + mr.GetTitle(),
 		Organization:  org,
 		Workspace:     wsName,
-		TFVersion:     t.cfg.TFVersion,
-		Target:        t.cfg.Target,
-		AllowEmptyRun: t.cfg.AllowEmptyRun,
+		TFVersion:     firstNonEmpty(t.cfg.TFVersion, cfgWS.TerraformVersion),
+		Target:        firstNonEmpty(t.cfg.Target, cfgWS.Target),
+		AllowEmptyRun: t.cfg.AllowEmptyRun || cfgWS.AllowEmptyRun,
 	})
 	if err != nil {
 		return fmt.Errorf("could not create TFC run. %w", err)
@@ -744,6 +943,9 @@ func (t *TFCTrigger) triggerRunForWorkspace(ctx context.Context, cfgWS *TFCWorks
 		Bool("speculative", run.ConfigurationVersion.Speculative).
 		Msg("created TFC run")
 
+	if !isApply {
+		t.cancelSupersededRuns(ctx, ws, run)
+	}
 	return t.publishRunToStream(ctx, run, cfgWS, discussionID, rootNoteID)
 }
 
@@ -9,6 +9,7 @@ import (
 	"time"
 
 	"github.com/hashicorp/go-tfe"
+	"github.com/nats-io/nats.go"
 	"github.com/rs/zerolog/log"
 	"github.com/rzajac/zltest"
 	"github.com/zapier/tfbuddy/internal/config"
@@ -1262,3 +1263,507 @@ func TestTFCEvents_UnlockAlreadyUnlocked_StillRemovesTags(t *testing.T) {
 		t.Fatal("expected unlock to still proceed and remove tags")
 	}
 }
+
+func TestTFCEvents_AutoPlanDisabled_SkipsMergeRequestEvents(t *testing.T) {
+	ws := &tfc_trigger.ProjectConfig{
+		Workspaces: []*tfc_trigger.TFCWorkspace{{
+			Name:         "service-tfbuddy",
+			Organization: "zapier-test",
+			Mode:         "apply-before-merge",
+		}}}
+
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	testSuite := mocks.CreateTestSuite(mockCtrl, mocks.TestOverrides{ProjectConfig: ws}, t)
+	// autoPlan defaults to true, so it must be written out explicitly.
+	testSuite.MetaData.TFBuddyConfig = []byte(`
+workspaces:
+  - name: service-tfbuddy
+    organization: zapier-test
+    autoPlan: false
+`)
+	testSuite.MockApiClient.EXPECT().CreateRunFromSource(gomock.Any(), gomock.Any()).Times(0)
+	testSuite.InitTestSuite()
+
+	tCfg, _ := tfc_trigger.NewTFCTriggerConfig(&tfc_trigger.TFCTriggerOptions{
+		Action:                   tfc_trigger.PlanAction,
+		Branch:                   testSuite.MetaData.SourceBranch,
+		CommitSHA:                "abcd12233",
+		ProjectNameWithNamespace: testSuite.MetaData.ProjectNameNS,
+		MergeRequestIID:          testSuite.MetaData.MRIID,
+		TriggerSource:            tfc_trigger.MergeRequestEventTrigger,
+	})
+	trigger := tfc_trigger.NewTFCTrigger(config.C, testSuite.MockGitClient, testSuite.MockApiClient, testSuite.MockStreamClient, tCfg)
+	triggeredWS, err := trigger.TriggerTFCEvents(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(triggeredWS.Executed) != 0 || len(triggeredWS.Errored) != 0 {
+		t.Fatal("expected no workspaces to be triggered", triggeredWS)
+	}
+}
+
+// draftMR is a draft merge request.
+type draftMR struct {
+	*mocks.MockDetailedMR
+}
+
+func (draftMR) IsDraft() bool { return true }
+
+func TestTFCEvents_DraftMergeRequests(t *testing.T) {
+	tests := []struct {
+		name       string
+		planDrafts bool
+		source     tfc_trigger.TriggerSource
+		wantRun    bool
+	}{
+		{name: "merge request event", source: tfc_trigger.MergeRequestEventTrigger},
+		{name: "merge request event with planDrafts", planDrafts: true, source: tfc_trigger.MergeRequestEventTrigger, wantRun: true},
+		{name: "comment", source: tfc_trigger.CommentTrigger, wantRun: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			testSuite := mocks.CreateTestSuite(mockCtrl, mocks.TestOverrides{}, t)
+			testSuite.MetaData.TFBuddyConfig = []byte(fmt.Sprintf(`
+planDrafts: %t
+workspaces:
+  - name: service-tfbuddy
+    organization: zapier-test
+`, tt.planDrafts))
+			testSuite.MockGitClient.EXPECT().GetMergeRequest(gomock.Any(), testSuite.MetaData.MRIID, testSuite.MetaData.ProjectNameNS).
+				Return(draftMR{testSuite.MockGitMR}, nil).AnyTimes()
+			testSuite.InitTestSuite()
+
+			tCfg, _ := tfc_trigger.NewTFCTriggerConfig(&tfc_trigger.TFCTriggerOptions{
+				Action:                   tfc_trigger.PlanAction,
+				Branch:                   testSuite.MetaData.SourceBranch,
+				CommitSHA:                "abcd12233",
+				ProjectNameWithNamespace: testSuite.MetaData.ProjectNameNS,
+				MergeRequestIID:          testSuite.MetaData.MRIID,
+				TriggerSource:            tt.source,
+			})
+			pub := &fakeWorkspacePublisher{}
+			trigger := tfc_trigger.NewTFCTrigger(config.C, testSuite.MockGitClient, testSuite.MockApiClient, testSuite.MockStreamClient, tCfg)
+			trigger.SetWorkspaceStream(pub)
+			if _, err := trigger.TriggerTFCEvents(context.Background()); err != nil {
+				t.Fatal(err)
+			}
+			if got := len(pub.names()) == 1; got != tt.wantRun {
+				t.Fatalf("planned workspaces %v, want run %v", pub.names(), tt.wantRun)
+			}
+		})
+	}
+}
+
+func TestTFCEvents_WorkspaceRunDefaults(t *testing.T) {
+	ws := &tfc_trigger.ProjectConfig{
+		Workspaces: []*tfc_trigger.TFCWorkspace{{
+			Name:             "service-tfbuddy",
+			Organization:     "zapier-test",
+			Mode:             "apply-before-merge",
+			TerraformVersion: "1.9.8",
+			Target:           "module.db",
+			AllowEmptyRun:    true,
+		}}}
+
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	testSuite := mocks.CreateTestSuite(mockCtrl, mocks.TestOverrides{ProjectConfig: ws}, t)
+	testSuite.MockGitClient.EXPECT().CreateMergeRequestDiscussion(gomock.Any(), testSuite.MetaData.MRIID, testSuite.MetaData.ProjectNameNS, gomock.Any()).Return(testSuite.MockGitDisc, nil)
+	testSuite.MockApiClient.EXPECT().CreateRunFromSource(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, opts *tfc_api.ApiRunOptions) (*tfe.Run, error) {
+		if opts.TFVersion != "1.7.0" {
+			t.Errorf("expected comment TFVersion to take precedence, got %q", opts.TFVersion)
+		}
+		if opts.Target != "module.db" {
+			t.Errorf("expected workspace default Target, got %q", opts.Target)
+		}
+		if !opts.AllowEmptyRun {
+			t.Error("expected workspace default AllowEmptyRun")
+		}
+		return &tfe.Run{
+			ID: "101",
+			Workspace: &tfe.Workspace{Name: "service-tfbuddy",
+				Organization: &tfe.Organization{Name: "zapier-test"},
+			},
+			ConfigurationVersion: &tfe.ConfigurationVersion{Speculative: true}}, nil
+	})
+
+	mockRunPollingTask := mocks.NewMockRunPollingTask(mockCtrl)
+	mockRunPollingTask.EXPECT().Schedule(gomock.Any())
+	testSuite.MockStreamClient.EXPECT().NewTFRunPollingTask(gomock.Any(), time.Second*1).Return(mockRunPollingTask)
+
+	testSuite.InitTestSuite()
+
+	tCfg, _ := tfc_trigger.NewTFCTriggerConfig(&tfc_trigger.TFCTriggerOptions{
+		Action:                   tfc_trigger.PlanAction,
+		Branch:                   testSuite.MetaData.SourceBranch,
+		CommitSHA:                "abcd12233",
+		ProjectNameWithNamespace: testSuite.MetaData.ProjectNameNS,
+		MergeRequestIID:          testSuite.MetaData.MRIID,
+		TriggerSource:            tfc_trigger.CommentTrigger,
+		TFVersion:                "1.7.0",
+	})
+	trigger := tfc_trigger.NewTFCTrigger(config.C, testSuite.MockGitClient, testSuite.MockApiClient, testSuite.MockStreamClient, tCfg)
+	triggeredWS, err := trigger.TriggerTFCEvents(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(triggeredWS.Executed) != 1 {
+		t.Fatal("expected a single TF workspace run", triggeredWS.Errored)
+	}
+}
+
+func TestTFCEvents_CreateMissingWorkspace(t *testing.T) {
+	tests := []struct {
+		name        string
+		createOrgs  []string
+		missingSets bool
+		wantCreated bool
+	}{
+		{name: "allowed organization", createOrgs: []string{"Zapier-Test"}, wantCreated: true},
+		{name: "other organization", createOrgs: []string{"zapier"}},
+		{name: "missing variable set", createOrgs: []string{"zapier-test"}, missingSets: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ws := &tfc_trigger.ProjectConfig{
+				Workspaces: []*tfc_trigger.TFCWorkspace{{
+					Name:         "service-tfbuddy",
+					Organization: "zapier-test",
+					Mode:         "apply-before-merge",
+					Create: &tfc_trigger.TFCWorkspaceCreate{
+						Project:      "services",
+						VariableSets: []string{"aws-credentials"},
+					},
+				}}}
+
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			testSuite := mocks.CreateTestSuite(mockCtrl, mocks.TestOverrides{ProjectConfig: ws}, t)
+			testSuite.MockApiClient.EXPECT().GetWorkspaceByName(gomock.Any(), "zapier-test", "service-tfbuddy").Return(nil, tfe.ErrResourceNotFound)
+			if tt.missingSets {
+				testSuite.MockApiClient.EXPECT().CreateWorkspace(gomock.Any(), gomock.Any()).Return(nil,
+					&tfc_api.MissingVariableSetsError{Organization: "zapier-test", Names: []string{"aws-credentials"}})
+				testSuite.MockGitClient.EXPECT().CreateMergeRequestComment(gomock.Any(), testSuite.MetaData.MRIID, testSuite.MetaData.ProjectNameNS,
+					":no_entry: Could not create TFC workspace `zapier-test/service-tfbuddy`: variable sets `aws-credentials` do not exist in the organization.").Return(nil)
+			}
+			if tt.wantCreated {
+				testSuite.MockApiClient.EXPECT().CreateWorkspace(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, opts *tfc_api.ApiWorkspaceOptions) (*tfe.Workspace, error) {
+					if opts.Organization != "zapier-test" || opts.Name != "service-tfbuddy" || opts.Project != "services" {
+						t.Errorf("unexpected workspace options %+v", opts)
+					}
+					if len(opts.VariableSets) != 1 || opts.VariableSets[0] != "aws-credentials" {
+						t.Errorf("unexpected variable sets %v", opts.VariableSets)
+					}
+					return &tfe.Workspace{ID: "ws-new", Name: "service-tfbuddy"}, nil
+				})
+				testSuite.MockGitClient.EXPECT().CreateMergeRequestComment(gomock.Any(), testSuite.MetaData.MRIID, testSuite.MetaData.ProjectNameNS,
+					":sparkles: Created TFC workspace `zapier-test/service-tfbuddy` in project `services` with variable sets `aws-credentials`.").Return(nil)
+				testSuite.MockGitClient.EXPECT().CreateMergeRequestDiscussion(gomock.Any(), testSuite.MetaData.MRIID, testSuite.MetaData.ProjectNameNS, gomock.Any()).Return(testSuite.MockGitDisc, nil)
+				testSuite.MockApiClient.EXPECT().CreateRunFromSource(gomock.Any(), gomock.Any()).Return(&tfe.Run{
+					ID: "101",
+					Workspace: &tfe.Workspace{Name: "service-tfbuddy",
+						Organization: &tfe.Organization{Name: "zapier-test"},
+					},
+					ConfigurationVersion: &tfe.ConfigurationVersion{Speculative: true}}, nil)
+				mockRunPollingTask := mocks.NewMockRunPollingTask(mockCtrl)
+				mockRunPollingTask.EXPECT().Schedule(gomock.Any())
+				testSuite.MockStreamClient.EXPECT().NewTFRunPollingTask(gomock.Any(), time.Second*1).Return(mockRunPollingTask)
+			}
+			testSuite.InitTestSuite()
+
+			appCfg := config.C
+			appCfg.TFCWorkspaceCreateOrgs = tt.createOrgs
+			tCfg, _ := tfc_trigger.NewTFCTriggerConfig(&tfc_trigger.TFCTriggerOptions{
+				Action:                   tfc_trigger.PlanAction,
+				Branch:                   testSuite.MetaData.SourceBranch,
+				CommitSHA:                "abcd12233",
+				ProjectNameWithNamespace: testSuite.MetaData.ProjectNameNS,
+				MergeRequestIID:          testSuite.MetaData.MRIID,
+				TriggerSource:            tfc_trigger.CommentTrigger,
+			})
+			trigger := tfc_trigger.NewTFCTrigger(appCfg, testSuite.MockGitClient, testSuite.MockApiClient, testSuite.MockStreamClient, tCfg)
+			triggeredWS, err := trigger.TriggerTFCEvents(context.Background())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tt.wantCreated && len(triggeredWS.Executed) != 1 {
+				t.Fatal("expected a single TF workspace run", triggeredWS.Errored)
+			}
+			if !tt.wantCreated && len(triggeredWS.Errored) != 1 {
+				t.Fatal("expected the missing workspace to fail", triggeredWS.Executed)
+			}
+		})
+	}
+}
+
+func TestTFCEvents_ApplyCostDeltaThreshold(t *testing.T) {
+	tests := []struct {
+		name        string
+		delta       string
+		status      tfe.CostEstimateStatus
+		planCommit  string
+		acceptCost  bool
+		wantApplied bool
+	}{
+		{name: "below threshold", delta: "50.0", wantApplied: true},
+		{name: "above threshold", delta: "150.5"},
+		{name: "above threshold accepted", delta: "150.5", acceptCost: true, wantApplied: true},
+		{name: "cost decrease", delta: "-500.0", wantApplied: true},
+		{name: "estimate pending", delta: "50.0", status: tfe.CostEstimatePending},
+		// the cheap plan of an older commit does not approve the apply
+		{name: "no plan of the commit", delta: "50.0", planCommit: "0ld5ha"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ws := &tfc_trigger.ProjectConfig{
+				Workspaces: []*tfc_trigger.TFCWorkspace{{
+					Name:         "service-tfbuddy",
+					Organization: "zapier-test",
+					Mode:         "apply-before-merge",
+				}}}
+
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			testSuite := mocks.CreateTestSuite(mockCtrl, mocks.TestOverrides{ProjectConfig: ws}, t)
+			testSuite.MockGitRepo.EXPECT().GetModifiedFileNamesBetweenCommits(testSuite.MetaData.CommonSHA, "main").Return([]string{}, nil)
+			if !tt.acceptCost {
+				status := tt.status
+				if status == "" {
+					status = tfe.CostEstimateFinished
+				}
+				planCommit := tt.planCommit
+				if planCommit == "" {
+					planCommit = "abcd12233"
+				}
+				testSuite.MockApiClient.EXPECT().ListSpeculativeRuns(gomock.Any(), "service-tfbuddy", fmt.Sprintf("MR [!%d]: ", testSuite.MetaData.MRIID)).Return([]*tfe.Run{
+					{ID: "run-2", CostEstimate: &tfe.CostEstimate{Status: status, DeltaMonthlyCost: tt.delta}},
+					{ID: "run-1", CostEstimate: &tfe.CostEstimate{Status: tfe.CostEstimateFinished, DeltaMonthlyCost: "0.0"}},
+				}, nil)
+				testSuite.MockStreamClient.EXPECT().GetRunMeta("run-2").Return(&runstream.TFRunMetadata{
+					MergeRequestProjectNameWithNamespace: testSuite.MetaData.ProjectNameNS,
+					MergeRequestIID:                      testSuite.MetaData.MRIID,
+					CommitSHA:                            planCommit,
+				}, nil)
+				testSuite.MockStreamClient.EXPECT().GetRunMeta("run-1").Return(&runstream.TFRunMetadata{
+					MergeRequestProjectNameWithNamespace: testSuite.MetaData.ProjectNameNS,
+					MergeRequestIID:                      testSuite.MetaData.MRIID,
+					CommitSHA:                            "0ld5ha",
+				}, nil).AnyTimes()
+			}
+			if tt.wantApplied {
+				testSuite.MockApiClient.EXPECT().CreateRunFromSource(gomock.Any(), gomock.Any()).Return(&tfe.Run{
+					ID: "101",
+					Workspace: &tfe.Workspace{Name: "service-tfbuddy",
+						Organization: &tfe.Organization{Name: "zapier-test"},
+					},
+					ConfigurationVersion: &tfe.ConfigurationVersion{Speculative: false}}, nil)
+				testSuite.MockStreamClient.EXPECT().AddRunMeta(gomock.Any())
+			}
+			testSuite.InitTestSuite()
+
+			appCfg := config.C
+			appCfg.TFCCostDeltaThreshold = 100
+			tCfg, _ := tfc_trigger.NewTFCTriggerConfig(&tfc_trigger.TFCTriggerOptions{
+				Action:                   tfc_trigger.ApplyAction,
+				Branch:                   "test-branch",
+				CommitSHA:                "abcd12233",
+				ProjectNameWithNamespace: testSuite.MetaData.ProjectNameNS,
+				MergeRequestIID:          testSuite.MetaData.MRIID,
+				TriggerSource:            tfc_trigger.CommentTrigger,
+				AcceptCost:               tt.acceptCost,
+			})
+			trigger := tfc_trigger.NewTFCTrigger(appCfg, testSuite.MockGitClient, testSuite.MockApiClient, testSuite.MockStreamClient, tCfg)
+			triggeredWS, err := trigger.TriggerTFCEvents(context.Background())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tt.wantApplied {
+				if len(triggeredWS.Executed) != 1 {
+					t.Fatal("expected the workspace to be applied", triggeredWS.Errored)
+				}
+				return
+			}
+			if len(triggeredWS.Errored) != 1 || !strings.Contains(triggeredWS.Errored[0].Error, "--accept-cost") {
+				t.Fatal("expected the apply to require --accept-cost", triggeredWS.Errored)
+			}
+		})
+	}
+}
+
+func TestTFCEvents_OverridePolicy(t *testing.T) {
+	ours := &runstream.TFRunMetadata{MergeRequestProjectNameWithNamespace: "zapier/tfbuddy", MergeRequestIID: 101}
+	// same MR number in another repository sharing the workspace
+	otherProject := &runstream.TFRunMetadata{MergeRequestProjectNameWithNamespace: "zapier/other", MergeRequestIID: 101}
+	tests := []struct {
+		name         string
+		commenter    string
+		runs         []*tfe.Run
+		meta         map[string]*runstream.TFRunMetadata
+		wantOverride string
+		wantErrored  string
+	}{
+		{
+			name:         "allowed user",
+			commenter:    "Security-Lead",
+			runs:         []*tfe.Run{{ID: "run-other"}, {ID: "run-7"}},
+			meta:         map[string]*runstream.TFRunMetadata{"run-other": otherProject, "run-7": ours},
+			wantOverride: "run-7",
+		},
+		{name: "other user", commenter: "developer", wantErrored: tfc_trigger.ErrPolicyOverrideNotAllowed.Error()},
+		{name: "no run awaiting override", commenter: "security-lead", wantErrored: tfc_trigger.ErrNoRunAwaitingOverride.Error()},
+		{
+			name:        "run of another project",
+			commenter:   "security-lead",
+			runs:        []*tfe.Run{{ID: "run-other"}},
+			meta:        map[string]*runstream.TFRunMetadata{"run-other": otherProject},
+			wantErrored: tfc_trigger.ErrNoRunAwaitingOverride.Error(),
+		},
+		{
+			name:        "run without metadata",
+			commenter:   "security-lead",
+			runs:        []*tfe.Run{{ID: "run-8"}},
+			wantErrored: tfc_trigger.ErrNoRunAwaitingOverride.Error(),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ws := &tfc_trigger.ProjectConfig{
+				Workspaces: []*tfc_trigger.TFCWorkspace{{
+					Name:         "service-tfbuddy",
+					Organization: "zapier-test",
+					Mode:         "apply-before-merge",
+				}}}
+
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			testSuite := mocks.CreateTestSuite(mockCtrl, mocks.TestOverrides{ProjectConfig: ws}, t)
+			testSuite.MockGitRepo.EXPECT().GetModifiedFileNamesBetweenCommits(testSuite.MetaData.CommonSHA, "main").Return([]string{}, nil)
+			if tt.wantErrored != tfc_trigger.ErrPolicyOverrideNotAllowed.Error() {
+				testSuite.MockApiClient.EXPECT().ListRunsAwaitingPolicyOverride(gomock.Any(), "service-tfbuddy", fmt.Sprintf("MR [!%d]: ", testSuite.MetaData.MRIID)).Return(tt.runs, nil)
+			}
+			for _, run := range tt.runs {
+				if rmd, ok := tt.meta[run.ID]; ok {
+					testSuite.MockStreamClient.EXPECT().GetRunMeta(run.ID).Return(rmd, nil)
+				} else {
+					testSuite.MockStreamClient.EXPECT().GetRunMeta(run.ID).Return(nil, nats.ErrKeyNotFound)
+				}
+				if run.ID == tt.wantOverride {
+					break
+				}
+			}
+			if tt.wantOverride != "" {
+				testSuite.MockApiClient.EXPECT().OverridePolicy(gomock.Any(), tt.wantOverride, gomock.Any()).Return(nil)
+				testSuite.MockGitClient.EXPECT().CreateMergeRequestComment(gomock.Any(), testSuite.MetaData.MRIID, testSuite.MetaData.ProjectNameNS, gomock.Any()).Return(nil)
+			}
+			testSuite.InitTestSuite()
+
+			appCfg := config.C
+			appCfg.TFCPolicyOverrideUsers = []string{"security-lead"}
+			tCfg, _ := tfc_trigger.NewTFCTriggerConfig(&tfc_trigger.TFCTriggerOptions{
+				Action:                   tfc_trigger.OverridePolicyAction,
+				Branch:                   testSuite.MetaData.SourceBranch,
+				CommitSHA:                "abcd12233",
+				ProjectNameWithNamespace: testSuite.MetaData.ProjectNameNS,
+				MergeRequestIID:          testSuite.MetaData.MRIID,
+				TriggerSource:            tfc_trigger.CommentTrigger,
+				Commenter:                tt.commenter,
+			})
+			trigger := tfc_trigger.NewTFCTrigger(appCfg, testSuite.MockGitClient, testSuite.MockApiClient, testSuite.MockStreamClient, tCfg)
+			triggeredWS, err := trigger.TriggerTFCEvents(context.Background())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tt.wantErrored == "" {
+				if len(triggeredWS.Executed) != 1 {
+					t.Fatal("expected the override to succeed", triggeredWS.Errored)
+				}
+				return
+			}
+			if len(triggeredWS.Errored) != 1 || !strings.HasSuffix(triggeredWS.Errored[0].Error, tt.wantErrored) {
+				t.Fatalf("expected error %q, got %+v", tt.wantErrored, triggeredWS.Errored[0])
+			}
+		})
+	}
+}
+
+func TestTFCEvents_CancelSupersededPlans(t *testing.T) {
+	for _, enabled := range []bool{true, false} {
+		t.Run(fmt.Sprintf("enabled=%t", enabled), func(t *testing.T) {
+			ws := &tfc_trigger.ProjectConfig{
+				Workspaces: []*tfc_trigger.TFCWorkspace{{
+					Name:         "service-tfbuddy",
+					Organization: "zapier-test",
+					Mode:         "apply-before-merge",
+					AutoPlan:     true,
+				}}}
+
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			testSuite := mocks.CreateTestSuite(mockCtrl, mocks.TestOverrides{ProjectConfig: ws}, t)
+			testSuite.MockGitClient.EXPECT().CreateMergeRequestDiscussion(gomock.Any(), testSuite.MetaData.MRIID, testSuite.MetaData.ProjectNameNS, gomock.Any()).Return(testSuite.MockGitDisc, nil)
+			now := time.Now()
+			testSuite.MockApiClient.EXPECT().CreateRunFromSource(gomock.Any(), gomock.Any()).Return(&tfe.Run{
+				ID:        "run-new",
+				CreatedAt: now,
+				Workspace: &tfe.Workspace{Name: "service-tfbuddy",
+					Organization: &tfe.Organization{Name: "zapier-test"},
+				},
+				ConfigurationVersion: &tfe.ConfigurationVersion{Speculative: true}}, nil)
+			mockRunPollingTask := mocks.NewMockRunPollingTask(mockCtrl)
+			mockRunPollingTask.EXPECT().Schedule(gomock.Any())
+			testSuite.MockStreamClient.EXPECT().NewTFRunPollingTask(gomock.Any(), time.Second*1).Return(mockRunPollingTask)
+
+			if enabled {
+				active := &tfe.RunActions{IsCancelable: true}
+				older := now.Add(-time.Minute)
+				testSuite.MockApiClient.EXPECT().ListSpeculativeRuns(gomock.Any(), "service-tfbuddy", fmt.Sprintf("MR [!%d]: ", testSuite.MetaData.MRIID)).Return([]*tfe.Run{
+					{ID: "run-new", Actions: active, CreatedAt: now},
+					// delivered out of order, started after the new run
+					{ID: "run-newer", Actions: active, CreatedAt: now.Add(time.Minute)},
+					{ID: "run-same-commit", Actions: active, CreatedAt: older},
+					{ID: "run-no-metadata", Actions: active, CreatedAt: older},
+					{ID: "run-other-project", Actions: active, CreatedAt: older},
+					{ID: "run-old", Actions: active, CreatedAt: older},
+					{ID: "run-finished", Actions: &tfe.RunActions{}, CreatedAt: older},
+				}, nil)
+				testSuite.MockStreamClient.EXPECT().GetRunMeta("run-same-commit").Return(&runstream.TFRunMetadata{RunID: "run-same-commit", CommitSHA: "abcd12233", MergeRequestProjectNameWithNamespace: testSuite.MetaData.ProjectNameNS, MergeRequestIID: testSuite.MetaData.MRIID}, nil)
+				testSuite.MockStreamClient.EXPECT().GetRunMeta("run-no-metadata").Return(nil, nats.ErrKeyNotFound)
+				testSuite.MockStreamClient.EXPECT().GetRunMeta("run-other-project").Return(&runstream.TFRunMetadata{RunID: "run-other-project", CommitSHA: "0ld5ha", MergeRequestProjectNameWithNamespace: "zapier/other", MergeRequestIID: testSuite.MetaData.MRIID, DiscussionID: "disc-other"}, nil)
+				testSuite.MockStreamClient.EXPECT().GetRunMeta("run-old").Return(&runstream.TFRunMetadata{RunID: "run-old", CommitSHA: "0ld5ha", MergeRequestProjectNameWithNamespace: testSuite.MetaData.ProjectNameNS, MergeRequestIID: testSuite.MetaData.MRIID, DiscussionID: "disc-old"}, nil)
+				testSuite.MockApiClient.EXPECT().CancelRun(gomock.Any(), "run-old", gomock.Any()).Return(nil)
+				testSuite.MockStreamClient.EXPECT().StopTFRunPollingTask("run-old").Return(nil)
+				testSuite.MockGitClient.EXPECT().AddMergeRequestDiscussionReply(gomock.Any(), testSuite.MetaData.MRIID, testSuite.MetaData.ProjectNameNS, "disc-old", gomock.Any()).
+					DoAndReturn(func(_ context.Context, _ int, _, _, comment string) (*mocks.MockMRNote, error) {
+						if !strings.Contains(comment, "Superseded") || !strings.Contains(comment, "run-new") {
+							t.Errorf("unexpected superseded comment %q", comment)
+						}
+						return testSuite.MockMRNote, nil
+					})
+				testSuite.MockGitClient.EXPECT().ResolveMergeRequestDiscussion(gomock.Any(), testSuite.MetaData.ProjectNameNS, testSuite.MetaData.MRIID, "disc-old").Return(nil)
+			}
+			testSuite.InitTestSuite()
+
+			appCfg := config.C
+			appCfg.TFCCancelSupersededPlans = enabled
+			tCfg, _ := tfc_trigger.NewTFCTriggerConfig(&tfc_trigger.TFCTriggerOptions{
+				Action:                   tfc_trigger.PlanAction,
+				Branch:                   testSuite.MetaData.SourceBranch,
+				CommitSHA:                "abcd12233",
+				ProjectNameWithNamespace: testSuite.MetaData.ProjectNameNS,
+				MergeRequestIID:          testSuite.MetaData.MRIID,
+				TriggerSource:            tfc_trigger.MergeRequestEventTrigger,
+			})
+			trigger := tfc_trigger.NewTFCTrigger(appCfg, testSuite.MockGitClient, testSuite.MockApiClient, testSuite.MockStreamClient, tCfg)
+			triggeredWS, err := trigger.TriggerTFCEvents(context.Background())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(triggeredWS.Executed) != 1 {
+				t.Fatal("expected a single TF workspace run", triggeredWS.Errored)
+			}
+		})
+	}
+}
@@ -0,0 +1,68 @@
+package tfc_trigger
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/go-tfe"
+	"github.com/rs/zerolog/log"
+	"github.com/zapier/tfbuddy/internal/config"
+	"github.com/zapier/tfbuddy/pkg/tfc_api"
+)
+
+// isWorkspaceCreationAllowed reports whether TFBuddy may create workspaces in
+// org, i.e. whether it is in `tfc-workspace-create-organizations`.
+func isWorkspaceCreationAllowed(cfg config.Config, org string) bool {
+	for _, allowed := range cfg.TFCWorkspaceCreateOrgs {
+		if strings.EqualFold(strings.TrimSpace(allowed), org) {
+			return true
+		}
+	}
+	return false
+}
+
+// createWorkspace creates the TFC workspace described by the create block of
+// cfgWS, and tells the merge request about it.
+func (t *TFCTrigger) createWorkspace(ctx context.Context, cfgWS *TFCWorkspace) (*tfe.Workspace, error) {
+	org := cfgWS.Organization
+	if !isWorkspaceCreationAllowed(t.appCfg, org) {
+		return nil, fmt.Errorf("workspace %s/%s does not exist, and TFBuddy is not allowed to create workspaces in organization %s", org, cfgWS.Name, org)
+	}
+	create := cfgWS.Create
+	ws, err := t.tfc.CreateWorkspace(ctx, &tfc_api.ApiWorkspaceOptions{
+		Organization:     org,
+		Name:             cfgWS.Name,
+		Project:          create.Project,
+		TerraformVersion: create.TerraformVersion,
+		ExecutionMode:    create.ExecutionMode,
+		AgentPoolID:      create.AgentPoolID,
+		WorkingDirectory: create.WorkingDirectory,
+		Tags:             create.Tags,
+		VariableSets:     create.VariableSets,
+	})
+	var missing *tfc_api.MissingVariableSetsError
+	if errors.As(err, &missing) {
+		msg := fmt.Sprintf(":no_entry: Could not create TFC workspace `%s/%s`: variable sets `%s` do not exist in the organization.", org, cfgWS.Name, strings.Join(missing.Names, "`, `"))
+		if err := t.postUpdate(ctx, msg); err != nil {
+			log.Error().Err(err).Msg("could not post the missing variable sets to the merge request")
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	log.Info().Str("org", org).Str("workspace", cfgWS.Name).Msg("created TFC workspace")
+
+	msg := fmt.Sprintf(":sparkles: Created TFC workspace `%s/%s`", org, cfgWS.Name)
+	if create.Project != "" {
+		msg += fmt.Sprintf(" in project `%s`", create.Project)
+	}
+	if len(create.VariableSets) > 0 {
+		msg += fmt.Sprintf(" with variable sets `%s`", strings.Join(create.VariableSets, "`, `"))
+	}
+	if err := t.postUpdate(ctx, msg+"."); err != nil {
+		log.Error().Err(err).Msg("could not post the workspace creation to the merge request")
+	}
+	return ws, nil
+}
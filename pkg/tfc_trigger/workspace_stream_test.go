@@ -54,6 +54,7 @@ func buildFanoutWorkspaces(n int) *tfc_trigger.ProjectConfig {
 			Organization: "zapier-test",
 			Mode:         "apply-before-merge",
 			Dir:          fmt.Sprintf("services/svc%02d/", i),
+			AutoPlan:     true,
 		})
 	}
 	return &tfc_trigger.ProjectConfig{Workspaces: wss}
@@ -121,6 +121,11 @@ func (w *WorkspaceTriggerWorker) runWorkspace(ctx context.Context, gl vcs.GitCli
 	if err != nil {
 		return fmt.Errorf("could not read MergeRequest data from VCS API: %w", err)
 	}
+	if isFork(mr) {
+		if err := trigger.checkForkPolicy(ctx, mr); err != nil {
+			return err
+		}
+	}
 
 	repo, err := trigger.cloneGitRepo(ctx, mr)
 	if err != nil {
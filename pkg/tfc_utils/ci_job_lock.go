@@ -5,6 +5,8 @@ import (
 
 	tfe "github.com/hashicorp/go-tfe"
 	"github.com/rs/zerolog/log"
+	"github.com/zapier/tfbuddy/internal/config"
+	"github.com/zapier/tfbuddy/pkg/tfc_api"
 	"go.opentelemetry.io/otel"
 )
 
@@ -14,13 +16,14 @@ func LockUnlockWorkspace(ctx context.Context, token, workspace string, lock bool
 
 	log.Info().Str("workspace", workspace).Msgf("LockUnlockWorkspace for workspace.")
 
-	config := &tfe.Config{
-		Token: token,
+	tfeConfig, err := tfc_api.NewTFEConfig(config.C, token)
+	if err != nil {
+		log.Fatal().Err(err).Msg("could not configure TFC client")
 	}
 
 	LockOptions := tfe.WorkspaceLockOptions{Reason: &lockReason}
 
-	client, err := tfe.NewClient(config)
+	client, err := tfe.NewClient(tfeConfig)
 	if err != nil {
 		log.Fatal().Err(err)
 	}
@@ -50,7 +50,7 @@ func MonitorRunStatus() {
 		return
 	}
 	glClient = gitlab.NewGitlabClient(cfg)
-	tfcClient = tfc_api.NewTFCClient()
+	tfcClient = tfc_api.NewTFCClient(cfg)
 	ctx := context.Background()
 
 	projectID := os.Getenv("CI_PROJECT_ID")
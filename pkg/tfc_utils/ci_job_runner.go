@@ -6,6 +6,8 @@ import (
 
 	tfe "github.com/hashicorp/go-tfe"
 	"github.com/rs/zerolog/log"
+	"github.com/zapier/tfbuddy/internal/config"
+	"github.com/zapier/tfbuddy/pkg/tfc_api"
 )
 
 const RUN_MESSAGE_PREFIX = "GitLab CI Scheduled"
@@ -15,11 +17,11 @@ func StartScheduledRun(token, workspace string) {
 
 	log.Info().Str("workspace", workspace).Msgf("StartScheduledRun for workspace.")
 
-	config := &tfe.Config{
-		Token: token,
+	tfeConfig, err := tfc_api.NewTFEConfig(config.C, token)
+	if err != nil {
+		log.Fatal().Err(err).Msg("could not configure TFC client")
 	}
-
-	client, err := tfe.NewClient(config)
+	client, err := tfe.NewClient(tfeConfig)
 	if err != nil {
 		log.Fatal().Err(err)
 	}
@@ -148,5 +150,5 @@ func canCancelRun(run *tfe.Run) bool {
 }
 
 func printRunInfo(run *tfe.Run, title, wsName string) {
-	log.Printf(RunInfo, title, run.ID, run.Status, run.Source, run.Message, wsName, run.ID)
+	log.Printf(RunInfo, title, run.ID, run.Status, run.Source, run.Message, tfc_api.RunURL(config.C, ORG_NAME, wsName, run.ID))
 }
@@ -2,4 +2,4 @@ package tfc_utils
 
 const ORG_NAME = "foo-corp"
 
-const RunInfo = "%s:\n ID: %s	Status: %-12s	Source: %-20s	Commit Message: %s URL: https://app.terraform.io/app/zapier/workspaces/%s/runs/%s \n"
+const RunInfo = "%s:\n ID: %s	Status: %-12s	Source: %-20s	Commit Message: %s URL: %s \n"
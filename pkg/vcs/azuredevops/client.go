@@ -0,0 +1,764 @@
+package azuredevops
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cenkalti/backoff/v4"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/protocol/packp/sideband"
+	githttp "github.com/go-git/go-git/v5/plumbing/transport/http"
+	"github.com/rs/zerolog/log"
+	"github.com/zapier/tfbuddy/internal/config"
+	zgit "github.com/zapier/tfbuddy/pkg/git"
+	"github.com/zapier/tfbuddy/pkg/utils"
+	"github.com/zapier/tfbuddy/pkg/vcs"
+	"go.opentelemetry.io/otel"
+)
+
+// ensure type complies with interface
+var _ vcs.GitClient = (*Client)(nil)
+
+const DefaultMaxRetries = 3
+
+const AZURE_DEVOPS_CLONE_DEPTH_ENV = "TFBUDDY_AZURE_DEVOPS_CLONE_DEPTH"
+
+// apiVersion is sent with every REST API request.
+const apiVersion = "7.1"
+
+func createBackOffWithRetries() backoff.BackOff {
+	exp := backoff.NewExponentialBackOff()
+	exp.MaxElapsedTime = 30 * time.Second
+	return backoff.WithMaxRetries(exp, DefaultMaxRetries)
+}
+
+// Client talks to the Azure Repos REST API of a single organization (or
+// Azure DevOps Server collection). Repositories are named project/repo.
+type Client struct {
+	r     *requester
+	org   *url.URL
+	token string
+	cfg   config.Config
+
+	mu   sync.Mutex
+	self string
+}
+
+// NewAzureDevOpsClient creates a client for the organization at
+// `azure-devops-url`, authenticated with the personal access token in
+// AZURE_DEVOPS_TOKEN.
+func NewAzureDevOpsClient(cfg config.Config) *Client {
+	token := os.Getenv("AZURE_DEVOPS_TOKEN")
+	if token == "" {
+		log.Info().Msg("AZURE_DEVOPS_TOKEN is not set, skipping creation of Azure DevOps API client")
+		return nil
+	}
+	c, err := newClient(cfg, http.DefaultClient, token)
+	if err != nil {
+		log.Fatal().Err(err).Msg("could not create Azure DevOps client")
+	}
+	return c
+}
+
+func newClient(cfg config.Config, httpClient *http.Client, token string) (*Client, error) {
+	org, err := url.Parse(strings.TrimSuffix(cfg.AzureDevOpsURL, "/") + "/")
+	if err != nil || org.Host == "" {
+		return nil, fmt.Errorf("invalid azure devops organization url %q", cfg.AzureDevOpsURL)
+	}
+	return &Client{
+		r:     &requester{http: httpClient, token: token},
+		org:   org,
+		token: token,
+		cfg:   cfg,
+	}, nil
+}
+
+// ----------------------------------------------------------------------------
+// REST API types
+
+type identity struct {
+	ID          string `json:"id"`
+	UniqueName  string `json:"uniqueName"`
+	DisplayName string `json:"displayName"`
+}
+
+type apiPullRequest struct {
+	PullRequestID         int      `json:"pullRequestId"`
+	Title                 string   `json:"title"`
+	Status                string   `json:"status"`
+	CreatedBy             identity `json:"createdBy"`
+	SourceRefName         string   `json:"sourceRefName"`
+	TargetRefName         string   `json:"targetRefName"`
+	MergeStatus           string   `json:"mergeStatus"`
+	LastMergeSourceCommit struct {
+		CommitID string `json:"commitId"`
+	} `json:"lastMergeSourceCommit"`
+	// ForkSource is only set for pull requests from a fork.
+	ForkSource *struct {
+		Name string `json:"name"`
+	} `json:"forkSource"`
+	Reviewers []struct {
+		Vote int `json:"vote"`
+	} `json:"reviewers"`
+}
+
+type apiComment struct {
+	ID              int64     `json:"id"`
+	ParentCommentID int64     `json:"parentCommentId"`
+	Content         string    `json:"content"`
+	Author          identity  `json:"author"`
+	PublishedDate   time.Time `json:"publishedDate"`
+	IsDeleted       bool      `json:"isDeleted"`
+}
+
+func (c *apiComment) toComment(threadID int) *Comment {
+	return &Comment{
+		ID:              c.ID,
+		ThreadID:        threadID,
+		ParentCommentID: c.ParentCommentID,
+		Body:            c.Content,
+		AuthorID:        c.Author.ID,
+		CreatedAt:       c.PublishedDate,
+	}
+}
+
+type apiThread struct {
+	ID        int          `json:"id"`
+	Status    string       `json:"status"`
+	IsDeleted bool         `json:"isDeleted"`
+	Comments  []apiComment `json:"comments"`
+}
+
+func (t *apiThread) toThread() *Thread {
+	thread := &Thread{ID: t.ID, Status: t.Status}
+	for i := range t.Comments {
+		if t.Comments[i].IsDeleted {
+			continue
+		}
+		thread.Comments = append(thread.Comments, t.Comments[i].toComment(t.ID))
+	}
+	return thread
+}
+
+// ----------------------------------------------------------------------------
+
+// repoURL returns the URL of a Git API resource of the repository.
+func (c *Client) repoURL(repo string, elem ...string) (*url.URL, error) {
+	project, name, err := splitFullName(repo)
+	if err != nil {
+		return nil, err
+	}
+	return c.org.JoinPath(append([]string{project, "_apis", "git", "repositories", name}, elem...)...), nil
+}
+
+func (c *Client) pullRequestURL(repo string, prID int, elem ...string) (*url.URL, error) {
+	return c.repoURL(repo, append([]string{"pullRequests", strconv.Itoa(prID)}, elem...)...)
+}
+
+func (c *Client) threadURL(repo string, prID int, threadID string, elem ...string) (*url.URL, error) {
+	if _, err := strconv.Atoi(threadID); err != nil {
+		return nil, utils.CreatePermanentError(fmt.Errorf("invalid azure devops thread id %q", threadID))
+	}
+	return c.pullRequestURL(repo, prID, append([]string{"threads", threadID}, elem...)...)
+}
+
+func (c *Client) GetMergeRequestApprovals(ctx context.Context, id int, project string) (vcs.MRApproved, error) {
+	ctx, span := otel.Tracer("TFC").Start(ctx, "GetMergeRequestApprovals")
+	defer span.End()
+
+	return c.getPullRequest(ctx, project, id)
+}
+
+func (c *Client) GetMergeRequest(ctx context.Context, prID int, fullName string) (vcs.DetailedMR, error) {
+	ctx, span := otel.Tracer("hooks").Start(ctx, "GetMergeRequest")
+	defer span.End()
+
+	return c.getPullRequest(ctx, fullName, prID)
+}
+
+func (c *Client) getPullRequest(ctx context.Context, repo string, prID int) (*PullRequest, error) {
+	u, err := c.pullRequestURL(repo, prID)
+	if err != nil {
+		return nil, err
+	}
+	var pr apiPullRequest
+	if err := c.r.call(ctx, http.MethodGet, u, nil, &pr); err != nil {
+		return nil, err
+	}
+	project, name, _ := splitFullName(repo)
+	result := &PullRequest{
+		ID:           pr.PullRequestID,
+		Title:        pr.Title,
+		Status:       pr.Status,
+		Author:       pr.CreatedBy.UniqueName,
+		SourceBranch: strings.TrimPrefix(pr.SourceRefName, "refs/heads/"),
+		SourceCommit: pr.LastMergeSourceCommit.CommitID,
+		TargetBranch: strings.TrimPrefix(pr.TargetRefName, "refs/heads/"),
+		WebURL:       c.org.JoinPath(project, "_git", name, "pullrequest", strconv.Itoa(pr.PullRequestID)).String(),
+		MergeStatus:  pr.MergeStatus,
+		FromFork:     pr.ForkSource != nil,
+	}
+	for _, r := range pr.Reviewers {
+		result.Votes = append(result.Votes, r.Vote)
+	}
+	return result, nil
+}
+
+// CreateMergeRequestComment posts a comment in a new, closed thread so that
+// it does not count as an unresolved comment.
+func (c *Client) CreateMergeRequestComment(ctx context.Context, id int, fullPath string, comment string) error {
+	ctx, span := otel.Tracer("TFC").Start(ctx, "CreateMergeRequestComment")
+	defer span.End()
+
+	_, err := c.createThread(ctx, fullPath, id, comment, "closed")
+	return err
+}
+
+// CreateMergeRequestDiscussion starts an active thread, which is resolved
+// once the run it reports on finishes.
+func (c *Client) CreateMergeRequestDiscussion(ctx context.Context, mrID int, fullPath string, comment string) (vcs.MRDiscussionNotes, error) {
+	ctx, span := otel.Tracer("TFC").Start(ctx, "CreateMergeRequestDiscussion")
+	defer span.End()
+
+	return c.createThread(ctx, fullPath, mrID, comment, "active")
+}
+
+func (c *Client) createThread(ctx context.Context, repo string, prID int, comment, status string) (*Thread, error) {
+	if comment == "" {
+		return nil, utils.CreatePermanentError(fmt.Errorf("comment is empty"))
+	}
+	u, err := c.pullRequestURL(repo, prID, "threads")
+	if err != nil {
+		return nil, err
+	}
+	var thread apiThread
+	req := map[string]any{
+		"comments": []map[string]any{{"parentCommentId": 0, "content": comment, "commentType": "text"}},
+		"status":   status,
+	}
+	if err := c.r.call(ctx, http.MethodPost, u, req, &thread); err != nil {
+		return nil, err
+	}
+	return thread.toThread(), nil
+}
+
+func (c *Client) UpdateMergeRequestDiscussionNote(ctx context.Context, mrIID, noteID int, project, discussionID, comment string) (vcs.MRNote, error) {
+	ctx, span := otel.Tracer("TFC").Start(ctx, "UpdateMergeRequestDiscussionNote")
+	defer span.End()
+
+	u, err := c.threadURL(project, mrIID, discussionID, "comments", strconv.Itoa(noteID))
+	if err != nil {
+		return nil, err
+	}
+	var updated apiComment
+	if err := c.r.call(ctx, http.MethodPatch, u, map[string]string{"content": comment}, &updated); err != nil {
+		return nil, err
+	}
+	threadID, _ := strconv.Atoi(discussionID)
+	return updated.toComment(threadID), nil
+}
+
+// ResolveMergeRequestDiscussion marks the thread as resolved.
+func (c *Client) ResolveMergeRequestDiscussion(ctx context.Context, project string, mrIID int, discussionID string) error {
+	ctx, span := otel.Tracer("TFC").Start(ctx, "ResolveMergeRequestDiscussion")
+	defer span.End()
+
+	u, err := c.threadURL(project, mrIID, discussionID)
+	if err != nil {
+		return err
+	}
+	return c.r.call(ctx, http.MethodPatch, u, map[string]string{"status": "fixed"}, nil)
+}
+
+// AddMergeRequestDiscussionReply replies to the first comment of the thread.
+func (c *Client) AddMergeRequestDiscussionReply(ctx context.Context, mrIID int, project, discussionID, comment string) (vcs.MRNote, error) {
+	ctx, span := otel.Tracer("TFC").Start(ctx, "AddMergeRequestDiscussionReply")
+	defer span.End()
+
+	u, err := c.threadURL(project, mrIID, discussionID, "comments")
+	if err != nil {
+		return nil, err
+	}
+	var created apiComment
+	req := map[string]any{"parentCommentId": 1, "content": comment, "commentType": "text"}
+	if err := c.r.call(ctx, http.MethodPost, u, req, &created); err != nil {
+		return nil, err
+	}
+	threadID, _ := strconv.Atoi(discussionID)
+	return created.toComment(threadID), nil
+}
+
+func (c *Client) GetRepoFile(ctx context.Context, fullName string, file string, ref string) ([]byte, error) {
+	ctx, span := otel.Tracer("TFC").Start(ctx, "GetRepoFile")
+	defer span.End()
+
+	u, err := c.repoURL(fullName, "items")
+	if err != nil {
+		return nil, err
+	}
+	q := u.Query()
+	q.Set("path", "/"+strings.TrimPrefix(file, "/"))
+	q.Set("includeContent", "true")
+	if ref != "" {
+		q.Set("versionDescriptor.version", ref)
+		q.Set("versionDescriptor.versionType", versionType(ref))
+	}
+	u.RawQuery = q.Encode()
+
+	var item struct {
+		Content string `json:"content"`
+	}
+	if err := c.r.call(ctx, http.MethodGet, u, nil, &item); err != nil {
+		return nil, err
+	}
+	return []byte(item.Content), nil
+}
+
+var commitSHARegexp = regexp.MustCompile(`^[0-9a-f]{40}$`)
+
+// versionType tells a commit SHA from a branch name.
+func versionType(ref string) string {
+	if commitSHARegexp.MatchString(ref) {
+		return "commit"
+	}
+	return "branch"
+}
+
+// GetMergeRequestModifiedFiles returns the files changed by the latest
+// iteration (push) of the pull request, compared to its target branch.
+func (c *Client) GetMergeRequestModifiedFiles(ctx context.Context, prID int, fullName string) ([]string, error) {
+	ctx, span := otel.Tracer("TFC").Start(ctx, "GetMergeRequestModifiedFiles")
+	defer span.End()
+
+	u, err := c.pullRequestURL(fullName, prID, "iterations")
+	if err != nil {
+		return nil, err
+	}
+	var iterations struct {
+		Value []struct {
+			ID int `json:"id"`
+		} `json:"value"`
+	}
+	if err := c.r.call(ctx, http.MethodGet, u, nil, &iterations); err != nil {
+		return nil, err
+	}
+	if len(iterations.Value) == 0 {
+		return nil, nil
+	}
+	latest := iterations.Value[len(iterations.Value)-1].ID
+
+	var files []string
+	skip := 0
+	for {
+		u, err := c.pullRequestURL(fullName, prID, "iterations", strconv.Itoa(latest), "changes")
+		if err != nil {
+			return nil, err
+		}
+		q := u.Query()
+		q.Set("$top", "2000")
+		q.Set("$skip", strconv.Itoa(skip))
+		u.RawQuery = q.Encode()
+
+		var changes struct {
+			ChangeEntries []struct {
+				OriginalPath string `json:"originalPath"`
+				Item         struct {
+					Path     string `json:"path"`
+					IsFolder bool   `json:"isFolder"`
+				} `json:"item"`
+			} `json:"changeEntries"`
+			NextSkip int `json:"nextSkip"`
+		}
+		if err := c.r.call(ctx, http.MethodGet, u, nil, &changes); err != nil {
+			return nil, err
+		}
+		for _, change := range changes.ChangeEntries {
+			if change.Item.IsFolder {
+				continue
+			}
+			files = append(files, strings.TrimPrefix(change.Item.Path, "/"))
+			if change.OriginalPath != "" {
+				files = append(files, strings.TrimPrefix(change.OriginalPath, "/"))
+			}
+		}
+		if changes.NextSkip == 0 {
+			return files, nil
+		}
+		skip = changes.NextSkip
+	}
+}
+
+// SetCommitStatus posts a status on the commit.
+func (c *Client) SetCommitStatus(ctx context.Context, projectWithNS string, commitSHA string, status vcs.CommitStatusOptions) (vcs.CommitStatus, error) {
+	ctx, span := otel.Tracer("TFC").Start(ctx, "SetCommitStatus")
+	defer span.End()
+
+	u, err := c.repoURL(projectWithNS, "commits", commitSHA, "statuses")
+	if err != nil {
+		return nil, err
+	}
+	if err := c.r.call(ctx, http.MethodPost, u, statusRequest(status), nil); err != nil {
+		return nil, err
+	}
+	return &Status{Context: status.GetContext(), State: status.GetState()}, nil
+}
+
+// SetPullRequestStatus posts a status on the pull request, which branch
+// policies can require before the pull request is completed.
+func (c *Client) SetPullRequestStatus(ctx context.Context, project string, prID int, status vcs.CommitStatusOptions) (vcs.CommitStatus, error) {
+	ctx, span := otel.Tracer("TFC").Start(ctx, "SetPullRequestStatus")
+	defer span.End()
+
+	u, err := c.pullRequestURL(project, prID, "statuses")
+	if err != nil {
+		return nil, err
+	}
+	if err := c.r.call(ctx, http.MethodPost, u, statusRequest(status), nil); err != nil {
+		return nil, err
+	}
+	return &Status{Context: status.GetContext(), State: status.GetState()}, nil
+}
+
+// statusRequest splits the status context into the genre and name Azure
+// Repos expects.
+func statusRequest(status vcs.CommitStatusOptions) map[string]any {
+	genre, name, ok := strings.Cut(status.GetContext(), "/")
+	if !ok {
+		genre, name = "", genre
+	}
+	return map[string]any{
+		"state":       status.GetState(),
+		"description": status.GetDescription(),
+		"targetUrl":   status.GetTargetURL(),
+		"context":     map[string]string{"genre": genre, "name": name},
+	}
+}
+
+// GetPipelinesForCommit returns no pipelines: statuses are attached to the
+// pull request, not to a pipeline.
+func (c *Client) GetPipelinesForCommit(ctx context.Context, projectWithNS string, commitSHA string) ([]vcs.ProjectPipeline, error) {
+	return nil, nil
+}
+
+// MergeMR completes the pull request at its current source commit.
+func (c *Client) MergeMR(ctx context.Context, mrIID int, project string) error {
+	ctx, span := otel.Tracer("TFC").Start(ctx, "MergeMR")
+	defer span.End()
+
+	pr, err := c.getPullRequest(ctx, project, mrIID)
+	if err != nil {
+		return err
+	}
+	u, err := c.pullRequestURL(project, mrIID)
+	if err != nil {
+		return err
+	}
+	return c.r.call(ctx, http.MethodPatch, u, map[string]any{
+		"status":                "completed",
+		"lastMergeSourceCommit": map[string]string{"commitId": pr.SourceCommit},
+	}, nil)
+}
+
+// currentUser returns the identity ID TFBuddy authenticates as.
+func (c *Client) currentUser(ctx context.Context) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.self != "" {
+		return c.self, nil
+	}
+	u := c.org.JoinPath("_apis", "connectionData")
+	u.RawQuery = url.Values{"api-version": []string{apiVersion + "-preview"}}.Encode()
+	var data struct {
+		AuthenticatedUser struct {
+			ID string `json:"id"`
+		} `json:"authenticatedUser"`
+	}
+	if err := c.r.call(ctx, http.MethodGet, u, nil, &data); err != nil {
+		return "", err
+	}
+	c.self = data.AuthenticatedUser.ID
+	return c.self, nil
+}
+
+func (c *Client) listThreads(ctx context.Context, repo string, prID int) ([]*Thread, error) {
+	u, err := c.pullRequestURL(repo, prID, "threads")
+	if err != nil {
+		return nil, err
+	}
+	var threads struct {
+		Value []apiThread `json:"value"`
+	}
+	if err := c.r.call(ctx, http.MethodGet, u, nil, &threads); err != nil {
+		return nil, err
+	}
+	result := make([]*Thread, 0, len(threads.Value))
+	for i := range threads.Value {
+		if threads.Value[i].IsDeleted {
+			continue
+		}
+		result = append(result, threads.Value[i].toThread())
+	}
+	return result, nil
+}
+
+// GetOldRunUrls crawls the threads TFBuddy started for the same
+// workspace+action, collects previous TFC run URLs into a collapsible block,
+// and (when TFBUDDY_DELETE_OLD_COMMENTS is set) deletes their comments.
+// Comment IDs are only unique within a thread, so rootThreadID names the
+// thread of the current run, which is kept.
+func (c *Client) GetOldRunUrls(ctx context.Context, prID int, fullName string, rootThreadID int, workspace string, action string) (string, error) {
+	ctx, span := otel.Tracer("TFC").Start(ctx, "GetOldRunURLs")
+	defer span.End()
+
+	threads, err := c.listThreads(ctx, fullName, prID)
+	if err != nil {
+		return "", err
+	}
+	self, err := c.currentUser(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	var oldRunUrls []string
+	var oldRunBlock string
+	var toDelete []*Comment
+	for _, thread := range threads {
+		if len(thread.Comments) == 0 {
+			continue
+		}
+		root := thread.Comments[0]
+		if root.AuthorID != self {
+			continue
+		}
+		noteWS, noteAction, hasMarker := utils.ParseTFBuddyMarker(root.Body)
+		if !hasMarker || noteWS != workspace || noteAction != action {
+			continue
+		}
+
+		for _, comment := range thread.Comments {
+			if comment.AuthorID != self {
+				continue
+			}
+			runUrl := utils.CaptureSubstring(comment.Body, utils.URL_RUN_PREFIX, utils.URL_RUN_SUFFIX)
+			runUrlRaw := utils.CaptureSubstring(runUrl, "[", "]")
+			runUrlSplit := strings.Split(runUrlRaw, "/")
+			runID := runUrlSplit[len(runUrlSplit)-1]
+			runStatus := utils.CaptureSubstring(comment.Body, utils.URL_RUN_STATUS_PREFIX, utils.URL_RUN_SUFFIX)
+			if runUrl != "" && runStatus != "" {
+				oldRunUrls = append(oldRunUrls, fmt.Sprintf("|[%s](%s)|%s|%s|", runID, runUrlRaw, utils.FormatStatus(runStatus), comment.CreatedAt))
+			}
+
+			oldRunBlockTest := utils.CaptureSubstring(comment.Body, utils.URL_RUN_GROUP_PREFIX, utils.URL_RUN_GROUP_SUFFIX)
+			if oldRunBlockTest != "" {
+				oldRunBlock = oldRunBlockTest
+			}
+		}
+
+		if thread.ID == rootThreadID {
+			continue
+		}
+		// replies first, the thread is removed with its first comment
+		for i := len(thread.Comments) - 1; i >= 0; i-- {
+			toDelete = append(toDelete, thread.Comments[i])
+		}
+	}
+
+	if c.cfg.DeleteOldComments {
+		for _, comment := range toDelete {
+			log.Debug().Str("workspace", workspace).Str("action", action).Msgf("deleting comment %d of thread %d", comment.ID, comment.ThreadID)
+			u, err := c.threadURL(fullName, prID, strconv.Itoa(comment.ThreadID), "comments", strconv.FormatInt(comment.ID, 10))
+			if err != nil {
+				return "", err
+			}
+			if err := c.r.call(ctx, http.MethodDelete, u, nil, nil); err != nil {
+				log.Warn().Err(err).Int("threadID", comment.ThreadID).Int64("commentID", comment.ID).Msg("could not delete comment, skipping")
+			}
+		}
+	}
+
+	if len(oldRunUrls) > 0 {
+		if oldRunBlock == "" {
+			oldRunBlock = "\n"
+		}
+		return fmt.Sprintf("%s%s%s\n%s", utils.URL_RUN_GROUP_PREFIX, oldRunBlock, strings.Join(oldRunUrls, "\n"), utils.URL_RUN_GROUP_SUFFIX), nil
+	}
+	if strings.TrimSpace(oldRunBlock) == "" {
+		return "", nil
+	}
+	return oldRunBlock, nil
+}
+
+// gitAuth returns the credentials used to clone and pull. Any non-empty
+// username works with a personal access token.
+func (c *Client) gitAuth() *githttp.BasicAuth {
+	return &githttp.BasicAuth{
+		Username: "tfbuddy",
+		Password: c.token,
+	}
+}
+
+// cloneURL returns the HTTPS clone URL of a project/repo repository.
+func (c *Client) cloneURL(repo string) (string, error) {
+	project, name, err := splitFullName(repo)
+	if err != nil {
+		return "", err
+	}
+	return c.org.JoinPath(project, "_git", name).String(), nil
+}
+
+// CloneMergeRequest performs a git clone of the pull request source branch to the `dest` path.
+func (c *Client) CloneMergeRequest(ctx context.Context, project string, mr vcs.MR, dest string) (vcs.GitRepo, error) {
+	_, span := otel.Tracer("TFC").Start(ctx, "CloneMergeRequest")
+	defer span.End()
+
+	if fork, ok := mr.(vcs.ForkMR); ok && fork.IsFromFork() {
+		// the source branch does not exist in the target repository
+		return nil, vcs.ErrForkNotSupported
+	}
+	cloneURL, err := c.cloneURL(project)
+	if err != nil {
+		return nil, err
+	}
+	ref := plumbing.NewBranchReferenceName(mr.GetSourceBranch())
+	auth := c.gitAuth()
+
+	var progress sideband.Progress
+	if log.Trace().Enabled() {
+		progress = os.Stdout
+	}
+	cloneDepth := zgit.GetCloneDepth(c.cfg, AZURE_DEVOPS_CLONE_DEPTH_ENV)
+
+	repo, err := git.PlainClone(dest, false, &git.CloneOptions{
+		Auth:          auth,
+		URL:           cloneURL,
+		ReferenceName: ref,
+		SingleBranch:  true,
+		Depth:         cloneDepth,
+		Progress:      progress,
+	})
+	if err != nil && err != git.ErrRepositoryAlreadyExists {
+		err = fmt.Errorf("could not clone MR: %v", err)
+		span.RecordError(err)
+		return nil, err
+	}
+
+	wt, _ := repo.Worktree()
+	err = wt.Pull(&git.PullOptions{
+		ReferenceName: ref,
+		Depth:         cloneDepth,
+		Auth:          auth,
+		Progress:      progress,
+	})
+	if err != nil && err != git.NoErrAlreadyUpToDate {
+		err = fmt.Errorf("could not pull MR: %v", err)
+		span.RecordError(err)
+		return nil, err
+	}
+
+	if log.Trace().Enabled() {
+		//nolint
+		filepath.WalkDir(dest, zgit.WalkRepo)
+	}
+	return zgit.NewRepository(repo, auth, dest), nil
+}
+
+// splitFullName returns the project and repository name of a repository.
+func splitFullName(fullName string) (string, string, error) {
+	project, repo, ok := strings.Cut(fullName, "/")
+	if !ok || project == "" || repo == "" || strings.Contains(repo, "/") {
+		return "", "", utils.CreatePermanentError(fmt.Errorf("azure devops client: invalid repo format %q", fullName))
+	}
+	return project, repo, nil
+}
+
+// ----------------------------------------------------------------------------
+
+// requester sends requests authenticated with a personal access token to the
+// Azure DevOps REST API.
+type requester struct {
+	http  *http.Client
+	token string
+}
+
+// call sends a request, retrying throttling and server errors. out is
+// decoded from the JSON response. The api-version parameter is added unless
+// the URL already has one.
+func (r *requester) call(ctx context.Context, method string, u *url.URL, body any, out any) error {
+	reqURL := *u
+	q := reqURL.Query()
+	if q.Get("api-version") == "" {
+		q.Set("api-version", apiVersion)
+		reqURL.RawQuery = q.Encode()
+	}
+	return backoff.Retry(func() error {
+		status, err := r.do(ctx, method, &reqURL, body, out)
+		switch {
+		case err == nil:
+			return nil
+		case status == 0:
+			// transport error
+			return err
+		case status >= 400:
+			return utils.CreatePermanentHTTPError(status, err)
+		default:
+			return utils.CreatePermanentError(err)
+		}
+	}, createBackOffWithRetries())
+}
+
+func (r *requester) do(ctx context.Context, method string, u *url.URL, body any, out any) (int, error) {
+	var reqBody io.Reader
+	if body != nil {
+		b, err := json.Marshal(body)
+		if err != nil {
+			return -1, err
+		}
+		reqBody = bytes.NewReader(b)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), reqBody)
+	if err != nil {
+		return -1, err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Authorization", "Basic "+base64.StdEncoding.EncodeToString([]byte(":"+r.token)))
+
+	resp, err := r.http.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return 0, err
+	}
+	if resp.StatusCode >= 400 {
+		return resp.StatusCode, fmt.Errorf("azure devops api: %s %s returned %d: %s", method, u.Path, resp.StatusCode, strings.TrimSpace(string(respBody)))
+	}
+	// An expired or invalid token is answered with a sign-in page.
+	if strings.HasPrefix(resp.Header.Get("Content-Type"), "text/html") {
+		return http.StatusUnauthorized, fmt.Errorf("azure devops api: %s %s returned a sign-in page, check AZURE_DEVOPS_TOKEN", method, u.Path)
+	}
+	if out != nil && len(respBody) > 0 {
+		if err := json.Unmarshal(respBody, out); err != nil {
+			return resp.StatusCode, fmt.Errorf("could not decode azure devops response for %s. %w", u.Path, err)
+		}
+	}
+	return resp.StatusCode, nil
+}
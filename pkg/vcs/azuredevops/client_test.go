@@ -0,0 +1,256 @@
+package azuredevops
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/zapier/tfbuddy/internal/config"
+	"github.com/zapier/tfbuddy/pkg/utils"
+	"github.com/zapier/tfbuddy/pkg/vcs"
+)
+
+// newTestClient returns a client for the organization `example` served by
+// handler.
+func newTestClient(t *testing.T, cfg config.Config, handler http.HandlerFunc) *Client {
+	t.Helper()
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+	cfg.AzureDevOpsURL = srv.URL + "/example"
+	c, err := newClient(cfg, srv.Client(), "token")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c
+}
+
+func TestNewClient(t *testing.T) {
+	tests := []struct {
+		name      string
+		url       string
+		wantClone string
+		wantErr   bool
+	}{
+		{name: "services", url: "https://dev.azure.com/example", wantClone: "https://dev.azure.com/example/Platform%20Team/_git/infra"},
+		{name: "server collection", url: "https://tfs.example.com/DefaultCollection/", wantClone: "https://tfs.example.com/DefaultCollection/Platform%20Team/_git/infra"},
+		{name: "not set", url: "", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := newClient(config.Config{AzureDevOpsURL: tt.url}, http.DefaultClient, "token")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("newClient() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			got, err := c.cloneURL("Platform Team/infra")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.wantClone {
+				t.Errorf("cloneURL() = %s, want %s", got, tt.wantClone)
+			}
+		})
+	}
+}
+
+func TestPullRequest_IsApproved(t *testing.T) {
+	tests := []struct {
+		name  string
+		votes []int
+		want  bool
+	}{
+		{name: "no reviewers", want: false},
+		{name: "no votes", votes: []int{0, 0}, want: false},
+		{name: "approved", votes: []int{0, 10}, want: true},
+		{name: "approved with suggestions", votes: []int{5}, want: true},
+		{name: "waiting for author", votes: []int{10, -5}, want: true},
+		{name: "rejected", votes: []int{10, -10}, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pr := &PullRequest{Votes: tt.votes}
+			if got := pr.IsApproved(); got != tt.want {
+				t.Errorf("IsApproved() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetMergeRequest(t *testing.T) {
+	var gotAuth, gotVersion string
+	c := newTestClient(t, config.Config{}, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/example/Platform/_apis/git/repositories/infra/pullRequests/7" {
+			http.NotFound(w, r)
+			return
+		}
+		gotAuth = r.Header.Get("Authorization")
+		gotVersion = r.URL.Query().Get("api-version")
+		io.WriteString(w, `{"pullRequestId":7,"status":"active","mergeStatus":"conflicts",
+			"sourceRefName":"refs/heads/feature","targetRefName":"refs/heads/main",
+			"lastMergeSourceCommit":{"commitId":"abc123"},"reviewers":[{"vote":10}]}`)
+	})
+	mr, err := c.GetMergeRequest(context.Background(), 7, "Platform/infra")
+	if err != nil {
+		t.Fatal(err)
+	}
+	pr := mr.(*PullRequest)
+	if pr.GetSourceBranch() != "feature" || pr.GetTargetBranch() != "main" || pr.SourceCommit != "abc123" {
+		t.Errorf("unexpected pull request %+v", pr)
+	}
+	if !pr.IsApproved() || !pr.HasConflicts() {
+		t.Errorf("IsApproved() = %v, HasConflicts() = %v, want both true", pr.IsApproved(), pr.HasConflicts())
+	}
+	if !strings.HasSuffix(pr.GetWebURL(), "/example/Platform/_git/infra/pullrequest/7") {
+		t.Errorf("GetWebURL() = %s", pr.GetWebURL())
+	}
+	if gotAuth != "Basic OnRva2Vu" {
+		t.Errorf("Authorization = %s, want basic auth with an empty user", gotAuth)
+	}
+	if gotVersion != apiVersion {
+		t.Errorf("api-version = %s, want %s", gotVersion, apiVersion)
+	}
+}
+
+func TestGetMergeRequest_Fork(t *testing.T) {
+	c := newTestClient(t, config.Config{}, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/example/Platform/_apis/git/repositories/infra/pullRequests/7" {
+			http.NotFound(w, r)
+			return
+		}
+		io.WriteString(w, `{"pullRequestId":7,"sourceRefName":"refs/heads/main","targetRefName":"refs/heads/main",
+			"lastMergeSourceCommit":{"commitId":"abc123"},"forkSource":{"name":"refs/heads/main"}}`)
+	})
+	mr, err := c.GetMergeRequest(context.Background(), 7, "Platform/infra")
+	if err != nil {
+		t.Fatal(err)
+	}
+	pr := mr.(*PullRequest)
+	if !pr.IsFromFork() || pr.GetHeadSHA() != "abc123" {
+		t.Errorf("IsFromFork() = %v, GetHeadSHA() = %s", pr.IsFromFork(), pr.GetHeadSHA())
+	}
+	if _, err := c.CloneMergeRequest(context.Background(), "Platform/infra", pr, t.TempDir()); !errors.Is(err, vcs.ErrForkNotSupported) {
+		t.Fatalf("CloneMergeRequest() error = %v, want %v", err, vcs.ErrForkNotSupported)
+	}
+}
+
+func TestCreateMergeRequestDiscussion(t *testing.T) {
+	var got map[string]any
+	c := newTestClient(t, config.Config{}, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/example/Platform/_apis/git/repositories/infra/pullRequests/7/threads" {
+			http.NotFound(w, r)
+			return
+		}
+		json.NewDecoder(r.Body).Decode(&got)
+		io.WriteString(w, `{"id":12,"status":"active","comments":[{"id":1,"content":"Starting TFC plan"}]}`)
+	})
+	disc, err := c.CreateMergeRequestDiscussion(context.Background(), 7, "Platform/infra", "Starting TFC plan")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if disc.GetDiscussionID() != "12" {
+		t.Errorf("GetDiscussionID() = %s, want 12", disc.GetDiscussionID())
+	}
+	if notes := disc.GetMRNotes(); len(notes) != 1 || notes[0].GetNoteID() != 1 {
+		t.Errorf("unexpected notes %v", notes)
+	}
+	if got["status"] != "active" {
+		t.Errorf("thread status = %v, want active", got["status"])
+	}
+}
+
+func TestSetPullRequestStatus(t *testing.T) {
+	var gotPath string
+	var got map[string]any
+	c := newTestClient(t, config.Config{}, func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+		json.NewDecoder(r.Body).Decode(&got)
+		w.WriteHeader(http.StatusCreated)
+		io.WriteString(w, `{}`)
+	})
+	_, err := c.SetPullRequestStatus(context.Background(), "Platform/infra", 7, &StatusOptions{
+		Genre: statusGenre,
+		Name:  "plan/service-tfbuddy",
+		State: StatusStateSucceeded,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if gotPath != "/example/Platform/_apis/git/repositories/infra/pullRequests/7/statuses" {
+		t.Errorf("path = %s", gotPath)
+	}
+	wantContext := map[string]any{"genre": "TFC", "name": "plan/service-tfbuddy"}
+	if !reflect.DeepEqual(got["context"], wantContext) || got["state"] != StatusStateSucceeded {
+		t.Errorf("unexpected status %v", got)
+	}
+}
+
+func TestGetMergeRequestModifiedFiles(t *testing.T) {
+	c := newTestClient(t, config.Config{}, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/example/Platform/_apis/git/repositories/infra/pullRequests/7/iterations":
+			io.WriteString(w, `{"value":[{"id":1},{"id":2}]}`)
+		case "/example/Platform/_apis/git/repositories/infra/pullRequests/7/iterations/2/changes":
+			if r.URL.Query().Get("$skip") == "0" {
+				io.WriteString(w, `{"changeEntries":[{"item":{"path":"/terraform","isFolder":true}},{"item":{"path":"/terraform/main.tf"}}],"nextSkip":2}`)
+				return
+			}
+			io.WriteString(w, `{"changeEntries":[{"item":{"path":"/modules/new.tf"},"originalPath":"/modules/old.tf"}],"nextSkip":0}`)
+		default:
+			http.NotFound(w, r)
+		}
+	})
+	got, err := c.GetMergeRequestModifiedFiles(context.Background(), 7, "Platform/infra")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"terraform/main.tf", "modules/new.tf", "modules/old.tf"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("GetMergeRequestModifiedFiles() = %v, want %v", got, want)
+	}
+}
+
+func TestGetOldRunUrls(t *testing.T) {
+	statusNote := "### Terraform Cloud\n" + utils.URL_RUN_PREFIX + "[https://app.terraform.io/app/zapier/workspaces/ws/runs/run-old](https://app.terraform.io/app/zapier/workspaces/ws/runs/run-old)" + utils.URL_RUN_SUFFIX +
+		utils.URL_RUN_STATUS_PREFIX + "planned" + utils.URL_RUN_SUFFIX + utils.FormatTFBuddyMarker("ws", "plan")
+	thread := func(id int, author string) map[string]any {
+		return map[string]any{"id": id, "comments": []any{
+			map[string]any{"id": 1, "content": statusNote, "author": map[string]string{"id": author}},
+			map[string]any{"id": 2, "parentCommentId": 1, "content": "details", "author": map[string]string{"id": author}},
+		}}
+	}
+	threads, _ := json.Marshal(map[string]any{"value": []any{thread(10, "tfbuddy"), thread(11, "someone-else"), thread(12, "tfbuddy")}})
+
+	var deleted []string
+	c := newTestClient(t, config.Config{DeleteOldComments: true}, func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/example/_apis/connectionData":
+			io.WriteString(w, `{"authenticatedUser":{"id":"tfbuddy"}}`)
+		case r.Method == http.MethodDelete:
+			deleted = append(deleted, strings.TrimPrefix(r.URL.Path, "/example/Platform/_apis/git/repositories/infra/pullRequests/7/"))
+		case r.URL.Path == "/example/Platform/_apis/git/repositories/infra/pullRequests/7/threads":
+			w.Write(threads)
+		default:
+			http.NotFound(w, r)
+		}
+	})
+
+	got, err := c.GetOldRunUrls(context.Background(), 7, "Platform/infra", 12, "ws", "plan")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(got, "run-old") {
+		t.Errorf("expected the old run in %q", got)
+	}
+	want := []string{"threads/10/comments/2", "threads/10/comments/1"}
+	if !reflect.DeepEqual(deleted, want) {
+		t.Errorf("deleted %v, want %v", deleted, want)
+	}
+}
@@ -0,0 +1,115 @@
+package hooks
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/zapier/tfbuddy/pkg/pr_hooks"
+)
+
+// Service hook event types TFBuddy subscribes to.
+const (
+	EventPullRequestCreated   = "git.pullrequest.created"
+	EventPullRequestUpdated   = "git.pullrequest.updated"
+	EventPullRequestCommented = "ms.vss-code.git-pullrequest-comment-event"
+)
+
+type pullRequestResource struct {
+	PullRequestID         int    `json:"pullRequestId"`
+	Status                string `json:"status"`
+	SourceRefName         string `json:"sourceRefName"`
+	LastMergeSourceCommit struct {
+		CommitID string `json:"commitId"`
+	} `json:"lastMergeSourceCommit"`
+	Repository struct {
+		Name    string `json:"name"`
+		Project struct {
+			Name string `json:"name"`
+		} `json:"project"`
+	} `json:"repository"`
+}
+
+type serviceHookPayload struct {
+	ID        string `json:"id"`
+	EventType string `json:"eventType"`
+	Message   struct {
+		Text string `json:"text"`
+	} `json:"message"`
+	Resource json.RawMessage `json:"resource"`
+}
+
+// parseEvent decodes an Azure DevOps service hook payload and returns the id
+// of the notification.
+func parseEvent(body []byte) (*pr_hooks.PullRequestEvent, string, error) {
+	var p serviceHookPayload
+	if err := json.Unmarshal(body, &p); err != nil {
+		return nil, "", fmt.Errorf("could not decode azure devops event. %w", err)
+	}
+
+	var (
+		pr    pullRequestResource
+		event = &pr_hooks.PullRequestEvent{EventType: p.EventType}
+	)
+	switch p.EventType {
+	case EventPullRequestCreated, EventPullRequestUpdated:
+		if err := json.Unmarshal(p.Resource, &pr); err != nil {
+			return nil, "", fmt.Errorf("could not decode azure devops pull request. %w", err)
+		}
+		event.Action = pullRequestAction(p.EventType, pr.Status, p.Message.Text)
+		if event.Action == "" {
+			return nil, "", pr_hooks.ErrUnhandledEvent
+		}
+	case EventPullRequestCommented:
+		var resource struct {
+			Comment struct {
+				ID          int64  `json:"id"`
+				Content     string `json:"content"`
+				CommentType string `json:"commentType"`
+				Author      struct {
+					UniqueName string `json:"uniqueName"`
+				} `json:"author"`
+			} `json:"comment"`
+			PullRequest pullRequestResource `json:"pullRequest"`
+		}
+		if err := json.Unmarshal(p.Resource, &resource); err != nil {
+			return nil, "", fmt.Errorf("could not decode azure devops comment. %w", err)
+		}
+		if resource.Comment.CommentType == "system" {
+			return nil, "", pr_hooks.ErrUnhandledEvent
+		}
+		pr = resource.PullRequest
+		event.Action = pr_hooks.ActionCommented
+		event.CommentID = resource.Comment.ID
+		event.Comment = resource.Comment.Content
+		event.Author = resource.Comment.Author.UniqueName
+	default:
+		return nil, "", pr_hooks.ErrUnhandledEvent
+	}
+
+	event.PRID = pr.PullRequestID
+	event.SourceBranch = strings.TrimPrefix(pr.SourceRefName, "refs/heads/")
+	event.CommitSHA = pr.LastMergeSourceCommit.CommitID
+	if pr.Repository.Project.Name != "" && pr.Repository.Name != "" {
+		event.Repo = pr.Repository.Project.Name + "/" + pr.Repository.Name
+	}
+	if event.Repo == "" || event.PRID == 0 {
+		return nil, "", fmt.Errorf("azure devops %s event has no pull request", p.EventType)
+	}
+	return event, p.ID, nil
+}
+
+// pullRequestAction maps a pull request event to an action. Azure DevOps
+// sends `git.pullrequest.updated` for pushes, votes, reviewer and status
+// changes alike; pushes are only told apart by the message.
+func pullRequestAction(eventType, status, message string) string {
+	switch {
+	case status == "completed" || status == "abandoned":
+		return pr_hooks.ActionClosed
+	case eventType == EventPullRequestCreated:
+		return pr_hooks.ActionOpened
+	case strings.Contains(message, "updated the source branch"):
+		return pr_hooks.ActionUpdated
+	}
+	return ""
+}
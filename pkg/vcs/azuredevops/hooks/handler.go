@@ -0,0 +1,71 @@
+package hooks
+
+import (
+	"crypto/subtle"
+	"net/http"
+
+	"github.com/nats-io/nats.go"
+	"github.com/rs/zerolog/log"
+	"github.com/zapier/tfbuddy/internal/config"
+	"github.com/zapier/tfbuddy/pkg/allow_list"
+	"github.com/zapier/tfbuddy/pkg/pr_hooks"
+	"github.com/zapier/tfbuddy/pkg/runstream"
+	"github.com/zapier/tfbuddy/pkg/tfc_api"
+	"github.com/zapier/tfbuddy/pkg/tfc_trigger"
+	"github.com/zapier/tfbuddy/pkg/vcs"
+)
+
+// provider adapts Azure DevOps service hooks to pull request events.
+type provider struct {
+	cfg config.Config
+}
+
+// ensure type complies with interface
+var _ pr_hooks.Provider = (*provider)(nil)
+
+func NewAzureDevOpsHooksHandler(cfg config.Config, vcs vcs.GitClient, tfc tfc_api.ApiClient, rs runstream.StreamClient, js nats.JetStreamContext, workspaceStream tfc_trigger.WorkspacePublisher) *pr_hooks.HooksHandler {
+	if cfg.AzureDevOpsHookSecretKey == "" {
+		if cfg.AzureDevOpsHookUnsigned {
+			log.Warn().Msgf("%s is not set and %s is enabled, Azure DevOps service hooks are not authenticated", config.KeyAzureDevOpsHookSecretKey, config.KeyAzureDevOpsHookUnsigned)
+		} else {
+			log.Warn().Msgf("%s is not set, all Azure DevOps service hooks are rejected", config.KeyAzureDevOpsHookSecretKey)
+		}
+	}
+	return pr_hooks.NewHooksHandler(cfg, &provider{cfg: cfg}, vcs, tfc, rs, js, workspaceStream)
+}
+
+func (p *provider) Name() string {
+	return "azuredevops"
+}
+func (p *provider) DisplayName() string {
+	return "Azure DevOps"
+}
+
+// EventType is empty as Azure DevOps only sends the event type in the body.
+func (p *provider) EventType(r *http.Request) string {
+	return ""
+}
+func (p *provider) Verify(r *http.Request, body []byte) bool {
+	return authorized(p.cfg.AzureDevOpsHookSecretKey, p.cfg.AzureDevOpsHookUnsigned, r)
+}
+func (p *provider) ParseEvent(r *http.Request, body []byte) (*pr_hooks.PullRequestEvent, string, error) {
+	return parseEvent(body)
+}
+
+// IsRepoAllowed matches project/repo within the configured organization
+// against the allow list.
+func (p *provider) IsRepoAllowed(cfg config.Config, repo string) bool {
+	return allow_list.IsAzureDevOpsRepoAllowed(cfg, repo)
+}
+
+// authorized checks the basic authentication password configured on the
+// service hook, as Azure DevOps does not sign service hooks. Without a secret
+// configured they are only accepted when unauthenticated ones are explicitly
+// allowed.
+func authorized(secret string, allowUnauthenticated bool, r *http.Request) bool {
+	if secret == "" {
+		return allowUnauthenticated
+	}
+	_, password, ok := r.BasicAuth()
+	return ok && subtle.ConstantTimeCompare([]byte(password), []byte(secret)) == 1
+}
@@ -0,0 +1,110 @@
+package hooks
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/zapier/tfbuddy/pkg/pr_hooks"
+)
+
+const testPullRequest = `{"pullRequestId":7,"status":"active","sourceRefName":"refs/heads/feature",
+	"lastMergeSourceCommit":{"commitId":"abc123"},"repository":{"name":"infra","project":{"name":"Platform"}}}`
+
+func TestParseEvent(t *testing.T) {
+	tests := []struct {
+		name    string
+		body    string
+		want    *pr_hooks.PullRequestEvent
+		wantID  string
+		wantErr bool
+	}{
+		{
+			name:   "created",
+			body:   `{"id":"evt-1","eventType":"git.pullrequest.created","resource":` + testPullRequest + `}`,
+			want:   &pr_hooks.PullRequestEvent{EventType: EventPullRequestCreated, Action: pr_hooks.ActionOpened, Repo: "Platform/infra", PRID: 7, SourceBranch: "feature", CommitSHA: "abc123"},
+			wantID: "evt-1",
+		},
+		{
+			name:   "source branch updated",
+			body:   `{"id":"evt-2","eventType":"git.pullrequest.updated","message":{"text":"Jamie updated the source branch of pull request 7"},"resource":` + testPullRequest + `}`,
+			want:   &pr_hooks.PullRequestEvent{EventType: EventPullRequestUpdated, Action: pr_hooks.ActionUpdated, Repo: "Platform/infra", PRID: 7, SourceBranch: "feature", CommitSHA: "abc123"},
+			wantID: "evt-2",
+		},
+		{
+			name:   "completed",
+			body:   `{"id":"evt-3","eventType":"git.pullrequest.updated","message":{"text":"Jamie completed pull request 7"},"resource":{"pullRequestId":7,"status":"completed","repository":{"name":"infra","project":{"name":"Platform"}}}}`,
+			want:   &pr_hooks.PullRequestEvent{EventType: EventPullRequestUpdated, Action: pr_hooks.ActionClosed, Repo: "Platform/infra", PRID: 7},
+			wantID: "evt-3",
+		},
+		{
+			name:    "vote",
+			body:    `{"id":"evt-4","eventType":"git.pullrequest.updated","message":{"text":"Jamie approved pull request 7"},"resource":` + testPullRequest + `}`,
+			wantErr: true,
+		},
+		{
+			name:   "comment",
+			body:   `{"id":"evt-5","eventType":"ms.vss-code.git-pullrequest-comment-event","resource":{"comment":{"id":3,"content":"tfc plan","commentType":"text","author":{"uniqueName":"jamie@example.com"}},"pullRequest":` + testPullRequest + `}}`,
+			want:   &pr_hooks.PullRequestEvent{EventType: EventPullRequestCommented, Action: pr_hooks.ActionCommented, Repo: "Platform/infra", PRID: 7, SourceBranch: "feature", CommitSHA: "abc123", CommentID: 3, Comment: "tfc plan", Author: "jamie@example.com"},
+			wantID: "evt-5",
+		},
+		{
+			name:    "system comment",
+			body:    `{"id":"evt-6","eventType":"ms.vss-code.git-pullrequest-comment-event","resource":{"comment":{"id":4,"content":"voted 10","commentType":"system"},"pullRequest":` + testPullRequest + `}}`,
+			wantErr: true,
+		},
+		{
+			name:    "other event",
+			body:    `{"id":"evt-7","eventType":"git.push","resource":{}}`,
+			wantErr: true,
+		},
+		{
+			name:    "missing pull request",
+			body:    `{"id":"evt-8","eventType":"git.pullrequest.created","resource":{}}`,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, id, err := parseEvent([]byte(tt.body))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseEvent() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if *got != *tt.want || id != tt.wantID {
+				t.Errorf("parseEvent() = %+v, %s, want %+v, %s", got, id, tt.want, tt.wantID)
+			}
+		})
+	}
+}
+
+func TestAuthorized(t *testing.T) {
+	tests := []struct {
+		name                 string
+		secret               string
+		allowUnauthenticated bool
+		password             string
+		noAuth               bool
+		want                 bool
+	}{
+		{name: "valid", secret: "secret", password: "secret", want: true},
+		{name: "wrong password", secret: "secret", password: "other"},
+		{name: "missing", secret: "secret", noAuth: true},
+		{name: "no secret configured", noAuth: true},
+		{name: "no secret configured with a password", password: "secret"},
+		{name: "unauthenticated allowed", allowUnauthenticated: true, noAuth: true, want: true},
+		{name: "secret configured and unauthenticated allowed", secret: "secret", allowUnauthenticated: true, noAuth: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("POST", "/hooks/azuredevops", nil)
+			if !tt.noAuth {
+				req.SetBasicAuth("tfbuddy", tt.password)
+			}
+			if got := authorized(tt.secret, tt.allowUnauthenticated, req); got != tt.want {
+				t.Errorf("authorized() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
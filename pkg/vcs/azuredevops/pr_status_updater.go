@@ -0,0 +1,65 @@
+package azuredevops
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/rs/zerolog/log"
+	"github.com/zapier/tfbuddy/internal/config"
+	"github.com/zapier/tfbuddy/pkg/pr_hooks"
+	"github.com/zapier/tfbuddy/pkg/runstream"
+	"github.com/zapier/tfbuddy/pkg/tfc_api"
+	"go.opentelemetry.io/otel"
+)
+
+// statusGenre prefixes every status TFBuddy posts, so they are listed as
+// `TFC/<action>/<workspace>`.
+const statusGenre = "TFC"
+
+// statusStates maps run action states to Azure Repos status states.
+var statusStates = map[pr_hooks.StatusState]string{
+	pr_hooks.StatusPending:   StatusStatePending,
+	pr_hooks.StatusFailed:    StatusStateFailed,
+	pr_hooks.StatusSucceeded: StatusStateSucceeded,
+}
+
+// SetStatus reports the run action as a pull request status named
+// `TFC/<action>/<workspace>`.
+func (r *runReporter) SetStatus(ctx context.Context, runState pr_hooks.StatusState, action string, rmd runstream.RunMetadata) {
+	ctx, span := otel.Tracer("TFC").Start(ctx, "updateStatus")
+	defer span.End()
+
+	state := statusStates[runState]
+
+	status := &StatusOptions{
+		Genre:       statusGenre,
+		Name:        fmt.Sprintf("%s/%s", action, rmd.GetWorkspace()),
+		TargetURL:   runUrlForTFRunMetadata(r.cfg, rmd),
+		Description: descriptionForState(state),
+		State:       state,
+	}
+
+	log.Debug().Str("repo", rmd.GetMRProjectNameWithNamespace()).Int("prID", rmd.GetMRInternalID()).Interface("new_status", status).Msg("updating Azure DevOps pull request status")
+	s, err := r.client.SetPullRequestStatus(ctx, rmd.GetMRProjectNameWithNamespace(), rmd.GetMRInternalID(), status)
+	if err != nil {
+		log.Error().Str("repo", rmd.GetMRProjectNameWithNamespace()).Int("prID", rmd.GetMRInternalID()).Err(err).Interface("status", status).Msg("could not update status")
+		return
+	}
+	log.Debug().Str("repo", rmd.GetMRProjectNameWithNamespace()).Int("prID", rmd.GetMRInternalID()).Str("pr_status", s.Info()).Msg("updated pull request status")
+}
+
+func descriptionForState(state string) string {
+	switch state {
+	case StatusStatePending:
+		return "in progress..."
+	case StatusStateFailed:
+		return "failed."
+	case StatusStateSucceeded:
+		return "succeeded."
+	}
+	return "unknown"
+}
+
+func runUrlForTFRunMetadata(cfg config.Config, rmd runstream.RunMetadata) string {
+	return tfc_api.RunURL(cfg, rmd.GetOrganization(), rmd.GetWorkspace(), rmd.GetRunID())
+}
@@ -0,0 +1,73 @@
+package azuredevops
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/go-tfe"
+	"github.com/rs/zerolog/log"
+	"github.com/zapier/tfbuddy/internal/config"
+	"github.com/zapier/tfbuddy/pkg/pr_hooks"
+	"github.com/zapier/tfbuddy/pkg/runstream"
+	"github.com/zapier/tfbuddy/pkg/tfc_api"
+)
+
+const runEventsConsumerDurableName = "azuredevops"
+
+// runReporter reports runs as pull request statuses and in the comment
+// thread of the run.
+type runReporter struct {
+	cfg    config.Config
+	client *Client
+}
+
+func NewRunEventsWorker(cfg config.Config, client *Client, rs runstream.StreamClient, tfc tfc_api.ApiClient) *pr_hooks.RunEventsWorker {
+	return pr_hooks.NewRunEventsWorker(cfg, runEventsConsumerDurableName, client, &runReporter{cfg: cfg, client: client}, rs, tfc)
+}
+
+// PostRunComment edits the first comment of the run's thread, replies with
+// details and resolves the thread once the run is done.
+func (r *runReporter) PostRunComment(ctx context.Context, run *tfe.Run, rmd runstream.RunMetadata, summary, details string, resolve bool) {
+	if summary != "" {
+		if _, err := r.client.UpdateMergeRequestDiscussionNote(
+			ctx,
+			rmd.GetMRInternalID(),
+			int(rmd.GetRootNoteID()),
+			rmd.GetMRProjectNameWithNamespace(),
+			rmd.GetDiscussionID(),
+			summary,
+		); err != nil {
+			log.Error().Str("repo", rmd.GetMRProjectNameWithNamespace()).Int("prID", rmd.GetMRInternalID()).Str("threadID", rmd.GetDiscussionID()).Err(err).Msg("could not update PR thread")
+		}
+	}
+
+	if details != "" {
+		r.postComment(ctx, fmt.Sprintf("Status: `%s`<br>%s", run.Status, details), rmd)
+	}
+
+	if resolve {
+		if err := r.client.ResolveMergeRequestDiscussion(ctx, rmd.GetMRProjectNameWithNamespace(), rmd.GetMRInternalID(), rmd.GetDiscussionID()); err != nil {
+			log.Error().Str("repo", rmd.GetMRProjectNameWithNamespace()).Int("prID", rmd.GetMRInternalID()).Str("threadID", rmd.GetDiscussionID()).Err(err).Msg("could not resolve PR thread")
+		}
+	}
+}
+
+// postComment replies in the run's thread, or starts a new one when the run
+// has none.
+func (r *runReporter) postComment(ctx context.Context, body string, rmd runstream.RunMetadata) {
+	content := fmt.Sprintf(PR_COMMENT_FORMAT, body)
+	if rmd.GetDiscussionID() != "" {
+		if _, err := r.client.AddMergeRequestDiscussionReply(ctx, rmd.GetMRInternalID(), rmd.GetMRProjectNameWithNamespace(), rmd.GetDiscussionID(), content); err != nil {
+			log.Error().Str("repo", rmd.GetMRProjectNameWithNamespace()).Int("prID", rmd.GetMRInternalID()).Str("threadID", rmd.GetDiscussionID()).Err(err).Msg("could not reply in PR thread")
+		}
+		return
+	}
+	if err := r.client.CreateMergeRequestComment(ctx, rmd.GetMRInternalID(), rmd.GetMRProjectNameWithNamespace(), content); err != nil {
+		log.Error().Str("repo", rmd.GetMRProjectNameWithNamespace()).Int("prID", rmd.GetMRInternalID()).Err(err).Msg("could not post PR comment")
+	}
+}
+
+const PR_COMMENT_FORMAT = `
+### Terraform Cloud
+%s
+`
@@ -0,0 +1,191 @@
+package azuredevops
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/zapier/tfbuddy/pkg/vcs"
+)
+
+// ----------------------------------------------------------------------------
+// ensure type complies with interface
+var _ vcs.DetailedMR = (*PullRequest)(nil)
+var _ vcs.MRApproved = (*PullRequest)(nil)
+var _ vcs.ForkMR = (*PullRequest)(nil)
+
+// PullRequest is an Azure Repos pull request.
+type PullRequest struct {
+	ID           int
+	Title        string
+	Status       string
+	Author       string
+	SourceBranch string
+	SourceCommit string
+	TargetBranch string
+	WebURL       string
+	// MergeStatus is the status of the last merge attempt, `conflicts` when
+	// the branches conflict.
+	MergeStatus string
+	// Votes holds the vote of every reviewer: 10 approved, 5 approved with
+	// suggestions, 0 no vote, -5 waiting for author, -10 rejected.
+	Votes []int
+	// FromFork is set when the source branch lives in a fork.
+	FromFork bool
+}
+
+func (pr *PullRequest) HasConflicts() bool {
+	return pr.MergeStatus == "conflicts"
+}
+func (pr *PullRequest) GetSourceBranch() string {
+	return pr.SourceBranch
+}
+func (pr *PullRequest) GetTargetBranch() string {
+	return pr.TargetBranch
+}
+func (pr *PullRequest) GetAuthor() vcs.MRAuthor {
+	return &Author{pr.Author}
+}
+func (pr *PullRequest) GetInternalID() int {
+	return pr.ID
+}
+func (pr *PullRequest) GetWebURL() string {
+	return pr.WebURL
+}
+func (pr *PullRequest) GetTitle() string {
+	return pr.Title
+}
+func (pr *PullRequest) GetState() string {
+	return pr.Status
+}
+
+// IsFromFork reports whether the source branch lives in a fork of the target
+// repository.
+func (pr *PullRequest) IsFromFork() bool {
+	return pr.FromFork
+}
+func (pr *PullRequest) GetHeadSHA() string {
+	return pr.SourceCommit
+}
+
+// IsApproved reports whether at least one reviewer approved the pull request
+// and none rejected it.
+func (pr *PullRequest) IsApproved() bool {
+	approved := false
+	for _, vote := range pr.Votes {
+		if vote <= -10 {
+			return false
+		}
+		approved = approved || vote >= 5
+	}
+	return approved
+}
+
+// ----------------------------------------------------------------------------
+// ensure type complies with interface
+var _ vcs.MRAuthor = (*Author)(nil)
+
+type Author struct {
+	UniqueName string
+}
+
+func (a *Author) GetUsername() string {
+	return a.UniqueName
+}
+
+// ----------------------------------------------------------------------------
+// ensure type complies with interface
+var _ vcs.MRDiscussionNotes = (*Thread)(nil)
+
+// Thread is a pull request comment thread, the equivalent of a GitLab
+// discussion.
+type Thread struct {
+	ID       int
+	Status   string
+	Comments []*Comment
+}
+
+func (t *Thread) GetDiscussionID() string {
+	return fmt.Sprintf("%d", t.ID)
+}
+func (t *Thread) GetMRNotes() []vcs.MRNote {
+	notes := make([]vcs.MRNote, 0, len(t.Comments))
+	for _, c := range t.Comments {
+		notes = append(notes, c)
+	}
+	return notes
+}
+
+// ensure type complies with interface
+var _ vcs.MRNote = (*Comment)(nil)
+
+// Comment is a comment in a thread. Comment IDs are only unique within their
+// thread; the first comment of a thread is always 1.
+type Comment struct {
+	ID              int64
+	ThreadID        int
+	ParentCommentID int64
+	Body            string
+	// AuthorID is the identity ID of the author.
+	AuthorID  string
+	CreatedAt time.Time
+}
+
+func (c *Comment) GetNoteID() int64 {
+	return c.ID
+}
+
+// ----------------------------------------------------------------------------
+// Status states supported by Azure Repos.
+const (
+	StatusStatePending   = "pending"
+	StatusStateSucceeded = "succeeded"
+	StatusStateFailed    = "failed"
+)
+
+// ensure type complies with interface
+var _ vcs.CommitStatusOptions = (*StatusOptions)(nil)
+
+// StatusOptions describes a pull request or commit status. Name is shown and
+// required by branch policies as `<genre>/<name>`; a status posted with the
+// same genre and name replaces the previous one.
+type StatusOptions struct {
+	Genre       string
+	Name        string
+	TargetURL   string
+	Description string
+	State       string
+}
+
+func (o *StatusOptions) GetName() string {
+	return o.Name
+}
+func (o *StatusOptions) GetContext() string {
+	if o.Genre == "" {
+		return o.Name
+	}
+	return o.Genre + "/" + o.Name
+}
+func (o *StatusOptions) GetTargetURL() string {
+	return o.TargetURL
+}
+func (o *StatusOptions) GetDescription() string {
+	return o.Description
+}
+func (o *StatusOptions) GetState() string {
+	return o.State
+}
+func (o *StatusOptions) GetPipelineID() int {
+	return 0
+}
+
+// ensure type complies with interface
+var _ vcs.CommitStatus = (*Status)(nil)
+
+type Status struct {
+	Context string
+	State   string
+}
+
+func (s *Status) Info() string {
+	return fmt.Sprintf("%s %s", s.Context, s.State)
+}
@@ -0,0 +1,61 @@
+package bitbucket
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/rs/zerolog/log"
+	"github.com/zapier/tfbuddy/internal/config"
+	"github.com/zapier/tfbuddy/pkg/pr_hooks"
+	"github.com/zapier/tfbuddy/pkg/runstream"
+	"github.com/zapier/tfbuddy/pkg/tfc_api"
+	"go.opentelemetry.io/otel"
+)
+
+// buildStates maps run action states to Bitbucket build states. Bitbucket has
+// no pending state, so queued runs are reported as in progress.
+var buildStates = map[pr_hooks.StatusState]string{
+	pr_hooks.StatusPending:   BuildStateInProgress,
+	pr_hooks.StatusFailed:    BuildStateFailed,
+	pr_hooks.StatusSucceeded: BuildStateSuccessful,
+}
+
+// SetStatus reports the run action as a build status named
+// `TFC/<action>/<workspace>` on the pull request's commit.
+func (r *runReporter) SetStatus(ctx context.Context, s pr_hooks.StatusState, action string, rmd runstream.RunMetadata) {
+	ctx, span := otel.Tracer("TFC").Start(ctx, "updateStatus")
+	defer span.End()
+
+	state := buildStates[s]
+
+	status := &BuildStatusOptions{
+		Name:        fmt.Sprintf("TFC/%s/%s", action, rmd.GetWorkspace()),
+		TargetURL:   runUrlForTFRunMetadata(r.cfg, rmd),
+		Description: descriptionForState(state),
+		State:       state,
+	}
+
+	log.Debug().Str("repo", rmd.GetMRProjectNameWithNamespace()).Int("prID", rmd.GetMRInternalID()).Interface("new_status", status).Msg("updating Bitbucket build status")
+	cs, err := r.client.SetCommitStatus(ctx, rmd.GetMRProjectNameWithNamespace(), rmd.GetCommitSHA(), status)
+	if err != nil {
+		log.Error().Str("repo", rmd.GetMRProjectNameWithNamespace()).Int("prID", rmd.GetMRInternalID()).Err(err).Interface("status", status).Msg("could not update status")
+		return
+	}
+	log.Debug().Str("repo", rmd.GetMRProjectNameWithNamespace()).Int("prID", rmd.GetMRInternalID()).Str("build_status", cs.Info()).Msg("updated build status")
+}
+
+func descriptionForState(state string) string {
+	switch state {
+	case BuildStateInProgress:
+		return "in progress..."
+	case BuildStateFailed:
+		return "failed."
+	case BuildStateSuccessful:
+		return "succeeded."
+	}
+	return "unknown"
+}
+
+func runUrlForTFRunMetadata(cfg config.Config, rmd runstream.RunMetadata) string {
+	return tfc_api.RunURL(cfg, rmd.GetOrganization(), rmd.GetWorkspace(), rmd.GetRunID())
+}
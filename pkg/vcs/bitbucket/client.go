@@ -0,0 +1,416 @@
+package bitbucket
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/cenkalti/backoff/v4"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/protocol/packp/sideband"
+	githttp "github.com/go-git/go-git/v5/plumbing/transport/http"
+	"github.com/rs/zerolog/log"
+	"github.com/zapier/tfbuddy/internal/config"
+	zgit "github.com/zapier/tfbuddy/pkg/git"
+	"github.com/zapier/tfbuddy/pkg/utils"
+	"github.com/zapier/tfbuddy/pkg/vcs"
+	"go.opentelemetry.io/otel"
+)
+
+// ensure type complies with interface
+var _ vcs.GitClient = (*Client)(nil)
+
+const DefaultMaxRetries = 3
+
+const BITBUCKET_CLONE_DEPTH_ENV = "TFBUDDY_BITBUCKET_CLONE_DEPTH"
+
+func createBackOffWithRetries() backoff.BackOff {
+	exp := backoff.NewExponentialBackOff()
+	exp.MaxElapsedTime = 30 * time.Second
+	return backoff.WithMaxRetries(exp, DefaultMaxRetries)
+}
+
+// restAPI hides the differences between the Bitbucket Cloud and Data Center
+// REST APIs. Repositories are named workspace/repo on Cloud and PROJECT/repo
+// on Data Center.
+type restAPI interface {
+	getPullRequest(ctx context.Context, repo string, prID int) (*PullRequest, error)
+	listComments(ctx context.Context, repo string, prID int) ([]*Comment, error)
+	createComment(ctx context.Context, repo string, prID int, parentID int64, body string) (*Comment, error)
+	updateComment(ctx context.Context, repo string, prID int, commentID int64, body string) (*Comment, error)
+	deleteComment(ctx context.Context, repo string, prID int, commentID int64) error
+	getFile(ctx context.Context, repo, path, ref string) ([]byte, error)
+	modifiedFiles(ctx context.Context, repo string, prID int) ([]string, error)
+	setBuildStatus(ctx context.Context, repo, commitSHA string, status *BuildStatusOptions) error
+	merge(ctx context.Context, repo string, prID int) error
+	// currentUser identifies the account TFBuddy authenticates as, in the
+	// same form as Comment.Author.
+	currentUser(ctx context.Context) (string, error)
+	cloneURL(repo string) string
+}
+
+type Client struct {
+	api      restAPI
+	username string
+	token    string
+	cfg      config.Config
+}
+
+// NewBitbucketClient creates a client for Bitbucket Cloud, or for the
+// Bitbucket Data Center instance at `bitbucket-base-url` when set.
+// BITBUCKET_TOKEN is an access token, or an app password when
+// BITBUCKET_USERNAME is set as well.
+func NewBitbucketClient(cfg config.Config) *Client {
+	token := os.Getenv("BITBUCKET_TOKEN")
+	if token == "" {
+		log.Info().Msg("BITBUCKET_TOKEN is not set, skipping creation of Bitbucket API client")
+		return nil
+	}
+	c, err := newClient(cfg, http.DefaultClient, os.Getenv("BITBUCKET_USERNAME"), token)
+	if err != nil {
+		log.Fatal().Err(err).Msg("could not create Bitbucket client")
+	}
+	return c
+}
+
+func newClient(cfg config.Config, httpClient *http.Client, username, token string) (*Client, error) {
+	r := &requester{http: httpClient, username: username, token: token}
+	c := &Client{username: username, token: token, cfg: cfg}
+	if cfg.BitbucketBaseURL == "" {
+		c.api = newCloudAPI(r, cloudAPIURL, cloudWebURL)
+		return c, nil
+	}
+	root, err := url.Parse(strings.TrimSuffix(cfg.BitbucketBaseURL, "/") + "/")
+	if err != nil || root.Host == "" {
+		return nil, fmt.Errorf("invalid bitbucket url %q", cfg.BitbucketBaseURL)
+	}
+	c.api = newDataCenterAPI(r, root)
+	return c, nil
+}
+
+func (c *Client) GetMergeRequestApprovals(ctx context.Context, id int, project string) (vcs.MRApproved, error) {
+	ctx, span := otel.Tracer("TFC").Start(ctx, "GetMergeRequestApprovals")
+	defer span.End()
+
+	return c.api.getPullRequest(ctx, project, id)
+}
+
+func (c *Client) CreateMergeRequestComment(ctx context.Context, id int, fullPath string, comment string) error {
+	ctx, span := otel.Tracer("TFC").Start(ctx, "CreateMergeRequestComment")
+	defer span.End()
+
+	_, err := c.api.createComment(ctx, fullPath, id, 0, comment)
+	return err
+}
+
+func (c *Client) CreateMergeRequestDiscussion(ctx context.Context, mrID int, fullPath string, comment string) (vcs.MRDiscussionNotes, error) {
+	ctx, span := otel.Tracer("TFC").Start(ctx, "CreateMergeRequestDiscussion")
+	defer span.End()
+
+	return c.api.createComment(ctx, fullPath, mrID, 0, comment)
+}
+
+func (c *Client) GetMergeRequest(ctx context.Context, prID int, fullName string) (vcs.DetailedMR, error) {
+	ctx, span := otel.Tracer("hooks").Start(ctx, "GetMergeRequest")
+	defer span.End()
+
+	return c.api.getPullRequest(ctx, fullName, prID)
+}
+
+func (c *Client) GetRepoFile(ctx context.Context, fullName string, file string, ref string) ([]byte, error) {
+	ctx, span := otel.Tracer("TFC").Start(ctx, "GetRepoFile")
+	defer span.End()
+
+	return c.api.getFile(ctx, fullName, file, ref)
+}
+
+func (c *Client) GetMergeRequestModifiedFiles(ctx context.Context, prID int, fullName string) ([]string, error) {
+	ctx, span := otel.Tracer("TFC").Start(ctx, "GetMergeRequestModifiedFiles")
+	defer span.End()
+
+	return c.api.modifiedFiles(ctx, fullName, prID)
+}
+
+// UpdateMergeRequestDiscussionNote edits the comment noteID in place.
+func (c *Client) UpdateMergeRequestDiscussionNote(ctx context.Context, mrIID, noteID int, project, discussionID, comment string) (vcs.MRNote, error) {
+	ctx, span := otel.Tracer("TFC").Start(ctx, "UpdateMergeRequestDiscussionNote")
+	defer span.End()
+
+	return c.api.updateComment(ctx, project, mrIID, int64(noteID), comment)
+}
+
+func (c *Client) ResolveMergeRequestDiscussion(ctx context.Context, project string, mrIID int, discussionID string) error {
+	// Status comments are edited in place, so there is no thread to resolve.
+	return nil
+}
+
+func (c *Client) AddMergeRequestDiscussionReply(ctx context.Context, mrIID int, project, discussionID, comment string) (vcs.MRNote, error) {
+	ctx, span := otel.Tracer("TFC").Start(ctx, "AddMergeRequestDiscussionReply")
+	defer span.End()
+
+	var parentID int64
+	if _, err := fmt.Sscanf(discussionID, "%d", &parentID); err != nil {
+		return nil, utils.CreatePermanentError(fmt.Errorf("invalid bitbucket comment id %q", discussionID))
+	}
+	return c.api.createComment(ctx, project, mrIID, parentID, comment)
+}
+
+func (c *Client) SetCommitStatus(ctx context.Context, projectWithNS string, commitSHA string, status vcs.CommitStatusOptions) (vcs.CommitStatus, error) {
+	ctx, span := otel.Tracer("TFC").Start(ctx, "SetCommitStatus")
+	defer span.End()
+
+	opts := &BuildStatusOptions{
+		Name:        status.GetName(),
+		TargetURL:   status.GetTargetURL(),
+		Description: status.GetDescription(),
+		State:       status.GetState(),
+	}
+	if err := c.api.setBuildStatus(ctx, projectWithNS, commitSHA, opts); err != nil {
+		return nil, err
+	}
+	return &BuildStatus{Key: opts.Name, State: opts.State}, nil
+}
+
+// GetPipelinesForCommit returns no pipelines: build statuses on Bitbucket are
+// attached to the commit, not to a pipeline.
+func (c *Client) GetPipelinesForCommit(ctx context.Context, projectWithNS string, commitSHA string) ([]vcs.ProjectPipeline, error) {
+	return nil, nil
+}
+
+func (c *Client) MergeMR(ctx context.Context, mrIID int, project string) error {
+	ctx, span := otel.Tracer("TFC").Start(ctx, "MergeMR")
+	defer span.End()
+
+	return c.api.merge(ctx, project, mrIID)
+}
+
+// GetOldRunUrls crawls PR comments authored by TFBuddy, collects previous TFC
+// run URLs into a collapsible block, and (when TFBUDDY_DELETE_OLD_COMMENTS is
+// set) deletes old comments that belong to the same workspace+action
+// combination.
+func (c *Client) GetOldRunUrls(ctx context.Context, prID int, fullName string, rootCommentID int, workspace string, action string) (string, error) {
+	ctx, span := otel.Tracer("TFC").Start(ctx, "GetOldRunURLs")
+	defer span.End()
+
+	comments, err := c.api.listComments(ctx, fullName, prID)
+	if err != nil {
+		return "", err
+	}
+	self, err := c.api.currentUser(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	var oldRunUrls []string
+	var oldRunBlock string
+	var matchingCommentIDs []int64
+	for _, comment := range comments {
+		if comment.Author != self {
+			continue
+		}
+		noteWS, noteAction, hasMarker := utils.ParseTFBuddyMarker(comment.Body)
+		if !hasMarker || noteWS != workspace || noteAction != action {
+			continue
+		}
+
+		runUrl := utils.CaptureSubstring(comment.Body, utils.URL_RUN_PREFIX, utils.URL_RUN_SUFFIX)
+		runUrlRaw := utils.CaptureSubstring(runUrl, "[", "]")
+		runUrlSplit := strings.Split(runUrlRaw, "/")
+		runID := runUrlSplit[len(runUrlSplit)-1]
+		runStatus := utils.CaptureSubstring(comment.Body, utils.URL_RUN_STATUS_PREFIX, utils.URL_RUN_SUFFIX)
+		if runUrl != "" && runStatus != "" && comment.ID != int64(rootCommentID) {
+			oldRunUrls = append(oldRunUrls, fmt.Sprintf("|[%s](%s)|%s|%s|", runID, runUrlRaw, runStatus, comment.CreatedAt))
+		}
+
+		oldRunBlockTest := utils.CaptureSubstring(comment.Body, utils.URL_RUN_GROUP_PREFIX, utils.URL_RUN_GROUP_SUFFIX)
+		if oldRunBlockTest != "" {
+			oldRunBlock = oldRunBlockTest
+		}
+
+		if comment.ID != int64(rootCommentID) {
+			matchingCommentIDs = append(matchingCommentIDs, comment.ID)
+		}
+	}
+
+	if c.cfg.DeleteOldComments {
+		for _, commentID := range matchingCommentIDs {
+			log.Debug().Str("workspace", workspace).Str("action", action).Msgf("Deleting comment %d", commentID)
+			if err := c.api.deleteComment(ctx, fullName, prID, commentID); err != nil {
+				return "", err
+			}
+		}
+	}
+
+	if len(oldRunUrls) > 0 {
+		if oldRunBlock == "" {
+			oldRunBlock = "\n"
+		}
+		return fmt.Sprintf("%s%s%s\n%s", utils.URL_RUN_GROUP_PREFIX, oldRunBlock, strings.Join(oldRunUrls, "\n"), utils.URL_RUN_GROUP_SUFFIX), nil
+	}
+	if strings.TrimSpace(oldRunBlock) == "" {
+		return "", nil
+	}
+	return oldRunBlock, nil
+}
+
+// gitAuth returns the credentials used to clone and pull. Access tokens are
+// used with the x-token-auth user.
+func (c *Client) gitAuth() *githttp.BasicAuth {
+	username := c.username
+	if username == "" {
+		username = "x-token-auth"
+	}
+	return &githttp.BasicAuth{
+		Username: username,
+		Password: c.token,
+	}
+}
+
+// CloneMergeRequest performs a git clone of the pull request source branch to the `dest` path.
+func (c *Client) CloneMergeRequest(ctx context.Context, project string, mr vcs.MR, dest string) (vcs.GitRepo, error) {
+	_, span := otel.Tracer("TFC").Start(ctx, "CloneMergeRequest")
+	defer span.End()
+
+	if fork, ok := mr.(vcs.ForkMR); ok && fork.IsFromFork() {
+		// the source branch does not exist in the target repository
+		return nil, vcs.ErrForkNotSupported
+	}
+
+	ref := plumbing.NewBranchReferenceName(mr.GetSourceBranch())
+	auth := c.gitAuth()
+
+	var progress sideband.Progress
+	if log.Trace().Enabled() {
+		progress = os.Stdout
+	}
+	cloneDepth := zgit.GetCloneDepth(c.cfg, BITBUCKET_CLONE_DEPTH_ENV)
+
+	repo, err := git.PlainClone(dest, false, &git.CloneOptions{
+		Auth:          auth,
+		URL:           c.api.cloneURL(project),
+		ReferenceName: ref,
+		SingleBranch:  true,
+		Depth:         cloneDepth,
+		Progress:      progress,
+	})
+	if err != nil && err != git.ErrRepositoryAlreadyExists {
+		err = fmt.Errorf("could not clone MR: %v", err)
+		span.RecordError(err)
+		return nil, err
+	}
+
+	wt, _ := repo.Worktree()
+	err = wt.Pull(&git.PullOptions{
+		ReferenceName: ref,
+		Depth:         cloneDepth,
+		Auth:          auth,
+		Progress:      progress,
+	})
+	if err != nil && err != git.NoErrAlreadyUpToDate {
+		err = fmt.Errorf("could not pull MR: %v", err)
+		span.RecordError(err)
+		return nil, err
+	}
+
+	if log.Trace().Enabled() {
+		//nolint
+		filepath.WalkDir(dest, zgit.WalkRepo)
+	}
+	return zgit.NewRepository(repo, auth, dest), nil
+}
+
+// splitFullName returns the workspace (or project) and slug of a repository.
+func splitFullName(fullName string) (string, string, error) {
+	owner, repo, ok := strings.Cut(fullName, "/")
+	if !ok || owner == "" || repo == "" || strings.Contains(repo, "/") {
+		return "", "", utils.CreatePermanentError(fmt.Errorf("bitbucket client: invalid repo format %q", fullName))
+	}
+	return owner, repo, nil
+}
+
+// ----------------------------------------------------------------------------
+
+// requester sends authenticated requests to the Bitbucket REST API.
+type requester struct {
+	http     *http.Client
+	username string
+	token    string
+}
+
+// call sends a request, retrying throttling and server errors. out is
+// decoded from the JSON response, or receives the raw body when it is a
+// *[]byte.
+func (r *requester) call(ctx context.Context, method string, u *url.URL, body any, out any) error {
+	return backoff.Retry(func() error {
+		status, err := r.do(ctx, method, u, body, out)
+		switch {
+		case err == nil:
+			return nil
+		case status == 0:
+			// transport error
+			return err
+		case status >= 400:
+			return utils.CreatePermanentHTTPError(status, err)
+		default:
+			return utils.CreatePermanentError(err)
+		}
+	}, createBackOffWithRetries())
+}
+
+func (r *requester) do(ctx context.Context, method string, u *url.URL, body any, out any) (int, error) {
+	var reqBody io.Reader
+	if body != nil {
+		b, err := json.Marshal(body)
+		if err != nil {
+			return -1, err
+		}
+		reqBody = bytes.NewReader(b)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), reqBody)
+	if err != nil {
+		return -1, err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	req.Header.Set("Accept", "application/json")
+	// Bitbucket Data Center rejects some writes without it.
+	req.Header.Set("X-Atlassian-Token", "no-check")
+	if r.username != "" {
+		req.SetBasicAuth(r.username, r.token)
+	} else {
+		req.Header.Set("Authorization", "Bearer "+r.token)
+	}
+
+	resp, err := r.http.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return 0, err
+	}
+	if resp.StatusCode >= 400 {
+		return resp.StatusCode, fmt.Errorf("bitbucket api: %s %s returned %d: %s", method, u.Path, resp.StatusCode, strings.TrimSpace(string(respBody)))
+	}
+	switch o := out.(type) {
+	case nil:
+	case *[]byte:
+		*o = respBody
+	default:
+		if err := json.Unmarshal(respBody, out); err != nil {
+			return resp.StatusCode, fmt.Errorf("could not decode bitbucket response for %s. %w", u.Path, err)
+		}
+	}
+	return resp.StatusCode, nil
+}
@@ -0,0 +1,258 @@
+package bitbucket
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/zapier/tfbuddy/internal/config"
+	"github.com/zapier/tfbuddy/pkg/utils"
+	"github.com/zapier/tfbuddy/pkg/vcs"
+)
+
+// newTestClient returns a client whose Cloud API (or Data Center API when
+// dataCenter is set) is served by handler.
+func newTestClient(t *testing.T, cfg config.Config, dataCenter bool, handler http.HandlerFunc) *Client {
+	t.Helper()
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+	if dataCenter {
+		cfg.BitbucketBaseURL = srv.URL
+	}
+	c, err := newClient(cfg, srv.Client(), "", "token")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !dataCenter {
+		c.api = newCloudAPI(c.api.(*cloudAPI).r, srv.URL+"/2.0/", srv.URL+"/")
+	}
+	return c
+}
+
+func TestNewClient(t *testing.T) {
+	tests := []struct {
+		name      string
+		baseURL   string
+		wantClone string
+		wantErr   bool
+	}{
+		{name: "cloud", wantClone: "https://bitbucket.org/zapier/tfbuddy.git"},
+		{name: "data center", baseURL: "https://bitbucket.example.com/", wantClone: "https://bitbucket.example.com/scm/zapier/tfbuddy.git"},
+		{name: "data center with context path", baseURL: "https://example.com/bitbucket", wantClone: "https://example.com/bitbucket/scm/zapier/tfbuddy.git"},
+		{name: "invalid url", baseURL: "bitbucket", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := newClient(config.Config{BitbucketBaseURL: tt.baseURL}, http.DefaultClient, "", "token")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("newClient() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if got := c.api.cloneURL("ZAPIER/tfbuddy"); !strings.EqualFold(got, tt.wantClone) {
+				t.Errorf("cloneURL() = %s, want %s", got, tt.wantClone)
+			}
+		})
+	}
+}
+
+func TestGetMergeRequest(t *testing.T) {
+	tests := []struct {
+		name           string
+		dataCenter     bool
+		responses      map[string]string
+		wantApproved   bool
+		wantConflicted bool
+		wantFork       bool
+	}{
+		{
+			name: "cloud approved",
+			responses: map[string]string{
+				"/2.0/repositories/zapier/tfbuddy/pullrequests/7":          `{"id":7,"source":{"branch":{"name":"feature"},"commit":{"hash":"abc123"}},"participants":[{"approved":false},{"approved":true}]}`,
+				"/2.0/repositories/zapier/tfbuddy/pullrequests/7/diffstat": `{"values":[{"status":"modified","new":{"path":"main.tf"}}]}`,
+			},
+			wantApproved: true,
+		},
+		{
+			name: "cloud conflicted",
+			responses: map[string]string{
+				"/2.0/repositories/zapier/tfbuddy/pullrequests/7":          `{"id":7,"source":{"branch":{"name":"feature"},"commit":{"hash":"abc123"}}}`,
+				"/2.0/repositories/zapier/tfbuddy/pullrequests/7/diffstat": `{"values":[{"status":"merge conflict","new":{"path":"main.tf"}}]}`,
+			},
+			wantConflicted: true,
+		},
+		{
+			name:       "data center approved and conflicted",
+			dataCenter: true,
+			responses: map[string]string{
+				"/rest/api/1.0/projects/zapier/repos/tfbuddy/pull-requests/7":       `{"id":7,"version":3,"fromRef":{"displayId":"feature","latestCommit":"abc123"},"reviewers":[{"approved":true}]}`,
+				"/rest/api/1.0/projects/zapier/repos/tfbuddy/pull-requests/7/merge": `{"canMerge":false,"conflicted":true}`,
+			},
+			wantApproved:   true,
+			wantConflicted: true,
+		},
+		{
+			name: "cloud fork",
+			responses: map[string]string{
+				"/2.0/repositories/zapier/tfbuddy/pullrequests/7": `{"id":7,"source":{"branch":{"name":"feature"},"commit":{"hash":"abc123"},"repository":{"full_name":"someone/tfbuddy"}},
+					"destination":{"branch":{"name":"main"},"repository":{"full_name":"zapier/tfbuddy"}}}`,
+				"/2.0/repositories/zapier/tfbuddy/pullrequests/7/diffstat": `{"values":[]}`,
+			},
+			wantFork: true,
+		},
+		{
+			name:       "data center fork",
+			dataCenter: true,
+			responses: map[string]string{
+				"/rest/api/1.0/projects/zapier/repos/tfbuddy/pull-requests/7":       `{"id":7,"fromRef":{"displayId":"feature","latestCommit":"abc123","repository":{"id":2}},"toRef":{"displayId":"main","repository":{"id":1}}}`,
+				"/rest/api/1.0/projects/zapier/repos/tfbuddy/pull-requests/7/merge": `{"canMerge":true}`,
+			},
+			wantFork: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newTestClient(t, config.Config{}, tt.dataCenter, func(w http.ResponseWriter, r *http.Request) {
+				body, ok := tt.responses[r.URL.Path]
+				if !ok {
+					http.NotFound(w, r)
+					return
+				}
+				io.WriteString(w, body)
+			})
+			mr, err := c.GetMergeRequest(context.Background(), 7, "zapier/tfbuddy")
+			if err != nil {
+				t.Fatal(err)
+			}
+			pr := mr.(*PullRequest)
+			if pr.GetSourceBranch() != "feature" || pr.SourceCommit != "abc123" {
+				t.Errorf("unexpected pull request %+v", pr)
+			}
+			if pr.IsApproved() != tt.wantApproved {
+				t.Errorf("IsApproved() = %v, want %v", pr.IsApproved(), tt.wantApproved)
+			}
+			if pr.HasConflicts() != tt.wantConflicted {
+				t.Errorf("HasConflicts() = %v, want %v", pr.HasConflicts(), tt.wantConflicted)
+			}
+			if pr.IsFromFork() != tt.wantFork || pr.GetHeadSHA() != "abc123" {
+				t.Errorf("IsFromFork() = %v, want %v", pr.IsFromFork(), tt.wantFork)
+			}
+		})
+	}
+}
+
+func TestCloneMergeRequest_Fork(t *testing.T) {
+	c := newTestClient(t, config.Config{}, false, func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("unexpected request %s", r.URL.Path)
+	})
+	_, err := c.CloneMergeRequest(context.Background(), "zapier/tfbuddy", &PullRequest{ID: 7, SourceBranch: "main", FromFork: true}, t.TempDir())
+	if !errors.Is(err, vcs.ErrForkNotSupported) {
+		t.Fatalf("CloneMergeRequest() error = %v, want %v", err, vcs.ErrForkNotSupported)
+	}
+}
+
+func TestUpdateMergeRequestDiscussionNote_DataCenterVersion(t *testing.T) {
+	var gotVersion float64
+	c := newTestClient(t, config.Config{}, true, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/rest/api/1.0/projects/zapier/repos/tfbuddy/pull-requests/7/comments/42" {
+			http.NotFound(w, r)
+			return
+		}
+		switch r.Method {
+		case http.MethodGet:
+			io.WriteString(w, `{"id":42,"version":5,"text":"old"}`)
+		case http.MethodPut:
+			var req map[string]any
+			json.NewDecoder(r.Body).Decode(&req)
+			gotVersion, _ = req["version"].(float64)
+			io.WriteString(w, `{"id":42,"version":6,"text":"new"}`)
+		}
+	})
+	note, err := c.UpdateMergeRequestDiscussionNote(context.Background(), 7, 42, "zapier/tfbuddy", "42", "new")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if gotVersion != 5 {
+		t.Errorf("update sent version %v, want 5", gotVersion)
+	}
+	if note.GetNoteID() != 42 {
+		t.Errorf("GetNoteID() = %d, want 42", note.GetNoteID())
+	}
+}
+
+func TestSetCommitStatus(t *testing.T) {
+	tests := []struct {
+		name       string
+		dataCenter bool
+		wantPath   string
+	}{
+		{name: "cloud", wantPath: "/2.0/repositories/zapier/tfbuddy/commit/abc123/statuses/build"},
+		{name: "data center", dataCenter: true, wantPath: "/rest/build-status/1.0/commits/abc123"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotPath string
+			var got map[string]string
+			c := newTestClient(t, config.Config{}, tt.dataCenter, func(w http.ResponseWriter, r *http.Request) {
+				gotPath = r.URL.Path
+				json.NewDecoder(r.Body).Decode(&got)
+				w.WriteHeader(http.StatusCreated)
+			})
+			_, err := c.SetCommitStatus(context.Background(), "zapier/tfbuddy", "abc123", &BuildStatusOptions{
+				Name:  "TFC/plan/service-tfbuddy",
+				State: BuildStateSuccessful,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if gotPath != tt.wantPath {
+				t.Errorf("path = %s, want %s", gotPath, tt.wantPath)
+			}
+			if got["key"] != "TFC/plan/service-tfbuddy" || got["state"] != BuildStateSuccessful {
+				t.Errorf("unexpected build status %v", got)
+			}
+		})
+	}
+}
+
+func TestGetOldRunUrls(t *testing.T) {
+	ownComment := "### Terraform Cloud\n" + utils.URL_RUN_PREFIX + "[https://app.terraform.io/app/zapier/workspaces/ws/runs/run-old](https://app.terraform.io/app/zapier/workspaces/ws/runs/run-old)" + utils.URL_RUN_SUFFIX +
+		utils.URL_RUN_STATUS_PREFIX + "planned" + utils.URL_RUN_SUFFIX + utils.FormatTFBuddyMarker("ws", "plan")
+	comments, _ := json.Marshal(map[string]any{"values": []any{
+		map[string]any{"id": 1, "content": map[string]string{"raw": ownComment}, "user": map[string]string{"uuid": "{tfbuddy}"}},
+		map[string]any{"id": 2, "content": map[string]string{"raw": ownComment}, "user": map[string]string{"uuid": "{someone-else}"}},
+		map[string]any{"id": 3, "content": map[string]string{"raw": ownComment}, "user": map[string]string{"uuid": "{tfbuddy}"}},
+	}})
+
+	var deleted []string
+	c := newTestClient(t, config.Config{DeleteOldComments: true}, false, func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/2.0/user":
+			io.WriteString(w, `{"uuid":"{tfbuddy}"}`)
+		case r.Method == http.MethodDelete:
+			deleted = append(deleted, r.URL.Path)
+			w.WriteHeader(http.StatusNoContent)
+		case r.URL.Path == "/2.0/repositories/zapier/tfbuddy/pullrequests/7/comments":
+			w.Write(comments)
+		default:
+			http.NotFound(w, r)
+		}
+	})
+
+	got, err := c.GetOldRunUrls(context.Background(), 7, "zapier/tfbuddy", 3, "ws", "plan")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(got, "run-old") {
+		t.Errorf("expected the old run in %q", got)
+	}
+	if len(deleted) != 1 || !strings.HasSuffix(deleted[0], "/comments/1") {
+		t.Errorf("deleted %v, want only comment 1", deleted)
+	}
+}
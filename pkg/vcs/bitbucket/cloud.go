@@ -0,0 +1,338 @@
+package bitbucket
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	cloudAPIURL = "https://api.bitbucket.org/2.0/"
+	cloudWebURL = "https://bitbucket.org/"
+)
+
+// cloudAPI implements restAPI against the Bitbucket Cloud 2.0 API.
+type cloudAPI struct {
+	r    *requester
+	base *url.URL
+	web  *url.URL
+
+	mu   sync.Mutex
+	self string
+}
+
+func newCloudAPI(r *requester, apiURL, webURL string) *cloudAPI {
+	base, _ := url.Parse(apiURL)
+	web, _ := url.Parse(webURL)
+	return &cloudAPI{r: r, base: base, web: web}
+}
+
+type cloudUser struct {
+	UUID        string `json:"uuid"`
+	Nickname    string `json:"nickname"`
+	DisplayName string `json:"display_name"`
+}
+
+type cloudRepository struct {
+	FullName string `json:"full_name"`
+}
+
+type cloudPullRequest struct {
+	ID     int       `json:"id"`
+	Title  string    `json:"title"`
+	State  string    `json:"state"`
+	Author cloudUser `json:"author"`
+	Source struct {
+		Branch struct {
+			Name string `json:"name"`
+		} `json:"branch"`
+		Commit struct {
+			Hash string `json:"hash"`
+		} `json:"commit"`
+		Repository cloudRepository `json:"repository"`
+	} `json:"source"`
+	Destination struct {
+		Branch struct {
+			Name string `json:"name"`
+		} `json:"branch"`
+		Repository cloudRepository `json:"repository"`
+	} `json:"destination"`
+	Links struct {
+		HTML struct {
+			Href string `json:"href"`
+		} `json:"html"`
+	} `json:"links"`
+	Participants []struct {
+		Approved bool `json:"approved"`
+	} `json:"participants"`
+}
+
+type cloudComment struct {
+	ID      int64 `json:"id"`
+	Content struct {
+		Raw string `json:"raw"`
+	} `json:"content"`
+	User      cloudUser `json:"user"`
+	CreatedOn time.Time `json:"created_on"`
+	Deleted   bool      `json:"deleted"`
+	Parent    *struct {
+		ID int64 `json:"id"`
+	} `json:"parent,omitempty"`
+}
+
+func (c *cloudComment) toComment() *Comment {
+	comment := &Comment{
+		ID:        c.ID,
+		Body:      c.Content.Raw,
+		Author:    c.User.UUID,
+		CreatedAt: c.CreatedOn,
+	}
+	if c.Parent != nil {
+		comment.ParentID = c.Parent.ID
+	}
+	return comment
+}
+
+// cloudPage is a page of a paginated Bitbucket Cloud collection.
+type cloudPage[T any] struct {
+	Values []T    `json:"values"`
+	Next   string `json:"next"`
+}
+
+// listAll follows the `next` links of a paginated collection.
+func listAll[T any](ctx context.Context, r *requester, u *url.URL) ([]T, error) {
+	var all []T
+	for u != nil {
+		var page cloudPage[T]
+		if err := r.call(ctx, http.MethodGet, u, nil, &page); err != nil {
+			return nil, err
+		}
+		all = append(all, page.Values...)
+		u = nil
+		if page.Next != "" {
+			next, err := url.Parse(page.Next)
+			if err != nil {
+				return nil, fmt.Errorf("invalid bitbucket next page url. %w", err)
+			}
+			u = next
+		}
+	}
+	return all, nil
+}
+
+func (a *cloudAPI) repoURL(repo string, elem ...string) (*url.URL, error) {
+	workspace, slug, err := splitFullName(repo)
+	if err != nil {
+		return nil, err
+	}
+	return a.base.JoinPath(append([]string{"repositories", workspace, slug}, elem...)...), nil
+}
+
+func (a *cloudAPI) pullRequestURL(repo string, prID int, elem ...string) (*url.URL, error) {
+	return a.repoURL(repo, append([]string{"pullrequests", strconv.Itoa(prID)}, elem...)...)
+}
+
+func (a *cloudAPI) getPullRequest(ctx context.Context, repo string, prID int) (*PullRequest, error) {
+	u, err := a.pullRequestURL(repo, prID)
+	if err != nil {
+		return nil, err
+	}
+	var pr cloudPullRequest
+	if err := a.r.call(ctx, http.MethodGet, u, nil, &pr); err != nil {
+		return nil, err
+	}
+
+	result := &PullRequest{
+		ID:           pr.ID,
+		Title:        pr.Title,
+		State:        pr.State,
+		Author:       pr.Author.Nickname,
+		SourceBranch: pr.Source.Branch.Name,
+		SourceCommit: pr.Source.Commit.Hash,
+		TargetBranch: pr.Destination.Branch.Name,
+		WebURL:       pr.Links.HTML.Href,
+		FromFork:     !strings.EqualFold(pr.Source.Repository.FullName, pr.Destination.Repository.FullName),
+	}
+	if result.Author == "" {
+		result.Author = pr.Author.DisplayName
+	}
+	for _, p := range pr.Participants {
+		result.Approved = result.Approved || p.Approved
+	}
+
+	// Conflicts are only reported per file in the diffstat.
+	diffstat, err := a.diffstat(ctx, repo, prID)
+	if err != nil {
+		return nil, err
+	}
+	for _, d := range diffstat {
+		switch d.Status {
+		case "merge conflict", "local deleted", "remote deleted":
+			result.Conflicted = true
+		}
+	}
+	return result, nil
+}
+
+type cloudDiffstat struct {
+	Status string `json:"status"`
+	Old    *struct {
+		Path string `json:"path"`
+	} `json:"old"`
+	New *struct {
+		Path string `json:"path"`
+	} `json:"new"`
+}
+
+func (a *cloudAPI) diffstat(ctx context.Context, repo string, prID int) ([]cloudDiffstat, error) {
+	u, err := a.pullRequestURL(repo, prID, "diffstat")
+	if err != nil {
+		return nil, err
+	}
+	return listAll[cloudDiffstat](ctx, a.r, u)
+}
+
+func (a *cloudAPI) modifiedFiles(ctx context.Context, repo string, prID int) ([]string, error) {
+	diffstat, err := a.diffstat(ctx, repo, prID)
+	if err != nil {
+		return nil, err
+	}
+	files := make([]string, 0, len(diffstat))
+	for _, d := range diffstat {
+		if d.New != nil {
+			files = append(files, d.New.Path)
+		} else if d.Old != nil {
+			files = append(files, d.Old.Path)
+		}
+	}
+	return files, nil
+}
+
+func (a *cloudAPI) listComments(ctx context.Context, repo string, prID int) ([]*Comment, error) {
+	u, err := a.pullRequestURL(repo, prID, "comments")
+	if err != nil {
+		return nil, err
+	}
+	u.RawQuery = url.Values{"pagelen": []string{"100"}}.Encode()
+	values, err := listAll[cloudComment](ctx, a.r, u)
+	if err != nil {
+		return nil, err
+	}
+	comments := make([]*Comment, 0, len(values))
+	for i := range values {
+		if values[i].Deleted {
+			continue
+		}
+		comments = append(comments, values[i].toComment())
+	}
+	return comments, nil
+}
+
+func (a *cloudAPI) createComment(ctx context.Context, repo string, prID int, parentID int64, body string) (*Comment, error) {
+	u, err := a.pullRequestURL(repo, prID, "comments")
+	if err != nil {
+		return nil, err
+	}
+	req := map[string]any{"content": map[string]string{"raw": body}}
+	if parentID != 0 {
+		req["parent"] = map[string]int64{"id": parentID}
+	}
+	var comment cloudComment
+	if err := a.r.call(ctx, http.MethodPost, u, req, &comment); err != nil {
+		return nil, err
+	}
+	return comment.toComment(), nil
+}
+
+func (a *cloudAPI) updateComment(ctx context.Context, repo string, prID int, commentID int64, body string) (*Comment, error) {
+	u, err := a.pullRequestURL(repo, prID, "comments", strconv.FormatInt(commentID, 10))
+	if err != nil {
+		return nil, err
+	}
+	var comment cloudComment
+	req := map[string]any{"content": map[string]string{"raw": body}}
+	if err := a.r.call(ctx, http.MethodPut, u, req, &comment); err != nil {
+		return nil, err
+	}
+	return comment.toComment(), nil
+}
+
+func (a *cloudAPI) deleteComment(ctx context.Context, repo string, prID int, commentID int64) error {
+	u, err := a.pullRequestURL(repo, prID, "comments", strconv.FormatInt(commentID, 10))
+	if err != nil {
+		return err
+	}
+	return a.r.call(ctx, http.MethodDelete, u, nil, nil)
+}
+
+func (a *cloudAPI) getFile(ctx context.Context, repo, path, ref string) ([]byte, error) {
+	if ref == "" {
+		u, err := a.repoURL(repo)
+		if err != nil {
+			return nil, err
+		}
+		var r struct {
+			MainBranch struct {
+				Name string `json:"name"`
+			} `json:"mainbranch"`
+		}
+		if err := a.r.call(ctx, http.MethodGet, u, nil, &r); err != nil {
+			return nil, err
+		}
+		ref = r.MainBranch.Name
+	}
+	u, err := a.repoURL(repo, "src", ref, path)
+	if err != nil {
+		return nil, err
+	}
+	var content []byte
+	if err := a.r.call(ctx, http.MethodGet, u, nil, &content); err != nil {
+		return nil, err
+	}
+	return content, nil
+}
+
+func (a *cloudAPI) setBuildStatus(ctx context.Context, repo, commitSHA string, status *BuildStatusOptions) error {
+	u, err := a.repoURL(repo, "commit", commitSHA, "statuses", "build")
+	if err != nil {
+		return err
+	}
+	return a.r.call(ctx, http.MethodPost, u, map[string]string{
+		"key":         status.Name,
+		"name":        status.Name,
+		"state":       status.State,
+		"url":         status.TargetURL,
+		"description": status.Description,
+	}, nil)
+}
+
+func (a *cloudAPI) merge(ctx context.Context, repo string, prID int) error {
+	u, err := a.pullRequestURL(repo, prID, "merge")
+	if err != nil {
+		return err
+	}
+	return a.r.call(ctx, http.MethodPost, u, map[string]any{}, nil)
+}
+
+func (a *cloudAPI) currentUser(ctx context.Context) (string, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.self != "" {
+		return a.self, nil
+	}
+	var user cloudUser
+	if err := a.r.call(ctx, http.MethodGet, a.base.JoinPath("user"), nil, &user); err != nil {
+		return "", err
+	}
+	a.self = user.UUID
+	return a.self, nil
+}
+
+func (a *cloudAPI) cloneURL(repo string) string {
+	return a.web.JoinPath(repo + ".git").String()
+}
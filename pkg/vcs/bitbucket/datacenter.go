@@ -0,0 +1,321 @@
+package bitbucket
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// dataCenterAPI implements restAPI against the Bitbucket Data Center (and
+// Server) 1.0 API.
+type dataCenterAPI struct {
+	r    *requester
+	root *url.URL
+	base *url.URL
+
+	mu   sync.Mutex
+	self string
+}
+
+func newDataCenterAPI(r *requester, root *url.URL) *dataCenterAPI {
+	return &dataCenterAPI{r: r, root: root, base: root.JoinPath("rest", "api", "1.0")}
+}
+
+type dcUser struct {
+	Name string `json:"name"`
+	Slug string `json:"slug"`
+}
+
+type dcRef struct {
+	DisplayID    string `json:"displayId"`
+	LatestCommit string `json:"latestCommit"`
+	Repository   struct {
+		ID int `json:"id"`
+	} `json:"repository"`
+}
+
+type dcPullRequest struct {
+	ID      int    `json:"id"`
+	Version int    `json:"version"`
+	Title   string `json:"title"`
+	State   string `json:"state"`
+	Author  struct {
+		User dcUser `json:"user"`
+	} `json:"author"`
+	Reviewers []struct {
+		Approved bool `json:"approved"`
+	} `json:"reviewers"`
+	FromRef dcRef `json:"fromRef"`
+	ToRef   dcRef `json:"toRef"`
+	Links   struct {
+		Self []struct {
+			Href string `json:"href"`
+		} `json:"self"`
+	} `json:"links"`
+}
+
+type dcComment struct {
+	ID          int64  `json:"id"`
+	Version     int    `json:"version"`
+	Text        string `json:"text"`
+	Author      dcUser `json:"author"`
+	CreatedDate int64  `json:"createdDate"`
+}
+
+func (c *dcComment) toComment() *Comment {
+	return &Comment{
+		ID:        c.ID,
+		Body:      c.Text,
+		Author:    c.Author.Name,
+		CreatedAt: time.UnixMilli(c.CreatedDate).UTC(),
+		Version:   c.Version,
+	}
+}
+
+// dcPage is a page of a paginated Bitbucket Data Center collection.
+type dcPage[T any] struct {
+	Values        []T  `json:"values"`
+	IsLastPage    bool `json:"isLastPage"`
+	NextPageStart int  `json:"nextPageStart"`
+}
+
+// listAllDC requests pages until the last one.
+func listAllDC[T any](ctx context.Context, r *requester, u *url.URL) ([]T, error) {
+	var all []T
+	start := 0
+	for {
+		pageURL := *u
+		pageURL.RawQuery = url.Values{"start": []string{strconv.Itoa(start)}, "limit": []string{"100"}}.Encode()
+		var page dcPage[T]
+		if err := r.call(ctx, http.MethodGet, &pageURL, nil, &page); err != nil {
+			return nil, err
+		}
+		all = append(all, page.Values...)
+		if page.IsLastPage || len(page.Values) == 0 {
+			return all, nil
+		}
+		start = page.NextPageStart
+	}
+}
+
+func (a *dataCenterAPI) repoURL(repo string, elem ...string) (*url.URL, error) {
+	project, slug, err := splitFullName(repo)
+	if err != nil {
+		return nil, err
+	}
+	return a.base.JoinPath(append([]string{"projects", project, "repos", slug}, elem...)...), nil
+}
+
+func (a *dataCenterAPI) pullRequestURL(repo string, prID int, elem ...string) (*url.URL, error) {
+	return a.repoURL(repo, append([]string{"pull-requests", strconv.Itoa(prID)}, elem...)...)
+}
+
+func (a *dataCenterAPI) getPullRequest(ctx context.Context, repo string, prID int) (*PullRequest, error) {
+	u, err := a.pullRequestURL(repo, prID)
+	if err != nil {
+		return nil, err
+	}
+	var pr dcPullRequest
+	if err := a.r.call(ctx, http.MethodGet, u, nil, &pr); err != nil {
+		return nil, err
+	}
+	result := &PullRequest{
+		ID:           pr.ID,
+		Title:        pr.Title,
+		State:        pr.State,
+		Author:       pr.Author.User.Name,
+		SourceBranch: pr.FromRef.DisplayID,
+		SourceCommit: pr.FromRef.LatestCommit,
+		TargetBranch: pr.ToRef.DisplayID,
+		Version:      pr.Version,
+		FromFork:     pr.FromRef.Repository.ID != pr.ToRef.Repository.ID,
+	}
+	if len(pr.Links.Self) > 0 {
+		result.WebURL = pr.Links.Self[0].Href
+	}
+	for _, r := range pr.Reviewers {
+		result.Approved = result.Approved || r.Approved
+	}
+
+	mergeURL, err := a.pullRequestURL(repo, prID, "merge")
+	if err != nil {
+		return nil, err
+	}
+	var mergeStatus struct {
+		Conflicted bool `json:"conflicted"`
+	}
+	if err := a.r.call(ctx, http.MethodGet, mergeURL, nil, &mergeStatus); err != nil {
+		return nil, err
+	}
+	result.Conflicted = mergeStatus.Conflicted
+	return result, nil
+}
+
+func (a *dataCenterAPI) modifiedFiles(ctx context.Context, repo string, prID int) ([]string, error) {
+	u, err := a.pullRequestURL(repo, prID, "changes")
+	if err != nil {
+		return nil, err
+	}
+	type path struct {
+		ToString string `json:"toString"`
+	}
+	changes, err := listAllDC[struct {
+		Path    path  `json:"path"`
+		SrcPath *path `json:"srcPath"`
+	}](ctx, a.r, u)
+	if err != nil {
+		return nil, err
+	}
+	files := make([]string, 0, len(changes))
+	for _, c := range changes {
+		files = append(files, c.Path.ToString)
+		if c.SrcPath != nil && c.SrcPath.ToString != "" {
+			files = append(files, c.SrcPath.ToString)
+		}
+	}
+	return files, nil
+}
+
+// listComments reads comments from the pull request activity, the only
+// listing not scoped to a file.
+func (a *dataCenterAPI) listComments(ctx context.Context, repo string, prID int) ([]*Comment, error) {
+	u, err := a.pullRequestURL(repo, prID, "activities")
+	if err != nil {
+		return nil, err
+	}
+	activities, err := listAllDC[struct {
+		Action        string     `json:"action"`
+		CommentAction string     `json:"commentAction"`
+		Comment       *dcComment `json:"comment"`
+	}](ctx, a.r, u)
+	if err != nil {
+		return nil, err
+	}
+	var comments []*Comment
+	for _, act := range activities {
+		if act.Action == "COMMENTED" && act.CommentAction == "ADDED" && act.Comment != nil {
+			comments = append(comments, act.Comment.toComment())
+		}
+	}
+	return comments, nil
+}
+
+func (a *dataCenterAPI) createComment(ctx context.Context, repo string, prID int, parentID int64, body string) (*Comment, error) {
+	u, err := a.pullRequestURL(repo, prID, "comments")
+	if err != nil {
+		return nil, err
+	}
+	req := map[string]any{"text": body}
+	if parentID != 0 {
+		req["parent"] = map[string]int64{"id": parentID}
+	}
+	var comment dcComment
+	if err := a.r.call(ctx, http.MethodPost, u, req, &comment); err != nil {
+		return nil, err
+	}
+	c := comment.toComment()
+	c.ParentID = parentID
+	return c, nil
+}
+
+// getComment reads the current version of a comment, which updates and
+// deletes must name.
+func (a *dataCenterAPI) getComment(ctx context.Context, u *url.URL) (*dcComment, error) {
+	var comment dcComment
+	if err := a.r.call(ctx, http.MethodGet, u, nil, &comment); err != nil {
+		return nil, err
+	}
+	return &comment, nil
+}
+
+func (a *dataCenterAPI) updateComment(ctx context.Context, repo string, prID int, commentID int64, body string) (*Comment, error) {
+	u, err := a.pullRequestURL(repo, prID, "comments", strconv.FormatInt(commentID, 10))
+	if err != nil {
+		return nil, err
+	}
+	existing, err := a.getComment(ctx, u)
+	if err != nil {
+		return nil, err
+	}
+	var comment dcComment
+	req := map[string]any{"text": body, "version": existing.Version}
+	if err := a.r.call(ctx, http.MethodPut, u, req, &comment); err != nil {
+		return nil, err
+	}
+	return comment.toComment(), nil
+}
+
+func (a *dataCenterAPI) deleteComment(ctx context.Context, repo string, prID int, commentID int64) error {
+	u, err := a.pullRequestURL(repo, prID, "comments", strconv.FormatInt(commentID, 10))
+	if err != nil {
+		return err
+	}
+	existing, err := a.getComment(ctx, u)
+	if err != nil {
+		return err
+	}
+	u.RawQuery = url.Values{"version": []string{strconv.Itoa(existing.Version)}}.Encode()
+	return a.r.call(ctx, http.MethodDelete, u, nil, nil)
+}
+
+func (a *dataCenterAPI) getFile(ctx context.Context, repo, path, ref string) ([]byte, error) {
+	u, err := a.repoURL(repo, "raw", path)
+	if err != nil {
+		return nil, err
+	}
+	if ref != "" {
+		u.RawQuery = url.Values{"at": []string{ref}}.Encode()
+	}
+	var content []byte
+	if err := a.r.call(ctx, http.MethodGet, u, nil, &content); err != nil {
+		return nil, err
+	}
+	return content, nil
+}
+
+func (a *dataCenterAPI) setBuildStatus(ctx context.Context, repo, commitSHA string, status *BuildStatusOptions) error {
+	u := a.root.JoinPath("rest", "build-status", "1.0", "commits", commitSHA)
+	return a.r.call(ctx, http.MethodPost, u, map[string]string{
+		"key":         status.Name,
+		"name":        status.Name,
+		"state":       status.State,
+		"url":         status.TargetURL,
+		"description": status.Description,
+	}, nil)
+}
+
+func (a *dataCenterAPI) merge(ctx context.Context, repo string, prID int) error {
+	pr, err := a.getPullRequest(ctx, repo, prID)
+	if err != nil {
+		return err
+	}
+	u, err := a.pullRequestURL(repo, prID, "merge")
+	if err != nil {
+		return err
+	}
+	u.RawQuery = url.Values{"version": []string{strconv.Itoa(pr.Version)}}.Encode()
+	return a.r.call(ctx, http.MethodPost, u, nil, nil)
+}
+
+func (a *dataCenterAPI) currentUser(ctx context.Context) (string, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.self != "" {
+		return a.self, nil
+	}
+	var name []byte
+	if err := a.r.call(ctx, http.MethodGet, a.root.JoinPath("plugins", "servlet", "applinks", "whoami"), nil, &name); err != nil {
+		return "", err
+	}
+	a.self = strings.TrimSpace(string(name))
+	return a.self, nil
+}
+
+// cloneURL returns the HTTP clone URL, served under /scm on Data Center.
+func (a *dataCenterAPI) cloneURL(repo string) string {
+	return a.root.JoinPath("scm", strings.ToLower(repo)+".git").String()
+}
@@ -0,0 +1,145 @@
+package hooks
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/zapier/tfbuddy/pkg/pr_hooks"
+)
+
+// eventActions maps Bitbucket event keys to actions. Bitbucket Cloud keys
+// start with `pullrequest:`, Data Center ones with `pr:`.
+var eventActions = map[string]string{
+	// Bitbucket Cloud
+	"pullrequest:created":         pr_hooks.ActionOpened,
+	"pullrequest:updated":         pr_hooks.ActionUpdated,
+	"pullrequest:fulfilled":       pr_hooks.ActionClosed,
+	"pullrequest:rejected":        pr_hooks.ActionClosed,
+	"pullrequest:comment_created": pr_hooks.ActionCommented,
+	// Bitbucket Data Center
+	"pr:opened":           pr_hooks.ActionOpened,
+	"pr:from_ref_updated": pr_hooks.ActionUpdated,
+	"pr:merged":           pr_hooks.ActionClosed,
+	"pr:declined":         pr_hooks.ActionClosed,
+	"pr:deleted":          pr_hooks.ActionClosed,
+	"pr:comment:added":    pr_hooks.ActionCommented,
+}
+
+// parseEvent decodes a Bitbucket Cloud or Data Center webhook payload.
+func parseEvent(eventKey string, body []byte) (*pr_hooks.PullRequestEvent, error) {
+	action, ok := eventActions[eventKey]
+	if !ok {
+		return nil, pr_hooks.ErrUnhandledEvent
+	}
+	var (
+		event *pr_hooks.PullRequestEvent
+		err   error
+	)
+	if strings.HasPrefix(eventKey, "pullrequest:") {
+		event, err = parseCloudEvent(body)
+	} else {
+		event, err = parseDataCenterEvent(body)
+	}
+	if err != nil {
+		return nil, err
+	}
+	event.EventType = eventKey
+	event.Action = action
+	if event.Repo == "" || event.PRID == 0 {
+		return nil, fmt.Errorf("bitbucket %s event has no pull request", eventKey)
+	}
+	return event, nil
+}
+
+type cloudPayload struct {
+	Actor struct {
+		Nickname string `json:"nickname"`
+	} `json:"actor"`
+	Repository struct {
+		FullName string `json:"full_name"`
+	} `json:"repository"`
+	PullRequest struct {
+		ID     int `json:"id"`
+		Source struct {
+			Branch struct {
+				Name string `json:"name"`
+			} `json:"branch"`
+			Commit struct {
+				Hash string `json:"hash"`
+			} `json:"commit"`
+		} `json:"source"`
+	} `json:"pullrequest"`
+	Comment *struct {
+		ID      int64 `json:"id"`
+		Content struct {
+			Raw string `json:"raw"`
+		} `json:"content"`
+	} `json:"comment"`
+}
+
+func parseCloudEvent(body []byte) (*pr_hooks.PullRequestEvent, error) {
+	var p cloudPayload
+	if err := json.Unmarshal(body, &p); err != nil {
+		return nil, fmt.Errorf("could not decode bitbucket cloud event. %w", err)
+	}
+	event := &pr_hooks.PullRequestEvent{
+		Repo:         p.Repository.FullName,
+		PRID:         p.PullRequest.ID,
+		SourceBranch: p.PullRequest.Source.Branch.Name,
+		CommitSHA:    p.PullRequest.Source.Commit.Hash,
+		Author:       p.Actor.Nickname,
+	}
+	if p.Comment != nil {
+		event.CommentID = p.Comment.ID
+		event.Comment = p.Comment.Content.Raw
+	}
+	return event, nil
+}
+
+type dataCenterPayload struct {
+	Actor struct {
+		Slug string `json:"slug"`
+	} `json:"actor"`
+	PullRequest struct {
+		ID      int `json:"id"`
+		FromRef struct {
+			DisplayID    string `json:"displayId"`
+			LatestCommit string `json:"latestCommit"`
+		} `json:"fromRef"`
+		ToRef struct {
+			Repository struct {
+				Slug    string `json:"slug"`
+				Project struct {
+					Key string `json:"key"`
+				} `json:"project"`
+			} `json:"repository"`
+		} `json:"toRef"`
+	} `json:"pullRequest"`
+	Comment *struct {
+		ID   int64  `json:"id"`
+		Text string `json:"text"`
+	} `json:"comment"`
+}
+
+func parseDataCenterEvent(body []byte) (*pr_hooks.PullRequestEvent, error) {
+	var p dataCenterPayload
+	if err := json.Unmarshal(body, &p); err != nil {
+		return nil, fmt.Errorf("could not decode bitbucket data center event. %w", err)
+	}
+	repo := p.PullRequest.ToRef.Repository
+	event := &pr_hooks.PullRequestEvent{
+		PRID:         p.PullRequest.ID,
+		SourceBranch: p.PullRequest.FromRef.DisplayID,
+		CommitSHA:    p.PullRequest.FromRef.LatestCommit,
+		Author:       p.Actor.Slug,
+	}
+	if repo.Project.Key != "" && repo.Slug != "" {
+		event.Repo = repo.Project.Key + "/" + repo.Slug
+	}
+	if p.Comment != nil {
+		event.CommentID = p.Comment.ID
+		event.Comment = p.Comment.Text
+	}
+	return event, nil
+}
@@ -0,0 +1,103 @@
+package hooks
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strings"
+
+	"github.com/nats-io/nats.go"
+	"github.com/rs/zerolog/log"
+	"github.com/zapier/tfbuddy/internal/config"
+	"github.com/zapier/tfbuddy/pkg/allow_list"
+	"github.com/zapier/tfbuddy/pkg/pr_hooks"
+	"github.com/zapier/tfbuddy/pkg/runstream"
+	"github.com/zapier/tfbuddy/pkg/tfc_api"
+	"github.com/zapier/tfbuddy/pkg/tfc_trigger"
+	"github.com/zapier/tfbuddy/pkg/vcs"
+)
+
+const (
+	EventKeyHeader  = "X-Event-Key"
+	SignatureHeader = "X-Hub-Signature"
+)
+
+// provider adapts Bitbucket Cloud and Data Center webhooks to pull request
+// events.
+type provider struct {
+	cfg config.Config
+}
+
+// ensure type complies with interface
+var _ pr_hooks.Provider = (*provider)(nil)
+
+func NewBitbucketHooksHandler(cfg config.Config, vcs vcs.GitClient, tfc tfc_api.ApiClient, rs runstream.StreamClient, js nats.JetStreamContext, workspaceStream tfc_trigger.WorkspacePublisher) *pr_hooks.HooksHandler {
+	if cfg.BitbucketHookSecretKey == "" {
+		if cfg.BitbucketHookUnsigned {
+			log.Warn().Msgf("%s is not set and %s is enabled, Bitbucket webhooks are not verified", config.KeyBitbucketHookSecretKey, config.KeyBitbucketHookUnsigned)
+		} else {
+			log.Warn().Msgf("%s is not set, all Bitbucket webhooks are rejected", config.KeyBitbucketHookSecretKey)
+		}
+	}
+	return pr_hooks.NewHooksHandler(cfg, &provider{cfg: cfg}, vcs, tfc, rs, js, workspaceStream)
+}
+
+func (p *provider) Name() string {
+	return "bitbucket"
+}
+func (p *provider) DisplayName() string {
+	return "Bitbucket"
+}
+func (p *provider) EventType(r *http.Request) string {
+	return r.Header.Get(EventKeyHeader)
+}
+
+// ParseEvent decodes the webhook. The Data Center `diagnostics:ping` test
+// event is ignored like every other unhandled event key.
+func (p *provider) ParseEvent(r *http.Request, body []byte) (*pr_hooks.PullRequestEvent, string, error) {
+	event, err := parseEvent(r.Header.Get(EventKeyHeader), body)
+	return event, deliveryID(r), err
+}
+
+// IsRepoAllowed matches workspace/repo on Bitbucket Cloud or PROJECT/repo on
+// Data Center against the allow list.
+func (p *provider) IsRepoAllowed(cfg config.Config, repo string) bool {
+	return allow_list.IsBitbucketRepoAllowed(cfg, repo)
+}
+
+// deliveryID returns the unique id Bitbucket sends with each delivery.
+func deliveryID(r *http.Request) string {
+	if id := r.Header.Get("X-Request-UUID"); id != "" {
+		return id
+	}
+	return r.Header.Get("X-Request-Id")
+}
+
+// Verify checks the signature of the webhook. Without a secret configured
+// webhooks are only accepted when unsigned ones are explicitly allowed.
+func (p *provider) Verify(r *http.Request, body []byte) bool {
+	if p.cfg.BitbucketHookSecretKey == "" {
+		return p.cfg.BitbucketHookUnsigned
+	}
+	return verifySignature(p.cfg.BitbucketHookSecretKey, r.Header.Get(SignatureHeader), body)
+}
+
+// verifySignature checks the `sha256=<hex HMAC>` signature Bitbucket computes
+// over the body with the webhook secret.
+func verifySignature(secret, signature string, body []byte) bool {
+	if secret == "" {
+		return false
+	}
+	sig, ok := strings.CutPrefix(signature, "sha256=")
+	if !ok {
+		return false
+	}
+	got, err := hex.DecodeString(sig)
+	if err != nil {
+		return false
+	}
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return hmac.Equal(got, mac.Sum(nil))
+}
@@ -0,0 +1,119 @@
+package hooks
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/zapier/tfbuddy/internal/config"
+	"github.com/zapier/tfbuddy/pkg/pr_hooks"
+)
+
+func TestParseEvent(t *testing.T) {
+	tests := []struct {
+		name     string
+		eventKey string
+		body     string
+		want     *pr_hooks.PullRequestEvent
+		wantErr  error
+	}{
+		{
+			name:     "cloud pull request created",
+			eventKey: "pullrequest:created",
+			body:     `{"repository":{"full_name":"zapier/tfbuddy"},"pullrequest":{"id":7,"source":{"branch":{"name":"feature"},"commit":{"hash":"abc123"}}}}`,
+			want:     &pr_hooks.PullRequestEvent{EventType: "pullrequest:created", Action: pr_hooks.ActionOpened, Repo: "zapier/tfbuddy", PRID: 7, SourceBranch: "feature", CommitSHA: "abc123"},
+		},
+		{
+			name:     "cloud comment",
+			eventKey: "pullrequest:comment_created",
+			body:     `{"actor":{"nickname":"alice"},"repository":{"full_name":"zapier/tfbuddy"},"pullrequest":{"id":7},"comment":{"id":42,"content":{"raw":"tfc plan"}}}`,
+			want:     &pr_hooks.PullRequestEvent{EventType: "pullrequest:comment_created", Action: pr_hooks.ActionCommented, Repo: "zapier/tfbuddy", PRID: 7, CommentID: 42, Comment: "tfc plan", Author: "alice"},
+		},
+		{
+			name:     "data center comment",
+			eventKey: "pr:comment:added",
+			body:     `{"actor":{"slug":"alice"},"pullRequest":{"id":7,"toRef":{"repository":{"slug":"tfbuddy","project":{"key":"ZAP"}}}},"comment":{"id":42,"text":"tfc plan"}}`,
+			want:     &pr_hooks.PullRequestEvent{EventType: "pr:comment:added", Action: pr_hooks.ActionCommented, Repo: "ZAP/tfbuddy", PRID: 7, CommentID: 42, Comment: "tfc plan", Author: "alice"},
+		},
+		{
+			name:     "data center source updated",
+			eventKey: "pr:from_ref_updated",
+			body:     `{"pullRequest":{"id":7,"fromRef":{"displayId":"feature","latestCommit":"abc123"},"toRef":{"repository":{"slug":"tfbuddy","project":{"key":"ZAP"}}}}}`,
+			want:     &pr_hooks.PullRequestEvent{EventType: "pr:from_ref_updated", Action: pr_hooks.ActionUpdated, Repo: "ZAP/tfbuddy", PRID: 7, SourceBranch: "feature", CommitSHA: "abc123"},
+		},
+		{
+			name:     "data center merged",
+			eventKey: "pr:merged",
+			body:     `{"pullRequest":{"id":7,"toRef":{"repository":{"slug":"tfbuddy","project":{"key":"ZAP"}}}}}`,
+			want:     &pr_hooks.PullRequestEvent{EventType: "pr:merged", Action: pr_hooks.ActionClosed, Repo: "ZAP/tfbuddy", PRID: 7},
+		},
+		{
+			name:     "data center ping",
+			eventKey: "diagnostics:ping",
+			body:     `{}`,
+			wantErr:  pr_hooks.ErrUnhandledEvent,
+		},
+		{
+			name:     "missing pull request",
+			eventKey: "pullrequest:created",
+			body:     `{"repository":{"full_name":"zapier/tfbuddy"}}`,
+			wantErr:  errors.New("bitbucket pullrequest:created event has no pull request"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseEvent(tt.eventKey, []byte(tt.body))
+			if tt.wantErr != nil {
+				if err == nil || err.Error() != tt.wantErr.Error() {
+					t.Fatalf("parseEvent() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if *got != *tt.want {
+				t.Errorf("parseEvent() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestVerifySignature(t *testing.T) {
+	body := []byte(`{"pullrequest":{"id":7}}`)
+	mac := hmac.New(sha256.New, []byte("secret"))
+	mac.Write(body)
+	valid := "sha256=" + hex.EncodeToString(mac.Sum(nil))
+
+	tests := []struct {
+		name          string
+		secret        string
+		allowUnsigned bool
+		signature     string
+		want          bool
+	}{
+		{name: "valid", secret: "secret", signature: valid, want: true},
+		{name: "wrong secret", secret: "other", signature: valid},
+		{name: "missing", secret: "secret"},
+		{name: "not hex", secret: "secret", signature: "sha256=zz"},
+		{name: "no secret configured", signature: valid},
+		{name: "unsigned allowed", allowUnsigned: true, want: true},
+		{name: "secret configured and unsigned allowed", secret: "secret", allowUnsigned: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := &provider{cfg: config.Config{BitbucketHookSecretKey: tt.secret, BitbucketHookUnsigned: tt.allowUnsigned}}
+			r := httptest.NewRequest(http.MethodPost, "/hooks/bitbucket", nil)
+			if tt.signature != "" {
+				r.Header.Set(SignatureHeader, tt.signature)
+			}
+			if got := p.Verify(r, body); got != tt.want {
+				t.Errorf("Verify() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
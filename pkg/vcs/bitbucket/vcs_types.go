@@ -0,0 +1,165 @@
+package bitbucket
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/zapier/tfbuddy/pkg/vcs"
+)
+
+// ----------------------------------------------------------------------------
+// ensure type complies with interface
+var _ vcs.DetailedMR = (*PullRequest)(nil)
+var _ vcs.MRApproved = (*PullRequest)(nil)
+var _ vcs.ForkMR = (*PullRequest)(nil)
+
+// PullRequest is a Bitbucket Cloud or Data Center pull request.
+type PullRequest struct {
+	ID           int
+	Title        string
+	State        string
+	Author       string
+	SourceBranch string
+	SourceCommit string
+	TargetBranch string
+	WebURL       string
+	Approved     bool
+	Conflicted   bool
+	// FromFork is set when the source branch lives in another repository.
+	FromFork bool
+	// Version is required by Bitbucket Data Center to update the pull request.
+	Version int
+}
+
+func (pr *PullRequest) HasConflicts() bool {
+	return pr.Conflicted
+}
+func (pr *PullRequest) GetSourceBranch() string {
+	return pr.SourceBranch
+}
+func (pr *PullRequest) GetTargetBranch() string {
+	return pr.TargetBranch
+}
+func (pr *PullRequest) GetAuthor() vcs.MRAuthor {
+	return &Author{pr.Author}
+}
+func (pr *PullRequest) GetInternalID() int {
+	return pr.ID
+}
+func (pr *PullRequest) GetWebURL() string {
+	return pr.WebURL
+}
+func (pr *PullRequest) GetTitle() string {
+	return pr.Title
+}
+func (pr *PullRequest) GetState() string {
+	return pr.State
+}
+
+// IsFromFork reports whether the source branch lives in a fork of the target
+// repository.
+func (pr *PullRequest) IsFromFork() bool {
+	return pr.FromFork
+}
+func (pr *PullRequest) GetHeadSHA() string {
+	return pr.SourceCommit
+}
+
+// IsApproved reports whether at least one reviewer approved the pull request.
+func (pr *PullRequest) IsApproved() bool {
+	return pr.Approved
+}
+
+// ----------------------------------------------------------------------------
+// ensure type complies with interface
+var _ vcs.MRAuthor = (*Author)(nil)
+
+type Author struct {
+	Username string
+}
+
+func (a *Author) GetUsername() string {
+	return a.Username
+}
+
+// ----------------------------------------------------------------------------
+// ensure type complies with interface
+var _ vcs.MRDiscussionNotes = (*Comment)(nil)
+var _ vcs.MRNote = (*Comment)(nil)
+
+// Comment is a pull request comment. Bitbucket has no separate discussion
+// objects: a top level comment is the discussion and replies are nested
+// under it.
+type Comment struct {
+	ID int64
+	// ParentID is the comment this one replies to, zero for top level comments.
+	ParentID  int64
+	Body      string
+	Author    string
+	CreatedAt time.Time
+	Version   int
+}
+
+func (c *Comment) GetNoteID() int64 {
+	return c.ID
+}
+func (c *Comment) GetDiscussionID() string {
+	return fmt.Sprintf("%d", c.ID)
+}
+
+// GetMRNotes returns the comment itself: it is the root note that run status
+// updates edit in place.
+func (c *Comment) GetMRNotes() []vcs.MRNote {
+	return []vcs.MRNote{c}
+}
+
+// ----------------------------------------------------------------------------
+// Build states shared by Bitbucket Cloud and Data Center.
+const (
+	BuildStateInProgress = "INPROGRESS"
+	BuildStateSuccessful = "SUCCESSFUL"
+	BuildStateFailed     = "FAILED"
+)
+
+// ensure type complies with interface
+var _ vcs.CommitStatusOptions = (*BuildStatusOptions)(nil)
+
+// BuildStatusOptions describes a build status on a commit. Name is used as
+// the build key, so updates with the same name replace the previous status.
+type BuildStatusOptions struct {
+	Name        string
+	TargetURL   string
+	Description string
+	State       string
+}
+
+func (o *BuildStatusOptions) GetName() string {
+	return o.Name
+}
+func (o *BuildStatusOptions) GetContext() string {
+	return o.Name
+}
+func (o *BuildStatusOptions) GetTargetURL() string {
+	return o.TargetURL
+}
+func (o *BuildStatusOptions) GetDescription() string {
+	return o.Description
+}
+func (o *BuildStatusOptions) GetState() string {
+	return o.State
+}
+func (o *BuildStatusOptions) GetPipelineID() int {
+	return 0
+}
+
+// ensure type complies with interface
+var _ vcs.CommitStatus = (*BuildStatus)(nil)
+
+type BuildStatus struct {
+	Key   string
+	State string
+}
+
+func (s *BuildStatus) Info() string {
+	return fmt.Sprintf("%s %s", s.Key, s.State)
+}
@@ -1,6 +1,14 @@
 package vcs
 
-import "github.com/zapier/tfbuddy/internal/config"
+import (
+	"errors"
+
+	"github.com/zapier/tfbuddy/internal/config"
+)
+
+// ErrForkNotSupported is returned by providers that cannot run merge requests
+// from forks safely yet.
+var ErrForkNotSupported = errors.New("merge requests from forks are not supported on this VCS provider")
 
 func IsGlobalAutoMergeEnabled(cfg config.Config) bool {
 	return cfg.AllowAutoMerge
@@ -0,0 +1,612 @@
+package gitea
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cenkalti/backoff/v4"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/protocol/packp/sideband"
+	githttp "github.com/go-git/go-git/v5/plumbing/transport/http"
+	"github.com/rs/zerolog/log"
+	"github.com/zapier/tfbuddy/internal/config"
+	zgit "github.com/zapier/tfbuddy/pkg/git"
+	"github.com/zapier/tfbuddy/pkg/utils"
+	"github.com/zapier/tfbuddy/pkg/vcs"
+	"go.opentelemetry.io/otel"
+)
+
+// ensure type complies with interface
+var _ vcs.GitClient = (*Client)(nil)
+
+const DefaultMaxRetries = 3
+
+const GITEA_CLONE_DEPTH_ENV = "TFBUDDY_GITEA_CLONE_DEPTH"
+
+// pageSize is the number of items requested per page of paginated lists.
+const pageSize = 50
+
+func createBackOffWithRetries() backoff.BackOff {
+	exp := backoff.NewExponentialBackOff()
+	exp.MaxElapsedTime = 30 * time.Second
+	return backoff.WithMaxRetries(exp, DefaultMaxRetries)
+}
+
+// Client talks to the v1 API of a Gitea or Forgejo instance. Forgejo keeps
+// the Gitea API, so both are served by the same client.
+type Client struct {
+	r     *requester
+	web   *url.URL
+	api   *url.URL
+	token string
+	cfg   config.Config
+
+	mu   sync.Mutex
+	self string
+}
+
+// NewGiteaClient creates a client for the instance at `gitea-url`,
+// authenticated with the access token in GITEA_TOKEN.
+func NewGiteaClient(cfg config.Config) *Client {
+	token := os.Getenv("GITEA_TOKEN")
+	if token == "" {
+		log.Info().Msg("GITEA_TOKEN is not set, skipping creation of Gitea API client")
+		return nil
+	}
+	c, err := newClient(cfg, http.DefaultClient, token)
+	if err != nil {
+		log.Fatal().Err(err).Msg("could not create Gitea client")
+	}
+	return c
+}
+
+func newClient(cfg config.Config, httpClient *http.Client, token string) (*Client, error) {
+	web, err := url.Parse(strings.TrimSuffix(strings.TrimSuffix(cfg.GiteaURL, "/"), "/api/v1") + "/")
+	if err != nil || web.Host == "" {
+		return nil, fmt.Errorf("invalid gitea url %q", cfg.GiteaURL)
+	}
+	return &Client{
+		r:     &requester{http: httpClient, token: token},
+		web:   web,
+		api:   web.JoinPath("api", "v1"),
+		token: token,
+		cfg:   cfg,
+	}, nil
+}
+
+// ----------------------------------------------------------------------------
+// API types
+
+type apiUser struct {
+	Login string `json:"login"`
+}
+
+type apiPullRequest struct {
+	Number    int     `json:"number"`
+	Title     string  `json:"title"`
+	State     string  `json:"state"`
+	Mergeable bool    `json:"mergeable"`
+	HTMLURL   string  `json:"html_url"`
+	User      apiUser `json:"user"`
+	Head      struct {
+		Ref    string `json:"ref"`
+		Sha    string `json:"sha"`
+		RepoID int64  `json:"repo_id"`
+	} `json:"head"`
+	Base struct {
+		Ref    string `json:"ref"`
+		RepoID int64  `json:"repo_id"`
+	} `json:"base"`
+}
+
+type apiReview struct {
+	ID        int64     `json:"id"`
+	State     string    `json:"state"`
+	User      apiUser   `json:"user"`
+	Dismissed bool      `json:"dismissed"`
+	Submitted time.Time `json:"submitted_at"`
+}
+
+type apiComment struct {
+	ID        int64     `json:"id"`
+	Body      string    `json:"body"`
+	User      apiUser   `json:"user"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func (c *apiComment) toComment() *Comment {
+	return &Comment{ID: c.ID, Body: c.Body, Author: c.User.Login, CreatedAt: c.CreatedAt}
+}
+
+// ----------------------------------------------------------------------------
+
+func (c *Client) repoURL(repo string, elem ...string) (*url.URL, error) {
+	owner, name, err := splitFullName(repo)
+	if err != nil {
+		return nil, err
+	}
+	return c.api.JoinPath(append([]string{"repos", owner, name}, elem...)...), nil
+}
+
+func (c *Client) GetMergeRequestApprovals(ctx context.Context, id int, project string) (vcs.MRApproved, error) {
+	ctx, span := otel.Tracer("TFC").Start(ctx, "GetMergeRequestApprovals")
+	defer span.End()
+
+	return c.getPullRequest(ctx, project, id)
+}
+
+func (c *Client) GetMergeRequest(ctx context.Context, prID int, fullName string) (vcs.DetailedMR, error) {
+	ctx, span := otel.Tracer("hooks").Start(ctx, "GetMergeRequest")
+	defer span.End()
+
+	return c.getPullRequest(ctx, fullName, prID)
+}
+
+func (c *Client) getPullRequest(ctx context.Context, repo string, prID int) (*PullRequest, error) {
+	u, err := c.repoURL(repo, "pulls", strconv.Itoa(prID))
+	if err != nil {
+		return nil, err
+	}
+	var pr apiPullRequest
+	if err := c.r.call(ctx, http.MethodGet, u, nil, &pr); err != nil {
+		return nil, err
+	}
+
+	reviewsURL, err := c.repoURL(repo, "pulls", strconv.Itoa(prID), "reviews")
+	if err != nil {
+		return nil, err
+	}
+	reviews, err := listAll[apiReview](ctx, c.r, reviewsURL)
+	if err != nil {
+		return nil, err
+	}
+
+	return &PullRequest{
+		Number:       pr.Number,
+		Title:        pr.Title,
+		State:        pr.State,
+		Author:       pr.User.Login,
+		SourceBranch: pr.Head.Ref,
+		SourceCommit: pr.Head.Sha,
+		TargetBranch: pr.Base.Ref,
+		WebURL:       pr.HTMLURL,
+		Mergeable:    pr.Mergeable,
+		Approved:     isApproved(reviews),
+		FromFork:     pr.Head.RepoID != pr.Base.RepoID,
+	}, nil
+}
+
+// isApproved looks at the latest approving or change requesting review of
+// each reviewer: at least one must approve and none may request changes.
+func isApproved(reviews []apiReview) bool {
+	latest := map[string]string{}
+	for _, r := range reviews {
+		if r.Dismissed {
+			continue
+		}
+		switch r.State {
+		case "APPROVED", "REQUEST_CHANGES":
+			// reviews are listed oldest first
+			latest[r.User.Login] = r.State
+		}
+	}
+	approved := false
+	for _, state := range latest {
+		if state == "REQUEST_CHANGES" {
+			return false
+		}
+		approved = true
+	}
+	return approved
+}
+
+func (c *Client) CreateMergeRequestComment(ctx context.Context, id int, fullPath string, comment string) error {
+	ctx, span := otel.Tracer("TFC").Start(ctx, "CreateMergeRequestComment")
+	defer span.End()
+
+	_, err := c.createComment(ctx, fullPath, id, comment)
+	return err
+}
+
+func (c *Client) CreateMergeRequestDiscussion(ctx context.Context, mrID int, fullPath string, comment string) (vcs.MRDiscussionNotes, error) {
+	ctx, span := otel.Tracer("TFC").Start(ctx, "CreateMergeRequestDiscussion")
+	defer span.End()
+
+	return c.createComment(ctx, fullPath, mrID, comment)
+}
+
+func (c *Client) createComment(ctx context.Context, repo string, prID int, body string) (*Comment, error) {
+	if body == "" {
+		return nil, utils.CreatePermanentError(fmt.Errorf("comment is empty"))
+	}
+	// pull requests share their number with the issue they are built on
+	u, err := c.repoURL(repo, "issues", strconv.Itoa(prID), "comments")
+	if err != nil {
+		return nil, err
+	}
+	var comment apiComment
+	if err := c.r.call(ctx, http.MethodPost, u, map[string]string{"body": body}, &comment); err != nil {
+		return nil, err
+	}
+	return comment.toComment(), nil
+}
+
+// UpdateMergeRequestDiscussionNote edits the comment noteID in place.
+func (c *Client) UpdateMergeRequestDiscussionNote(ctx context.Context, mrIID, noteID int, project, discussionID, comment string) (vcs.MRNote, error) {
+	ctx, span := otel.Tracer("TFC").Start(ctx, "UpdateMergeRequestDiscussionNote")
+	defer span.End()
+
+	u, err := c.repoURL(project, "issues", "comments", strconv.Itoa(noteID))
+	if err != nil {
+		return nil, err
+	}
+	var updated apiComment
+	if err := c.r.call(ctx, http.MethodPatch, u, map[string]string{"body": comment}, &updated); err != nil {
+		return nil, err
+	}
+	return updated.toComment(), nil
+}
+
+func (c *Client) ResolveMergeRequestDiscussion(ctx context.Context, project string, mrIID int, discussionID string) error {
+	// Status comments are edited in place, so there is no thread to resolve.
+	return nil
+}
+
+// AddMergeRequestDiscussionReply posts a new comment, Gitea has no replies
+// on the conversation tab.
+func (c *Client) AddMergeRequestDiscussionReply(ctx context.Context, mrIID int, project, discussionID, comment string) (vcs.MRNote, error) {
+	ctx, span := otel.Tracer("TFC").Start(ctx, "AddMergeRequestDiscussionReply")
+	defer span.End()
+
+	return c.createComment(ctx, project, mrIID, comment)
+}
+
+func (c *Client) GetRepoFile(ctx context.Context, fullName string, file string, ref string) ([]byte, error) {
+	ctx, span := otel.Tracer("TFC").Start(ctx, "GetRepoFile")
+	defer span.End()
+
+	u, err := c.repoURL(fullName, append([]string{"raw"}, strings.Split(strings.TrimPrefix(file, "/"), "/")...)...)
+	if err != nil {
+		return nil, err
+	}
+	if ref != "" {
+		u.RawQuery = url.Values{"ref": []string{ref}}.Encode()
+	}
+	var content []byte
+	if err := c.r.call(ctx, http.MethodGet, u, nil, &content); err != nil {
+		return nil, err
+	}
+	return content, nil
+}
+
+func (c *Client) GetMergeRequestModifiedFiles(ctx context.Context, prID int, fullName string) ([]string, error) {
+	ctx, span := otel.Tracer("TFC").Start(ctx, "GetMergeRequestModifiedFiles")
+	defer span.End()
+
+	u, err := c.repoURL(fullName, "pulls", strconv.Itoa(prID), "files")
+	if err != nil {
+		return nil, err
+	}
+	changes, err := listAll[struct {
+		Filename         string `json:"filename"`
+		PreviousFilename string `json:"previous_filename"`
+	}](ctx, c.r, u)
+	if err != nil {
+		return nil, err
+	}
+	files := make([]string, 0, len(changes))
+	for _, f := range changes {
+		files = append(files, f.Filename)
+		if f.PreviousFilename != "" {
+			files = append(files, f.PreviousFilename)
+		}
+	}
+	return files, nil
+}
+
+func (c *Client) SetCommitStatus(ctx context.Context, projectWithNS string, commitSHA string, status vcs.CommitStatusOptions) (vcs.CommitStatus, error) {
+	ctx, span := otel.Tracer("TFC").Start(ctx, "SetCommitStatus")
+	defer span.End()
+
+	u, err := c.repoURL(projectWithNS, "statuses", commitSHA)
+	if err != nil {
+		return nil, err
+	}
+	var created CommitStatus
+	err = c.r.call(ctx, http.MethodPost, u, map[string]string{
+		"context":     status.GetContext(),
+		"state":       status.GetState(),
+		"target_url":  status.GetTargetURL(),
+		"description": status.GetDescription(),
+	}, &created)
+	if err != nil {
+		return nil, err
+	}
+	return &created, nil
+}
+
+// GetPipelinesForCommit returns no pipelines: commit statuses are not tied to
+// a pipeline.
+func (c *Client) GetPipelinesForCommit(ctx context.Context, projectWithNS string, commitSHA string) ([]vcs.ProjectPipeline, error) {
+	return nil, nil
+}
+
+func (c *Client) MergeMR(ctx context.Context, mrIID int, project string) error {
+	ctx, span := otel.Tracer("TFC").Start(ctx, "MergeMR")
+	defer span.End()
+
+	u, err := c.repoURL(project, "pulls", strconv.Itoa(mrIID), "merge")
+	if err != nil {
+		return err
+	}
+	return c.r.call(ctx, http.MethodPost, u, map[string]string{"Do": "merge"}, nil)
+}
+
+// currentUser returns the login TFBuddy authenticates as.
+func (c *Client) currentUser(ctx context.Context) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.self != "" {
+		return c.self, nil
+	}
+	var user apiUser
+	if err := c.r.call(ctx, http.MethodGet, c.api.JoinPath("user"), nil, &user); err != nil {
+		return "", err
+	}
+	c.self = user.Login
+	return c.self, nil
+}
+
+// GetOldRunUrls crawls PR comments authored by TFBuddy, collects previous TFC
+// run URLs into a collapsible block, and (when TFBUDDY_DELETE_OLD_COMMENTS is
+// set) deletes old comments that belong to the same workspace+action
+// combination.
+func (c *Client) GetOldRunUrls(ctx context.Context, prID int, fullName string, rootCommentID int, workspace string, action string) (string, error) {
+	ctx, span := otel.Tracer("TFC").Start(ctx, "GetOldRunURLs")
+	defer span.End()
+
+	u, err := c.repoURL(fullName, "issues", strconv.Itoa(prID), "comments")
+	if err != nil {
+		return "", err
+	}
+	var comments []apiComment
+	if err := c.r.call(ctx, http.MethodGet, u, nil, &comments); err != nil {
+		return "", err
+	}
+	self, err := c.currentUser(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	var oldRunUrls []string
+	var oldRunBlock string
+	var matchingCommentIDs []int64
+	for _, comment := range comments {
+		if comment.User.Login != self {
+			continue
+		}
+		noteWS, noteAction, hasMarker := utils.ParseTFBuddyMarker(comment.Body)
+		if !hasMarker || noteWS != workspace || noteAction != action {
+			continue
+		}
+
+		runUrl := utils.CaptureSubstring(comment.Body, utils.URL_RUN_PREFIX, utils.URL_RUN_SUFFIX)
+		runUrlRaw := utils.CaptureSubstring(runUrl, "[", "]")
+		runUrlSplit := strings.Split(runUrlRaw, "/")
+		runID := runUrlSplit[len(runUrlSplit)-1]
+		runStatus := utils.CaptureSubstring(comment.Body, utils.URL_RUN_STATUS_PREFIX, utils.URL_RUN_SUFFIX)
+		if runUrl != "" && runStatus != "" && comment.ID != int64(rootCommentID) {
+			oldRunUrls = append(oldRunUrls, fmt.Sprintf("|[%s](%s)|%s|%s|", runID, runUrlRaw, runStatus, comment.CreatedAt))
+		}
+
+		oldRunBlockTest := utils.CaptureSubstring(comment.Body, utils.URL_RUN_GROUP_PREFIX, utils.URL_RUN_GROUP_SUFFIX)
+		if oldRunBlockTest != "" {
+			oldRunBlock = oldRunBlockTest
+		}
+
+		if comment.ID != int64(rootCommentID) {
+			matchingCommentIDs = append(matchingCommentIDs, comment.ID)
+		}
+	}
+
+	if c.cfg.DeleteOldComments {
+		for _, commentID := range matchingCommentIDs {
+			log.Debug().Str("workspace", workspace).Str("action", action).Msgf("Deleting comment %d", commentID)
+			u, err := c.repoURL(fullName, "issues", "comments", strconv.FormatInt(commentID, 10))
+			if err != nil {
+				return "", err
+			}
+			if err := c.r.call(ctx, http.MethodDelete, u, nil, nil); err != nil {
+				return "", err
+			}
+		}
+	}
+
+	if len(oldRunUrls) > 0 {
+		if oldRunBlock == "" {
+			oldRunBlock = "\n"
+		}
+		return fmt.Sprintf("%s%s%s\n%s", utils.URL_RUN_GROUP_PREFIX, oldRunBlock, strings.Join(oldRunUrls, "\n"), utils.URL_RUN_GROUP_SUFFIX), nil
+	}
+	if strings.TrimSpace(oldRunBlock) == "" {
+		return "", nil
+	}
+	return oldRunBlock, nil
+}
+
+// gitAuth returns the credentials used to clone and pull. Gitea accepts an
+// access token as the username with this placeholder password.
+func (c *Client) gitAuth() *githttp.BasicAuth {
+	return &githttp.BasicAuth{
+		Username: c.token,
+		Password: "x-oauth-basic",
+	}
+}
+
+// CloneMergeRequest performs a git clone of the pull request source branch to the `dest` path.
+func (c *Client) CloneMergeRequest(ctx context.Context, project string, mr vcs.MR, dest string) (vcs.GitRepo, error) {
+	_, span := otel.Tracer("TFC").Start(ctx, "CloneMergeRequest")
+	defer span.End()
+
+	if fork, ok := mr.(vcs.ForkMR); ok && fork.IsFromFork() {
+		// the source branch does not exist in the target repository
+		return nil, vcs.ErrForkNotSupported
+	}
+	if _, _, err := splitFullName(project); err != nil {
+		return nil, err
+	}
+	ref := plumbing.NewBranchReferenceName(mr.GetSourceBranch())
+	auth := c.gitAuth()
+
+	var progress sideband.Progress
+	if log.Trace().Enabled() {
+		progress = os.Stdout
+	}
+	cloneDepth := zgit.GetCloneDepth(c.cfg, GITEA_CLONE_DEPTH_ENV)
+
+	repo, err := git.PlainClone(dest, false, &git.CloneOptions{
+		Auth:          auth,
+		URL:           c.web.JoinPath(project + ".git").String(),
+		ReferenceName: ref,
+		SingleBranch:  true,
+		Depth:         cloneDepth,
+		Progress:      progress,
+	})
+	if err != nil && err != git.ErrRepositoryAlreadyExists {
+		err = fmt.Errorf("could not clone MR: %v", err)
+		span.RecordError(err)
+		return nil, err
+	}
+
+	wt, _ := repo.Worktree()
+	err = wt.Pull(&git.PullOptions{
+		ReferenceName: ref,
+		Depth:         cloneDepth,
+		Auth:          auth,
+		Progress:      progress,
+	})
+	if err != nil && err != git.NoErrAlreadyUpToDate {
+		err = fmt.Errorf("could not pull MR: %v", err)
+		span.RecordError(err)
+		return nil, err
+	}
+
+	if log.Trace().Enabled() {
+		//nolint
+		filepath.WalkDir(dest, zgit.WalkRepo)
+	}
+	return zgit.NewRepository(repo, auth, dest), nil
+}
+
+// splitFullName returns the owner and name of a repository.
+func splitFullName(fullName string) (string, string, error) {
+	owner, repo, ok := strings.Cut(fullName, "/")
+	if !ok || owner == "" || repo == "" || strings.Contains(repo, "/") {
+		return "", "", utils.CreatePermanentError(fmt.Errorf("gitea client: invalid repo format %q", fullName))
+	}
+	return owner, repo, nil
+}
+
+// listAll requests pages of a list until a short page.
+func listAll[T any](ctx context.Context, r *requester, u *url.URL) ([]T, error) {
+	var all []T
+	for page := 1; ; page++ {
+		pageURL := *u
+		pageURL.RawQuery = url.Values{"page": []string{strconv.Itoa(page)}, "limit": []string{strconv.Itoa(pageSize)}}.Encode()
+		var values []T
+		if err := r.call(ctx, http.MethodGet, &pageURL, nil, &values); err != nil {
+			return nil, err
+		}
+		all = append(all, values...)
+		if len(values) < pageSize {
+			return all, nil
+		}
+	}
+}
+
+// ----------------------------------------------------------------------------
+
+// requester sends requests authenticated with an access token to the Gitea
+// API.
+type requester struct {
+	http  *http.Client
+	token string
+}
+
+// call sends a request, retrying throttling and server errors. out is
+// decoded from the JSON response, or receives the raw body when it is a
+// *[]byte.
+func (r *requester) call(ctx context.Context, method string, u *url.URL, body any, out any) error {
+	return backoff.Retry(func() error {
+		status, err := r.do(ctx, method, u, body, out)
+		switch {
+		case err == nil:
+			return nil
+		case status == 0:
+			// transport error
+			return err
+		case status >= 400:
+			return utils.CreatePermanentHTTPError(status, err)
+		default:
+			return utils.CreatePermanentError(err)
+		}
+	}, createBackOffWithRetries())
+}
+
+func (r *requester) do(ctx context.Context, method string, u *url.URL, body any, out any) (int, error) {
+	var reqBody io.Reader
+	if body != nil {
+		b, err := json.Marshal(body)
+		if err != nil {
+			return -1, err
+		}
+		reqBody = bytes.NewReader(b)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), reqBody)
+	if err != nil {
+		return -1, err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Authorization", "token "+r.token)
+
+	resp, err := r.http.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return 0, err
+	}
+	if resp.StatusCode >= 400 {
+		return resp.StatusCode, fmt.Errorf("gitea api: %s %s returned %d: %s", method, u.Path, resp.StatusCode, strings.TrimSpace(string(respBody)))
+	}
+	switch o := out.(type) {
+	case nil:
+	case *[]byte:
+		*o = respBody
+	default:
+		if len(respBody) == 0 {
+			break
+		}
+		if err := json.Unmarshal(respBody, out); err != nil {
+			return resp.StatusCode, fmt.Errorf("could not decode gitea response for %s. %w", u.Path, err)
+		}
+	}
+	return resp.StatusCode, nil
+}
@@ -0,0 +1,304 @@
+package gitea
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/zapier/tfbuddy/internal/config"
+	"github.com/zapier/tfbuddy/pkg/utils"
+	"github.com/zapier/tfbuddy/pkg/vcs"
+)
+
+// newTestClient returns a client for an instance served by handler.
+func newTestClient(t *testing.T, cfg config.Config, handler http.HandlerFunc) *Client {
+	t.Helper()
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+	cfg.GiteaURL = srv.URL
+	c, err := newClient(cfg, srv.Client(), "token")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c
+}
+
+// serveFixture writes the recorded API response testdata/name.
+func serveFixture(t *testing.T, w http.ResponseWriter, name string) {
+	t.Helper()
+	b, err := os.ReadFile("testdata/" + name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w.Write(b)
+}
+
+func TestNewClient(t *testing.T) {
+	tests := []struct {
+		name    string
+		url     string
+		wantAPI string
+		wantErr bool
+	}{
+		{name: "instance", url: "https://forgejo.example.com", wantAPI: "https://forgejo.example.com/api/v1"},
+		{name: "sub path", url: "https://example.com/git/", wantAPI: "https://example.com/git/api/v1"},
+		{name: "api url", url: "https://forgejo.example.com/api/v1", wantAPI: "https://forgejo.example.com/api/v1"},
+		{name: "not set", url: "", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := newClient(config.Config{GiteaURL: tt.url}, http.DefaultClient, "token")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("newClient() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if c.api.String() != tt.wantAPI {
+				t.Errorf("api = %s, want %s", c.api, tt.wantAPI)
+			}
+		})
+	}
+}
+
+func TestGetMergeRequest(t *testing.T) {
+	var gotAuth string
+	c := newTestClient(t, config.Config{}, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v1/repos/infra/terraform/pulls/7":
+			gotAuth = r.Header.Get("Authorization")
+			serveFixture(t, w, "pull.json")
+		case "/api/v1/repos/infra/terraform/pulls/7/reviews":
+			serveFixture(t, w, "reviews.json")
+		default:
+			http.NotFound(w, r)
+		}
+	})
+	mr, err := c.GetMergeRequest(context.Background(), 7, "infra/terraform")
+	if err != nil {
+		t.Fatal(err)
+	}
+	pr := mr.(*PullRequest)
+	want := &PullRequest{
+		Number:       7,
+		Title:        "Increase instance count",
+		State:        "open",
+		Author:       "jamie",
+		SourceBranch: "feature",
+		SourceCommit: "3f2c9a7e1d4b6c8a0e2f4a6c8e0b2d4f6a8c0e2f",
+		TargetBranch: "main",
+		WebURL:       "https://forgejo.example.com/infra/terraform/pulls/7",
+		Mergeable:    true,
+		Approved:     true,
+	}
+	if !reflect.DeepEqual(pr, want) {
+		t.Errorf("GetMergeRequest() = %+v, want %+v", pr, want)
+	}
+	if gotAuth != "token token" {
+		t.Errorf("Authorization = %s", gotAuth)
+	}
+}
+
+func TestGetMergeRequest_Fork(t *testing.T) {
+	c := newTestClient(t, config.Config{}, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v1/repos/infra/terraform/pulls/7":
+			io.WriteString(w, `{"number":7,"head":{"ref":"main","sha":"abc123","repo_id":35},"base":{"ref":"main","repo_id":21}}`)
+		case "/api/v1/repos/infra/terraform/pulls/7/reviews":
+			io.WriteString(w, `[]`)
+		default:
+			http.NotFound(w, r)
+		}
+	})
+	mr, err := c.GetMergeRequest(context.Background(), 7, "infra/terraform")
+	if err != nil {
+		t.Fatal(err)
+	}
+	pr := mr.(*PullRequest)
+	if !pr.IsFromFork() || pr.GetHeadSHA() != "abc123" {
+		t.Errorf("IsFromFork() = %v, GetHeadSHA() = %s", pr.IsFromFork(), pr.GetHeadSHA())
+	}
+	if _, err := c.CloneMergeRequest(context.Background(), "infra/terraform", pr, t.TempDir()); !errors.Is(err, vcs.ErrForkNotSupported) {
+		t.Fatalf("CloneMergeRequest() error = %v, want %v", err, vcs.ErrForkNotSupported)
+	}
+}
+
+func TestIsApproved(t *testing.T) {
+	review := func(user, state string, dismissed bool) apiReview {
+		return apiReview{User: apiUser{Login: user}, State: state, Dismissed: dismissed}
+	}
+	tests := []struct {
+		name    string
+		reviews []apiReview
+		want    bool
+	}{
+		{name: "no reviews", want: false},
+		{name: "comments only", reviews: []apiReview{review("sam", "COMMENT", false)}, want: false},
+		{name: "approved", reviews: []apiReview{review("sam", "APPROVED", false)}, want: true},
+		{name: "changes requested", reviews: []apiReview{review("sam", "APPROVED", false), review("alex", "REQUEST_CHANGES", false)}, want: false},
+		{name: "changes requested then approved", reviews: []apiReview{review("sam", "REQUEST_CHANGES", false), review("sam", "APPROVED", false)}, want: true},
+		{name: "dismissed approval", reviews: []apiReview{review("sam", "APPROVED", true)}, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isApproved(tt.reviews); got != tt.want {
+				t.Errorf("isApproved() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCreateMergeRequestDiscussion(t *testing.T) {
+	var got map[string]string
+	c := newTestClient(t, config.Config{}, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/api/v1/repos/infra/terraform/issues/7/comments" {
+			http.NotFound(w, r)
+			return
+		}
+		json.NewDecoder(r.Body).Decode(&got)
+		w.WriteHeader(http.StatusCreated)
+		serveFixture(t, w, "comment.json")
+	})
+	disc, err := c.CreateMergeRequestDiscussion(context.Background(), 7, "infra/terraform", "Starting TFC plan")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if disc.GetDiscussionID() != "1042" {
+		t.Errorf("GetDiscussionID() = %s, want 1042", disc.GetDiscussionID())
+	}
+	if notes := disc.GetMRNotes(); len(notes) != 1 || notes[0].GetNoteID() != 1042 {
+		t.Errorf("unexpected notes %v", notes)
+	}
+	if got["body"] != "Starting TFC plan" {
+		t.Errorf("body = %s", got["body"])
+	}
+}
+
+func TestSetCommitStatus(t *testing.T) {
+	var gotPath string
+	var got map[string]string
+	c := newTestClient(t, config.Config{}, func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+		json.NewDecoder(r.Body).Decode(&got)
+		w.WriteHeader(http.StatusCreated)
+		io.WriteString(w, `{"id":5,"status":"success","context":"TFC/plan/aws"}`)
+	})
+	cs, err := c.SetCommitStatus(context.Background(), "infra/terraform", "abc123", &CommitStatusOptions{
+		Context:     "TFC/plan/aws",
+		TargetURL:   "https://app.terraform.io/app/zapier/workspaces/aws/runs/run-1",
+		Description: "succeeded.",
+		State:       StatusSuccess,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if gotPath != "/api/v1/repos/infra/terraform/statuses/abc123" {
+		t.Errorf("path = %s", gotPath)
+	}
+	if got["context"] != "TFC/plan/aws" || got["state"] != StatusSuccess {
+		t.Errorf("unexpected status %v", got)
+	}
+	if cs.Info() != "TFC/plan/aws success" {
+		t.Errorf("Info() = %s", cs.Info())
+	}
+}
+
+func TestGetRepoFile(t *testing.T) {
+	var gotRef string
+	c := newTestClient(t, config.Config{}, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/repos/infra/terraform/raw/.tfbuddy.yaml" {
+			http.NotFound(w, r)
+			return
+		}
+		gotRef = r.URL.Query().Get("ref")
+		io.WriteString(w, "workspaces:\n  - name: aws\n")
+	})
+	got, err := c.GetRepoFile(context.Background(), "infra/terraform", ".tfbuddy.yaml", "feature")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != "workspaces:\n  - name: aws\n" {
+		t.Errorf("GetRepoFile() = %q", got)
+	}
+	if gotRef != "feature" {
+		t.Errorf("ref = %s, want feature", gotRef)
+	}
+}
+
+func TestGetMergeRequestModifiedFiles(t *testing.T) {
+	c := newTestClient(t, config.Config{}, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/repos/infra/terraform/pulls/7/files" || r.URL.Query().Get("page") != "1" {
+			http.NotFound(w, r)
+			return
+		}
+		serveFixture(t, w, "files.json")
+	})
+	got, err := c.GetMergeRequestModifiedFiles(context.Background(), 7, "infra/terraform")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"aws/main.tf", "aws/modules/compute.tf", "aws/modules/instances.tf"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("GetMergeRequestModifiedFiles() = %v, want %v", got, want)
+	}
+}
+
+func TestMergeMR(t *testing.T) {
+	var got map[string]string
+	c := newTestClient(t, config.Config{}, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/api/v1/repos/infra/terraform/pulls/7/merge" {
+			http.NotFound(w, r)
+			return
+		}
+		json.NewDecoder(r.Body).Decode(&got)
+	})
+	if err := c.MergeMR(context.Background(), 7, "infra/terraform"); err != nil {
+		t.Fatal(err)
+	}
+	if got["Do"] != "merge" {
+		t.Errorf("merge style = %s, want merge", got["Do"])
+	}
+}
+
+func TestGetOldRunUrls(t *testing.T) {
+	statusNote := "### Terraform Cloud\n" + utils.URL_RUN_PREFIX + "[https://app.terraform.io/app/zapier/workspaces/aws/runs/run-old](https://app.terraform.io/app/zapier/workspaces/aws/runs/run-old)" + utils.URL_RUN_SUFFIX +
+		utils.URL_RUN_STATUS_PREFIX + "planned" + utils.URL_RUN_SUFFIX + utils.FormatTFBuddyMarker("aws", "plan")
+	comments, _ := json.Marshal([]map[string]any{
+		{"id": 10, "body": statusNote, "user": map[string]string{"login": "tfbuddy"}},
+		{"id": 11, "body": statusNote, "user": map[string]string{"login": "someone-else"}},
+		{"id": 12, "body": statusNote, "user": map[string]string{"login": "tfbuddy"}},
+	})
+
+	var deleted []string
+	c := newTestClient(t, config.Config{DeleteOldComments: true}, func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/api/v1/user":
+			io.WriteString(w, `{"login":"tfbuddy"}`)
+		case r.Method == http.MethodDelete:
+			deleted = append(deleted, strings.TrimPrefix(r.URL.Path, "/api/v1/repos/infra/terraform/"))
+			w.WriteHeader(http.StatusNoContent)
+		case r.URL.Path == "/api/v1/repos/infra/terraform/issues/7/comments":
+			w.Write(comments)
+		default:
+			http.NotFound(w, r)
+		}
+	})
+
+	got, err := c.GetOldRunUrls(context.Background(), 7, "infra/terraform", 12, "aws", "plan")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(got, "run-old") {
+		t.Errorf("expected the old run in %q", got)
+	}
+	if want := []string{"issues/comments/10"}; !reflect.DeepEqual(deleted, want) {
+		t.Errorf("deleted %v, want %v", deleted, want)
+	}
+}
@@ -0,0 +1,60 @@
+package gitea
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/rs/zerolog/log"
+	"github.com/zapier/tfbuddy/internal/config"
+	"github.com/zapier/tfbuddy/pkg/pr_hooks"
+	"github.com/zapier/tfbuddy/pkg/runstream"
+	"github.com/zapier/tfbuddy/pkg/tfc_api"
+	"go.opentelemetry.io/otel"
+)
+
+// commitStates maps run action states to Gitea commit states.
+var commitStates = map[pr_hooks.StatusState]string{
+	pr_hooks.StatusPending:   StatusPending,
+	pr_hooks.StatusFailed:    StatusFailure,
+	pr_hooks.StatusSucceeded: StatusSuccess,
+}
+
+// SetStatus reports the run action as a commit status with the context
+// `TFC/<action>/<workspace>` on the pull request's head commit.
+func (r *runReporter) SetStatus(ctx context.Context, s pr_hooks.StatusState, action string, rmd runstream.RunMetadata) {
+	ctx, span := otel.Tracer("TFC").Start(ctx, "updateStatus")
+	defer span.End()
+
+	state := commitStates[s]
+
+	status := &CommitStatusOptions{
+		Context:     fmt.Sprintf("TFC/%s/%s", action, rmd.GetWorkspace()),
+		TargetURL:   runUrlForTFRunMetadata(r.cfg, rmd),
+		Description: descriptionForState(state),
+		State:       state,
+	}
+
+	log.Debug().Str("repo", rmd.GetMRProjectNameWithNamespace()).Int("prID", rmd.GetMRInternalID()).Interface("new_status", status).Msg("updating Gitea commit status")
+	cs, err := r.client.SetCommitStatus(ctx, rmd.GetMRProjectNameWithNamespace(), rmd.GetCommitSHA(), status)
+	if err != nil {
+		log.Error().Str("repo", rmd.GetMRProjectNameWithNamespace()).Int("prID", rmd.GetMRInternalID()).Err(err).Interface("status", status).Msg("could not update status")
+		return
+	}
+	log.Debug().Str("repo", rmd.GetMRProjectNameWithNamespace()).Int("prID", rmd.GetMRInternalID()).Str("commit_status", cs.Info()).Msg("updated commit status")
+}
+
+func descriptionForState(state string) string {
+	switch state {
+	case StatusPending:
+		return "pending..."
+	case StatusFailure:
+		return "failed."
+	case StatusSuccess:
+		return "succeeded."
+	}
+	return "unknown"
+}
+
+func runUrlForTFRunMetadata(cfg config.Config, rmd runstream.RunMetadata) string {
+	return tfc_api.RunURL(cfg, rmd.GetOrganization(), rmd.GetWorkspace(), rmd.GetRunID())
+}
@@ -0,0 +1,111 @@
+package hooks
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/zapier/tfbuddy/pkg/pr_hooks"
+)
+
+// Webhook event types, sent in the X-Gitea-Event (or X-Forgejo-Event) header.
+const (
+	EventPullRequest        = "pull_request"
+	EventPullRequestComment = "pull_request_comment"
+	EventIssueComment       = "issue_comment"
+)
+
+// pullRequestActions maps the `action` of pull_request events to actions.
+var pullRequestActions = map[string]string{
+	"opened":       pr_hooks.ActionOpened,
+	"reopened":     pr_hooks.ActionOpened,
+	"synchronized": pr_hooks.ActionUpdated,
+	"closed":       pr_hooks.ActionClosed,
+}
+
+type repository struct {
+	FullName string `json:"full_name"`
+}
+
+type user struct {
+	Login string `json:"login"`
+}
+
+type pullRequestPayload struct {
+	Action      string `json:"action"`
+	PullRequest struct {
+		Number int `json:"number"`
+		Head   struct {
+			Ref string `json:"ref"`
+			Sha string `json:"sha"`
+		} `json:"head"`
+	} `json:"pull_request"`
+	Repository repository `json:"repository"`
+	Sender     user       `json:"sender"`
+}
+
+type commentPayload struct {
+	Action string `json:"action"`
+	Issue  struct {
+		Number      int             `json:"number"`
+		PullRequest json.RawMessage `json:"pull_request"`
+	} `json:"issue"`
+	Comment struct {
+		ID   int64  `json:"id"`
+		Body string `json:"body"`
+	} `json:"comment"`
+	IsPull     bool       `json:"is_pull"`
+	Repository repository `json:"repository"`
+	Sender     user       `json:"sender"`
+}
+
+// parseEvent decodes a Gitea or Forgejo webhook payload. Comments on pull
+// requests arrive as pull_request_comment events, or as issue_comment events
+// on older releases.
+func parseEvent(eventType string, body []byte) (*pr_hooks.PullRequestEvent, error) {
+	var event *pr_hooks.PullRequestEvent
+	switch eventType {
+	case EventPullRequest:
+		var p pullRequestPayload
+		if err := json.Unmarshal(body, &p); err != nil {
+			return nil, fmt.Errorf("could not decode gitea event. %w", err)
+		}
+		action, ok := pullRequestActions[p.Action]
+		if !ok {
+			return nil, pr_hooks.ErrUnhandledEvent
+		}
+		event = &pr_hooks.PullRequestEvent{
+			Action:       action,
+			Repo:         p.Repository.FullName,
+			PRID:         p.PullRequest.Number,
+			SourceBranch: p.PullRequest.Head.Ref,
+			CommitSHA:    p.PullRequest.Head.Sha,
+			Author:       p.Sender.Login,
+		}
+
+	case EventPullRequestComment, EventIssueComment:
+		var p commentPayload
+		if err := json.Unmarshal(body, &p); err != nil {
+			return nil, fmt.Errorf("could not decode gitea event. %w", err)
+		}
+		isPull := p.IsPull || (len(p.Issue.PullRequest) > 0 && string(p.Issue.PullRequest) != "null")
+		if p.Action != "created" || !isPull {
+			return nil, pr_hooks.ErrUnhandledEvent
+		}
+		event = &pr_hooks.PullRequestEvent{
+			Action:    pr_hooks.ActionCommented,
+			Repo:      p.Repository.FullName,
+			PRID:      p.Issue.Number,
+			CommentID: p.Comment.ID,
+			Comment:   p.Comment.Body,
+			Author:    p.Sender.Login,
+		}
+
+	default:
+		return nil, pr_hooks.ErrUnhandledEvent
+	}
+	event.EventType = eventType
+	if event.Repo == "" || event.PRID == 0 {
+		return nil, fmt.Errorf("gitea %s event has no pull request", eventType)
+	}
+	return event, nil
+}
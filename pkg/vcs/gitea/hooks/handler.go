@@ -0,0 +1,103 @@
+package hooks
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+
+	"github.com/nats-io/nats.go"
+	"github.com/rs/zerolog/log"
+	"github.com/zapier/tfbuddy/internal/config"
+	"github.com/zapier/tfbuddy/pkg/allow_list"
+	"github.com/zapier/tfbuddy/pkg/pr_hooks"
+	"github.com/zapier/tfbuddy/pkg/runstream"
+	"github.com/zapier/tfbuddy/pkg/tfc_api"
+	"github.com/zapier/tfbuddy/pkg/tfc_trigger"
+	"github.com/zapier/tfbuddy/pkg/vcs"
+)
+
+// Gitea headers. Forgejo sends the same headers with an X-Forgejo prefix.
+const (
+	EventTypeHeader  = "X-Gitea-Event"
+	DeliveryHeader   = "X-Gitea-Delivery"
+	SignatureHeader  = "X-Gitea-Signature"
+	forgejoEventType = "X-Forgejo-Event"
+	forgejoDelivery  = "X-Forgejo-Delivery"
+	forgejoSignature = "X-Forgejo-Signature"
+)
+
+// provider adapts Gitea and Forgejo webhooks to pull request events.
+type provider struct {
+	cfg config.Config
+}
+
+// ensure type complies with interface
+var _ pr_hooks.Provider = (*provider)(nil)
+
+func NewGiteaHooksHandler(cfg config.Config, vcs vcs.GitClient, tfc tfc_api.ApiClient, rs runstream.StreamClient, js nats.JetStreamContext, workspaceStream tfc_trigger.WorkspacePublisher) *pr_hooks.HooksHandler {
+	if cfg.GiteaHookSecretKey == "" {
+		if cfg.GiteaHookUnsigned {
+			log.Warn().Msgf("%s is not set and %s is enabled, Gitea webhooks are not verified", config.KeyGiteaHookSecretKey, config.KeyGiteaHookUnsigned)
+		} else {
+			log.Warn().Msgf("%s is not set, all Gitea webhooks are rejected", config.KeyGiteaHookSecretKey)
+		}
+	}
+	return pr_hooks.NewHooksHandler(cfg, &provider{cfg: cfg}, vcs, tfc, rs, js, workspaceStream)
+}
+
+func (p *provider) Name() string {
+	return "gitea"
+}
+func (p *provider) DisplayName() string {
+	return "Gitea"
+}
+func (p *provider) EventType(r *http.Request) string {
+	return header(r, EventTypeHeader, forgejoEventType)
+}
+func (p *provider) ParseEvent(r *http.Request, body []byte) (*pr_hooks.PullRequestEvent, string, error) {
+	event, err := parseEvent(p.EventType(r), body)
+	return event, deliveryID(r), err
+}
+
+// IsRepoAllowed matches owner/repo against the allow list.
+func (p *provider) IsRepoAllowed(cfg config.Config, repo string) bool {
+	return allow_list.IsGiteaRepoAllowed(cfg, repo)
+}
+
+// deliveryID returns the unique id Gitea sends with each delivery.
+func deliveryID(r *http.Request) string {
+	return header(r, DeliveryHeader, forgejoDelivery)
+}
+
+// header returns the Gitea header, or its Forgejo equivalent.
+func header(r *http.Request, gitea, forgejo string) string {
+	if v := r.Header.Get(gitea); v != "" {
+		return v
+	}
+	return r.Header.Get(forgejo)
+}
+
+// Verify checks the signature of the webhook. Without a secret configured
+// webhooks are only accepted when unsigned ones are explicitly allowed.
+func (p *provider) Verify(r *http.Request, body []byte) bool {
+	if p.cfg.GiteaHookSecretKey == "" {
+		return p.cfg.GiteaHookUnsigned
+	}
+	return verifySignature(p.cfg.GiteaHookSecretKey, header(r, SignatureHeader, forgejoSignature), body)
+}
+
+// verifySignature checks the hex HMAC-SHA256 signature Gitea computes over the
+// body with the webhook secret.
+func verifySignature(secret, signature string, body []byte) bool {
+	if secret == "" {
+		return false
+	}
+	got, err := hex.DecodeString(signature)
+	if err != nil {
+		return false
+	}
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return hmac.Equal(got, mac.Sum(nil))
+}
@@ -0,0 +1,146 @@
+package hooks
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/zapier/tfbuddy/internal/config"
+	"github.com/zapier/tfbuddy/pkg/pr_hooks"
+)
+
+func readFixture(t *testing.T, name string) string {
+	t.Helper()
+	b, err := os.ReadFile("testdata/" + name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(b)
+}
+
+func TestParseEvent(t *testing.T) {
+	tests := []struct {
+		name      string
+		eventType string
+		body      string
+		want      *pr_hooks.PullRequestEvent
+		wantErr   error
+	}{
+		{
+			name:      "pull request synchronized",
+			eventType: EventPullRequest,
+			body:      readFixture(t, "pull_request_synchronized.json"),
+			want:      &pr_hooks.PullRequestEvent{EventType: EventPullRequest, Action: pr_hooks.ActionUpdated, Repo: "infra/terraform", PRID: 7, SourceBranch: "feature", CommitSHA: "3f2c9a7e1d4b6c8a0e2f4a6c8e0b2d4f6a8c0e2f", Author: "jamie"},
+		},
+		{
+			name:      "pull request closed",
+			eventType: EventPullRequest,
+			body:      `{"action":"closed","pull_request":{"number":7},"repository":{"full_name":"infra/terraform"}}`,
+			want:      &pr_hooks.PullRequestEvent{EventType: EventPullRequest, Action: pr_hooks.ActionClosed, Repo: "infra/terraform", PRID: 7},
+		},
+		{
+			name:      "pull request labeled",
+			eventType: EventPullRequest,
+			body:      `{"action":"label_updated","pull_request":{"number":7},"repository":{"full_name":"infra/terraform"}}`,
+			wantErr:   pr_hooks.ErrUnhandledEvent,
+		},
+		{
+			name:      "pull request comment",
+			eventType: EventPullRequestComment,
+			body:      readFixture(t, "pull_request_comment.json"),
+			want:      &pr_hooks.PullRequestEvent{EventType: EventPullRequestComment, Action: pr_hooks.ActionCommented, Repo: "infra/terraform", PRID: 7, CommentID: 1043, Comment: "tfc apply -w aws", Author: "jamie"},
+		},
+		{
+			name:      "issue comment on pull request",
+			eventType: EventIssueComment,
+			body:      `{"action":"created","issue":{"number":7,"pull_request":{"merged":false}},"comment":{"id":5,"body":"tfc plan"},"repository":{"full_name":"infra/terraform"}}`,
+			want:      &pr_hooks.PullRequestEvent{EventType: EventIssueComment, Action: pr_hooks.ActionCommented, Repo: "infra/terraform", PRID: 7, CommentID: 5, Comment: "tfc plan"},
+		},
+		{
+			name:      "issue comment on issue",
+			eventType: EventIssueComment,
+			body:      `{"action":"created","issue":{"number":8,"pull_request":null},"comment":{"id":6,"body":"tfc plan"},"repository":{"full_name":"infra/terraform"}}`,
+			wantErr:   pr_hooks.ErrUnhandledEvent,
+		},
+		{
+			name:      "comment edited",
+			eventType: EventPullRequestComment,
+			body:      `{"action":"edited","issue":{"number":7},"comment":{"id":5,"body":"tfc plan"},"repository":{"full_name":"infra/terraform"},"is_pull":true}`,
+			wantErr:   pr_hooks.ErrUnhandledEvent,
+		},
+		{
+			name:      "push",
+			eventType: "push",
+			body:      `{}`,
+			wantErr:   pr_hooks.ErrUnhandledEvent,
+		},
+		{
+			name:      "missing pull request",
+			eventType: EventPullRequest,
+			body:      `{"action":"opened","repository":{"full_name":"infra/terraform"}}`,
+			wantErr:   errors.New("gitea pull_request event has no pull request"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseEvent(tt.eventType, []byte(tt.body))
+			if tt.wantErr != nil {
+				if err == nil || err.Error() != tt.wantErr.Error() {
+					t.Fatalf("parseEvent() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if *got != *tt.want {
+				t.Errorf("parseEvent() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestVerifySignature(t *testing.T) {
+	body := []byte(readFixture(t, "pull_request_synchronized.json"))
+	mac := hmac.New(sha256.New, []byte("secret"))
+	mac.Write(body)
+	valid := hex.EncodeToString(mac.Sum(nil))
+
+	tests := []struct {
+		name          string
+		secret        string
+		allowUnsigned bool
+		header        string
+		signature     string
+		want          bool
+	}{
+		{name: "valid", secret: "secret", signature: valid, want: true},
+		{name: "valid forgejo", secret: "secret", header: forgejoSignature, signature: valid, want: true},
+		{name: "wrong secret", secret: "other", signature: valid},
+		{name: "prefixed", secret: "secret", signature: "sha256=" + valid},
+		{name: "missing", secret: "secret"},
+		{name: "no secret configured", signature: valid},
+		{name: "unsigned allowed", allowUnsigned: true, want: true},
+		{name: "secret configured and unsigned allowed", secret: "secret", allowUnsigned: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := &provider{cfg: config.Config{GiteaHookSecretKey: tt.secret, GiteaHookUnsigned: tt.allowUnsigned}}
+			r := httptest.NewRequest(http.MethodPost, "/hooks/gitea", nil)
+			if tt.signature != "" {
+				if tt.header == "" {
+					tt.header = SignatureHeader
+				}
+				r.Header.Set(tt.header, tt.signature)
+			}
+			if got := p.Verify(r, body); got != tt.want {
+				t.Errorf("Verify() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
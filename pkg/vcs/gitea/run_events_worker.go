@@ -0,0 +1,29 @@
+package gitea
+
+import (
+	"context"
+
+	"github.com/hashicorp/go-tfe"
+	"github.com/zapier/tfbuddy/internal/config"
+	"github.com/zapier/tfbuddy/pkg/pr_hooks"
+	"github.com/zapier/tfbuddy/pkg/runstream"
+	"github.com/zapier/tfbuddy/pkg/tfc_api"
+	"github.com/zapier/tfbuddy/pkg/vcs"
+)
+
+const runEventsConsumerDurableName = "gitea"
+
+// runReporter reports runs as commit statuses and edits the pull request
+// comment of the run in place.
+type runReporter struct {
+	cfg    config.Config
+	client vcs.GitClient
+}
+
+func NewRunEventsWorker(cfg config.Config, client vcs.GitClient, rs runstream.StreamClient, tfc tfc_api.ApiClient) *pr_hooks.RunEventsWorker {
+	return pr_hooks.NewRunEventsWorker(cfg, runEventsConsumerDurableName, client, &runReporter{cfg: cfg, client: client}, rs, tfc)
+}
+
+func (r *runReporter) PostRunComment(ctx context.Context, run *tfe.Run, rmd runstream.RunMetadata, summary, details string, resolve bool) {
+	pr_hooks.UpdateRootComment(ctx, r.client, rmd, summary, details)
+}
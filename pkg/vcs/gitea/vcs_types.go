@@ -0,0 +1,163 @@
+package gitea
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/zapier/tfbuddy/pkg/vcs"
+)
+
+// ----------------------------------------------------------------------------
+// ensure type complies with interface
+var _ vcs.DetailedMR = (*PullRequest)(nil)
+var _ vcs.MRApproved = (*PullRequest)(nil)
+var _ vcs.ForkMR = (*PullRequest)(nil)
+
+// PullRequest is a Gitea or Forgejo pull request.
+type PullRequest struct {
+	Number       int
+	Title        string
+	State        string
+	Author       string
+	SourceBranch string
+	SourceCommit string
+	TargetBranch string
+	WebURL       string
+	Mergeable    bool
+	Approved     bool
+	// FromFork is set when the head branch lives in another repository.
+	FromFork bool
+}
+
+// HasConflicts reports whether Gitea considers the pull request unmergeable,
+// which is the case when its branches conflict.
+func (pr *PullRequest) HasConflicts() bool {
+	return !pr.Mergeable
+}
+func (pr *PullRequest) GetSourceBranch() string {
+	return pr.SourceBranch
+}
+func (pr *PullRequest) GetTargetBranch() string {
+	return pr.TargetBranch
+}
+func (pr *PullRequest) GetAuthor() vcs.MRAuthor {
+	return &Author{pr.Author}
+}
+func (pr *PullRequest) GetInternalID() int {
+	return pr.Number
+}
+func (pr *PullRequest) GetWebURL() string {
+	return pr.WebURL
+}
+func (pr *PullRequest) GetTitle() string {
+	return pr.Title
+}
+func (pr *PullRequest) GetState() string {
+	return pr.State
+}
+
+// IsFromFork reports whether the head branch lives in a fork of the base
+// repository.
+func (pr *PullRequest) IsFromFork() bool {
+	return pr.FromFork
+}
+func (pr *PullRequest) GetHeadSHA() string {
+	return pr.SourceCommit
+}
+
+// IsApproved reports whether the latest review of at least one reviewer
+// approves the pull request and no reviewer requests changes.
+func (pr *PullRequest) IsApproved() bool {
+	return pr.Approved
+}
+
+// ----------------------------------------------------------------------------
+// ensure type complies with interface
+var _ vcs.MRAuthor = (*Author)(nil)
+
+type Author struct {
+	Login string
+}
+
+func (a *Author) GetUsername() string {
+	return a.Login
+}
+
+// ----------------------------------------------------------------------------
+// ensure type complies with interface
+var _ vcs.MRDiscussionNotes = (*Comment)(nil)
+var _ vcs.MRNote = (*Comment)(nil)
+
+// Comment is a pull request comment. Gitea has no discussion threads on the
+// conversation tab, so a comment is its own discussion.
+type Comment struct {
+	ID        int64
+	Body      string
+	Author    string
+	CreatedAt time.Time
+}
+
+func (c *Comment) GetNoteID() int64 {
+	return c.ID
+}
+func (c *Comment) GetDiscussionID() string {
+	return fmt.Sprintf("%d", c.ID)
+}
+
+// GetMRNotes returns the comment itself: it is the root note that run status
+// updates edit in place.
+func (c *Comment) GetMRNotes() []vcs.MRNote {
+	return []vcs.MRNote{c}
+}
+
+// ----------------------------------------------------------------------------
+// Commit status states supported by Gitea.
+const (
+	StatusPending = "pending"
+	StatusSuccess = "success"
+	StatusFailure = "failure"
+)
+
+// ensure type complies with interface
+var _ vcs.CommitStatusOptions = (*CommitStatusOptions)(nil)
+
+// CommitStatusOptions describes a commit status. Statuses with the same
+// context replace each other.
+type CommitStatusOptions struct {
+	Context     string
+	TargetURL   string
+	Description string
+	State       string
+}
+
+func (o *CommitStatusOptions) GetName() string {
+	return o.Context
+}
+func (o *CommitStatusOptions) GetContext() string {
+	return o.Context
+}
+func (o *CommitStatusOptions) GetTargetURL() string {
+	return o.TargetURL
+}
+func (o *CommitStatusOptions) GetDescription() string {
+	return o.Description
+}
+func (o *CommitStatusOptions) GetState() string {
+	return o.State
+}
+func (o *CommitStatusOptions) GetPipelineID() int {
+	return 0
+}
+
+// ensure type complies with interface
+var _ vcs.CommitStatus = (*CommitStatus)(nil)
+
+type CommitStatus struct {
+	ID      int64  `json:"id"`
+	Context string `json:"context"`
+	State   string `json:"status"`
+}
+
+func (s *CommitStatus) Info() string {
+	return fmt.Sprintf("%s %s", s.Context, s.State)
+}
@@ -0,0 +1,222 @@
+package github
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bradleyfalzon/ghinstallation/v2"
+	gogithub "github.com/google/go-github/v69/github"
+	"github.com/rs/zerolog/log"
+)
+
+// ErrAppNotInstalled is returned when the GitHub App has no installation
+// covering a repository.
+var ErrAppNotInstalled = errors.New("github app is not installed on repository")
+
+// installationCacheTTL bounds how long installations found for owners and
+// repositories are reused, so that reinstalling the app or removing it from
+// a repository is noticed.
+const installationCacheTTL = 15 * time.Minute
+
+type cachedInstallation struct {
+	id        int64
+	checkedAt time.Time
+}
+
+// appAuth authenticates as a GitHub App. Requests for a repository, org or
+// user are signed with a token of the app installation on that owner;
+// ghinstallation mints the tokens on first use and refreshes them before they
+// expire.
+type appAuth struct {
+	appsTransport *ghinstallation.AppsTransport
+	// apps is authenticated with the app JWT and only used for the Apps API.
+	apps *gogithub.Client
+	now  func() time.Time
+
+	mu sync.Mutex
+	// installations and repositories are keyed by lower case owner and
+	// owner/repo.
+	installations map[string]cachedInstallation
+	repositories  map[string]cachedInstallation
+	transports    map[int64]*ghinstallation.Transport
+}
+
+// newAppAuth authenticates as the app against the API at apiURL, e.g.
+// https://api.github.com/.
+func newAppAuth(appID int64, privateKey []byte, base http.RoundTripper, apiURL *url.URL) (*appAuth, error) {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	atr, err := ghinstallation.NewAppsTransport(base, appID, privateKey)
+	if err != nil {
+		return nil, fmt.Errorf("could not parse github app private key. %w", err)
+	}
+	atr.BaseURL = strings.TrimSuffix(apiURL.String(), "/")
+	apps := gogithub.NewClient(&http.Client{Transport: atr})
+	apps.BaseURL = apiURL
+	return &appAuth{
+		appsTransport: atr,
+		apps:          apps,
+		now:           time.Now,
+		installations: map[string]cachedInstallation{},
+		repositories:  map[string]cachedInstallation{},
+		transports:    map[int64]*ghinstallation.Transport{},
+	}, nil
+}
+
+// cached returns the installation id stored under key while it is fresh.
+func (a *appAuth) cached(cache map[string]cachedInstallation, key string) (int64, bool) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	inst, ok := cache[strings.ToLower(key)]
+	if !ok || a.now().Sub(inst.checkedAt) > installationCacheTTL {
+		return 0, false
+	}
+	return inst.id, true
+}
+
+func (a *appAuth) store(cache map[string]cachedInstallation, key string, id int64) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	cache[strings.ToLower(key)] = cachedInstallation{id: id, checkedAt: a.now()}
+}
+
+// installationID finds the installation of the app on an org or user account.
+func (a *appAuth) installationID(ctx context.Context, owner string) (int64, error) {
+	if id, ok := a.cached(a.installations, owner); ok {
+		return id, nil
+	}
+
+	inst, resp, err := a.apps.Apps.FindOrganizationInstallation(ctx, owner)
+	if resp != nil && resp.StatusCode == http.StatusNotFound {
+		inst, resp, err = a.apps.Apps.FindUserInstallation(ctx, owner)
+	}
+	if resp != nil && resp.StatusCode == http.StatusNotFound {
+		return 0, fmt.Errorf("no installation found for %s. %w", owner, ErrAppNotInstalled)
+	}
+	if err != nil {
+		return 0, fmt.Errorf("could not find github app installation for %s. %w", owner, err)
+	}
+	a.store(a.installations, owner, inst.GetID())
+	return inst.GetID(), nil
+}
+
+// verifyRepository checks that the app installation covers the repository,
+// which is not implied by being installed on its owner. Repositories found
+// are remembered, missing ones are looked up again on the next event.
+func (a *appAuth) verifyRepository(ctx context.Context, owner, repo string) error {
+	fullName := owner + "/" + repo
+	if _, ok := a.cached(a.repositories, fullName); ok {
+		return nil
+	}
+	inst, resp, err := a.apps.Apps.FindRepositoryInstallation(ctx, owner, repo)
+	if resp != nil && resp.StatusCode == http.StatusNotFound {
+		return fmt.Errorf("%s. %w", fullName, ErrAppNotInstalled)
+	}
+	if err != nil {
+		return fmt.Errorf("could not find github app installation for %s. %w", fullName, err)
+	}
+	a.store(a.repositories, fullName, inst.GetID())
+	a.store(a.installations, owner, inst.GetID())
+	return nil
+}
+
+// token returns a valid installation token for the owner.
+func (a *appAuth) token(ctx context.Context, owner string) (string, error) {
+	id, err := a.installationID(ctx, owner)
+	if err != nil {
+		return "", err
+	}
+
+	a.mu.Lock()
+	tr, ok := a.transports[id]
+	if !ok {
+		log.Debug().Str("owner", owner).Int64("installation", id).Msg("authenticating as github app installation")
+		tr = ghinstallation.NewFromAppsTransport(a.appsTransport, id)
+		a.transports[id] = tr
+	}
+	a.mu.Unlock()
+
+	token, err := tr.Token(ctx)
+	if err != nil {
+		return "", fmt.Errorf("could not create github app installation token for %s. %w", owner, err)
+	}
+	return token, nil
+}
+
+// botLogin is the login GitHub shows as the author of the app's comments.
+func (a *appAuth) botLogin(ctx context.Context) (string, error) {
+	app, _, err := a.apps.Apps.Get(ctx, "")
+	if err != nil {
+		return "", fmt.Errorf("could not get github app. %w", err)
+	}
+	return app.GetSlug() + "[bot]", nil
+}
+
+// installationTransport signs requests with the installation token of the
+// owner named in the request path.
+type installationTransport struct {
+	auth *appAuth
+	// apiPath is the path prefix of the API, "/" on github.com.
+	apiPath string
+	base    http.RoundTripper
+}
+
+func (t *installationTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	owner := ownerFromAPIPath(t.apiPath, req.URL)
+	if owner == "" {
+		return tokenErrorResponse(req, http.StatusBadRequest, fmt.Errorf("github app: cannot tell which installation to use for %s", req.URL.Path)), nil
+	}
+	token, err := t.auth.token(req.Context(), owner)
+	if errors.Is(err, ErrAppNotInstalled) {
+		return tokenErrorResponse(req, http.StatusNotFound, err), nil
+	}
+	if err != nil {
+		return tokenErrorResponse(req, http.StatusBadGateway, err), nil
+	}
+	r := req.Clone(req.Context())
+	r.Header.Set("Authorization", "token "+token)
+	return t.base.RoundTrip(r)
+}
+
+// tokenErrorResponse reports a failure to pick or get an installation token
+// as an API error response, as callers rely on the response status to decide
+// whether to retry.
+func tokenErrorResponse(req *http.Request, status int, err error) *http.Response {
+	body, _ := json.Marshal(map[string]string{"message": err.Error()})
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", status, http.StatusText(status)),
+		StatusCode:    status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        http.Header{"Content-Type": []string{"application/json"}},
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}
+}
+
+// ownerFromAPIPath extracts the account a REST API request acts on, e.g.
+// "octo" for /repos/octo/repo/pulls or /orgs/octo/teams.
+func ownerFromAPIPath(apiPath string, u *url.URL) string {
+	p := strings.TrimPrefix(u.Path, strings.TrimSuffix(apiPath, "/"))
+	parts := strings.Split(strings.TrimPrefix(p, "/"), "/")
+	if len(parts) < 2 {
+		return ""
+	}
+	switch parts[0] {
+	case "repos", "orgs", "users":
+		return parts[1]
+	}
+	return ""
+}
@@ -0,0 +1,262 @@
+package github
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	gogithub "github.com/google/go-github/v69/github"
+	"github.com/zapier/tfbuddy/internal/config"
+	"github.com/zapier/tfbuddy/pkg/utils"
+)
+
+const testAppID = 1234
+
+func generateTestAppKey(t *testing.T) (*rsa.PrivateKey, []byte) {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return key, pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+}
+
+// verifyTestJWT checks the signature and issuer of an app JWT.
+func verifyTestJWT(t *testing.T, key *rsa.PublicKey, authHeader string) bool {
+	t.Helper()
+	token, ok := strings.CutPrefix(authHeader, "Bearer ")
+	if !ok {
+		return false
+	}
+	claims := &jwt.RegisteredClaims{}
+	_, err := jwt.ParseWithClaims(token, claims, func(*jwt.Token) (interface{}, error) {
+		return key, nil
+	}, jwt.WithValidMethods([]string{"RS256"}))
+	return err == nil && claims.Issuer == strconv.Itoa(testAppID)
+}
+
+type testAppServer struct {
+	*httptest.Server
+	mu           sync.Mutex
+	mintedTokens int
+	repoLookups  int
+	repoAuth     []string
+}
+
+func newTestAppServer(t *testing.T, key *rsa.PrivateKey) *testAppServer {
+	t.Helper()
+	s := &testAppServer{}
+	mux := http.NewServeMux()
+	appOnly := func(h http.HandlerFunc) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			if !verifyTestJWT(t, &key.PublicKey, r.Header.Get("Authorization")) {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			w.Header().Set("Content-Type", "application/json")
+			h(w, r)
+		}
+	}
+	mux.HandleFunc("GET /orgs/"+testOwner+"/installation", appOnly(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]interface{}{"id": 99})
+	}))
+	mux.HandleFunc("GET /orgs/{org}/installation", appOnly(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	mux.HandleFunc("GET /users/{user}/installation", appOnly(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	mux.HandleFunc("GET /repos/"+testOwner+"/"+testRepo+"/installation", appOnly(func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		s.repoLookups++
+		s.mu.Unlock()
+		json.NewEncoder(w).Encode(map[string]interface{}{"id": 99})
+	}))
+	mux.HandleFunc("GET /repos/{owner}/{repo}/installation", appOnly(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	mux.HandleFunc("GET /app", appOnly(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]interface{}{"id": testAppID, "slug": "tfbuddy"})
+	}))
+	mux.HandleFunc("POST /app/installations/99/access_tokens", appOnly(func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		s.mintedTokens++
+		n := s.mintedTokens
+		s.mu.Unlock()
+		// the first token is about to expire, so the next request mints another
+		expiresAt := time.Now().Add(time.Hour)
+		if n == 1 {
+			expiresAt = time.Now().Add(30 * time.Second)
+		}
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"token":      fmt.Sprintf("ghs_%d", n),
+			"expires_at": expiresAt.Format(time.RFC3339),
+		})
+	}))
+	mux.HandleFunc("GET /repos/"+testOwner+"/"+testRepo+"/pulls/1", func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		s.repoAuth = append(s.repoAuth, r.Header.Get("Authorization"))
+		s.mu.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{"number": 1})
+	})
+	s.Server = httptest.NewServer(mux)
+	return s
+}
+
+func newTestAppClient(t *testing.T, serverURL string, pemKey []byte, now *time.Time) *Client {
+	t.Helper()
+	apiURL, _ := url.Parse(serverURL + "/")
+	app, err := newAppAuth(testAppID, pemKey, http.DefaultTransport, apiURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	app.now = func() time.Time { return *now }
+
+	ghClient := gogithub.NewClient(&http.Client{
+		Transport: &installationTransport{auth: app, apiPath: "/", base: http.DefaultTransport},
+	})
+	ghClient.BaseURL, _ = url.Parse(serverURL + "/")
+	return &Client{client: ghClient, ctx: context.Background(), cfg: config.C, app: app}
+}
+
+func TestGithubApp_InstallationTokens(t *testing.T) {
+	key, pemKey := generateTestAppKey(t)
+	server := newTestAppServer(t, key)
+	defer server.Close()
+
+	now := time.Date(2026, 3, 31, 18, 0, 0, 0, time.UTC)
+	client := newTestAppClient(t, server.URL, pemKey, &now)
+
+	for i := 0; i < 3; i++ {
+		if _, err := client.GetPullRequest(context.Background(), testFullName, testPRID); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	server.mu.Lock()
+	defer server.mu.Unlock()
+	if server.mintedTokens != 2 {
+		t.Fatalf("expected 2 installation tokens to be minted, got %d", server.mintedTokens)
+	}
+	want := []string{"token ghs_1", "token ghs_2", "token ghs_2"}
+	if strings.Join(server.repoAuth, ",") != strings.Join(want, ",") {
+		t.Fatalf("expected requests authenticated with %v, got %v", want, server.repoAuth)
+	}
+}
+
+func TestGithubApp_UnknownOwner(t *testing.T) {
+	key, pemKey := generateTestAppKey(t)
+	server := newTestAppServer(t, key)
+	defer server.Close()
+
+	now := time.Date(2026, 3, 31, 18, 0, 0, 0, time.UTC)
+	client := newTestAppClient(t, server.URL, pemKey, &now)
+
+	_, err := client.GetPullRequest(context.Background(), "other-org/other-repo", testPRID)
+	if err == nil || !strings.Contains(err.Error(), "404 no installation found for other-org") {
+		t.Fatalf("expected a not found error, got %v", err)
+	}
+	if !errors.Is(err, utils.ErrPermanent) {
+		t.Fatalf("expected a missing installation not to be retried, got %v", err)
+	}
+}
+
+func TestGithubApp_VerifyInstallation(t *testing.T) {
+	key, pemKey := generateTestAppKey(t)
+	server := newTestAppServer(t, key)
+	defer server.Close()
+
+	now := time.Date(2026, 3, 31, 18, 0, 0, 0, time.UTC)
+	client := newTestAppClient(t, server.URL, pemKey, &now)
+
+	if err := client.VerifyInstallation(context.Background(), testFullName); err != nil {
+		t.Fatalf("expected app to be installed on %s, got %v", testFullName, err)
+	}
+	err := client.VerifyInstallation(context.Background(), testOwner+"/not-selected")
+	if !errors.Is(err, ErrAppNotInstalled) {
+		t.Fatalf("expected ErrAppNotInstalled, got %v", err)
+	}
+
+	login, err := client.botLogin(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if login != "tfbuddy[bot]" {
+		t.Fatalf("expected bot login tfbuddy[bot], got %s", login)
+	}
+
+	auth, err := client.gitAuth(context.Background(), testOwner)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if auth.Username != "x-access-token" || auth.Password != "ghs_1" {
+		t.Fatalf("expected clone auth with installation token, got %s:%s", auth.Username, auth.Password)
+	}
+}
+
+func TestGithubApp_VerifyInstallationCached(t *testing.T) {
+	key, pemKey := generateTestAppKey(t)
+	server := newTestAppServer(t, key)
+	defer server.Close()
+
+	now := time.Date(2026, 3, 31, 18, 0, 0, 0, time.UTC)
+	client := newTestAppClient(t, server.URL, pemKey, &now)
+
+	verify := func() {
+		t.Helper()
+		if err := client.VerifyInstallation(context.Background(), testFullName); err != nil {
+			t.Fatal(err)
+		}
+	}
+	verify()
+	now = now.Add(installationCacheTTL / 2)
+	verify()
+	if server.repoLookups != 1 {
+		t.Fatalf("expected the installation to be looked up once, got %d", server.repoLookups)
+	}
+
+	now = now.Add(installationCacheTTL)
+	verify()
+	if server.repoLookups != 2 {
+		t.Fatalf("expected the installation to be looked up again after %s, got %d lookups", installationCacheTTL, server.repoLookups)
+	}
+}
+
+func TestOwnerFromAPIPath(t *testing.T) {
+	tests := []struct {
+		apiPath string
+		path    string
+		want    string
+	}{
+		{apiPath: "/", path: "/repos/octo/repo/pulls/1", want: "octo"},
+		{apiPath: "/", path: "/orgs/octo/teams", want: "octo"},
+		{apiPath: "/", path: "/users/octo/installation", want: "octo"},
+		{apiPath: "/api/v3/", path: "/api/v3/repos/octo/repo", want: "octo"},
+		{apiPath: "/", path: "/user", want: ""},
+		{apiPath: "/", path: "/search/issues", want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			got := ownerFromAPIPath(tt.apiPath, &url.URL{Path: tt.path})
+			if got != tt.want {
+				t.Errorf("ownerFromAPIPath() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
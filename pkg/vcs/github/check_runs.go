@@ -0,0 +1,237 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/cenkalti/backoff/v4"
+	gogithub "github.com/google/go-github/v69/github"
+	"github.com/rs/zerolog/log"
+	"github.com/zapier/tfbuddy/pkg/utils"
+	"github.com/zapier/tfbuddy/pkg/vcs"
+	"go.opentelemetry.io/otel"
+)
+
+// Commit status states, matching the GitLab build state names so both
+// providers share the same updateCommitStatusForRun semantics.
+const (
+	CheckStatePending = "pending"
+	CheckStateRunning = "running"
+	CheckStateSuccess = "success"
+	CheckStateFailed  = "failed"
+)
+
+// SetCommitStatus creates or updates the check run named status.GetName() on
+// the commit. Check runs can only be written by GitHub Apps, so when the
+// token is refused we fall back to a classic commit status with the same
+// context, which branch protection can require just the same.
+func (c *Client) SetCommitStatus(ctx context.Context, fullName string, commitSHA string, status vcs.CommitStatusOptions) (vcs.CommitStatus, error) {
+	ctx, span := otel.Tracer("TFC").Start(ctx, "SetCommitStatus")
+	defer span.End()
+
+	parts, err := splitFullName(fullName)
+	if err != nil {
+		return nil, utils.CreatePermanentError(err)
+	}
+	owner, repo := parts[0], parts[1]
+
+	var summary string
+	if s, ok := status.(interface{ GetSummary() string }); ok {
+		summary = s.GetSummary()
+	}
+
+	forbidden := false
+	checkRun, err := backoff.RetryWithData(func() (*gogithub.CheckRun, error) {
+		existing, resp, err := c.client.Checks.ListCheckRunsForRef(ctx, owner, repo, commitSHA, &gogithub.ListCheckRunsOptions{
+			CheckName: gogithub.Ptr(status.GetName()),
+		})
+		if resp != nil && resp.StatusCode == http.StatusForbidden {
+			forbidden = true
+			return nil, backoff.Permanent(err)
+		}
+		if err != nil {
+			return nil, utils.CreatePermanentHTTPError(resp.StatusCode, err)
+		}
+
+		checkStatus, conclusion := checkRunStatusForState(status.GetState())
+		output := &gogithub.CheckRunOutput{
+			Title:   gogithub.Ptr(status.GetDescription()),
+			Summary: gogithub.Ptr(summary),
+		}
+		if existing.GetTotal() > 0 {
+			cr, resp, err := c.client.Checks.UpdateCheckRun(ctx, owner, repo, existing.CheckRuns[0].GetID(), gogithub.UpdateCheckRunOptions{
+				Name:       status.GetName(),
+				DetailsURL: gogithub.Ptr(status.GetTargetURL()),
+				Status:     gogithub.Ptr(checkStatus),
+				Conclusion: conclusion,
+				Output:     output,
+			})
+			if err != nil {
+				return nil, utils.CreatePermanentHTTPError(resp.StatusCode, err)
+			}
+			return cr, nil
+		}
+		cr, resp, err := c.client.Checks.CreateCheckRun(ctx, owner, repo, gogithub.CreateCheckRunOptions{
+			Name:       status.GetName(),
+			HeadSHA:    commitSHA,
+			DetailsURL: gogithub.Ptr(status.GetTargetURL()),
+			Status:     gogithub.Ptr(checkStatus),
+			Conclusion: conclusion,
+			Output:     output,
+		})
+		if resp != nil && resp.StatusCode == http.StatusForbidden {
+			forbidden = true
+			return nil, backoff.Permanent(err)
+		}
+		if err != nil {
+			return nil, utils.CreatePermanentHTTPError(resp.StatusCode, err)
+		}
+		return cr, nil
+	}, createBackOffWithRetries())
+	if forbidden {
+		log.Debug().Str("repo", fullName).Str("check", status.GetName()).Msg("check runs not permitted for this token, falling back to commit status")
+		return c.setRepoStatus(ctx, owner, repo, commitSHA, status)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &GithubCheckRun{checkRun}, nil
+}
+
+func (c *Client) setRepoStatus(ctx context.Context, owner, repo, commitSHA string, status vcs.CommitStatusOptions) (vcs.CommitStatus, error) {
+	return backoff.RetryWithData(func() (vcs.CommitStatus, error) {
+		rs, resp, err := c.client.Repositories.CreateStatus(ctx, owner, repo, commitSHA, &gogithub.RepoStatus{
+			Context:     gogithub.Ptr(status.GetContext()),
+			TargetURL:   gogithub.Ptr(status.GetTargetURL()),
+			Description: gogithub.Ptr(status.GetDescription()),
+			State:       gogithub.Ptr(repoStatusForState(status.GetState())),
+		})
+		if err != nil {
+			return nil, utils.CreatePermanentHTTPError(resp.StatusCode, err)
+		}
+		return &GithubRepoStatus{rs}, nil
+	}, createBackOffWithRetries())
+}
+
+// GetPipelinesForCommit returns the check suites attached to a commit, the
+// closest GitHub has to GitLab pipelines.
+func (c *Client) GetPipelinesForCommit(ctx context.Context, fullName string, commitSHA string) ([]vcs.ProjectPipeline, error) {
+	ctx, span := otel.Tracer("TFC").Start(ctx, "GetPipelinesForCommit")
+	defer span.End()
+
+	parts, err := splitFullName(fullName)
+	if err != nil {
+		return nil, utils.CreatePermanentError(err)
+	}
+	return backoff.RetryWithData(func() ([]vcs.ProjectPipeline, error) {
+		suites, resp, err := c.client.Checks.ListCheckSuitesForRef(ctx, parts[0], parts[1], commitSHA, nil)
+		if err != nil {
+			return nil, utils.CreatePermanentHTTPError(resp.StatusCode, err)
+		}
+		output := make([]vcs.ProjectPipeline, 0, len(suites.CheckSuites))
+		for _, s := range suites.CheckSuites {
+			output = append(output, &GithubCheckSuite{s})
+		}
+		return output, nil
+	}, createBackOffWithRetries())
+}
+
+// checkRunStatusForState maps a commit status state onto the Checks API
+// status and, for completed runs, conclusion.
+func checkRunStatusForState(state string) (string, *string) {
+	switch state {
+	case CheckStateRunning:
+		return "in_progress", nil
+	case CheckStateSuccess:
+		return "completed", gogithub.Ptr("success")
+	case CheckStateFailed:
+		return "completed", gogithub.Ptr("failure")
+	default:
+		return "queued", nil
+	}
+}
+
+// repoStatusForState maps a commit status state onto the classic statuses
+// API, which has no separate "running" state.
+func repoStatusForState(state string) string {
+	switch state {
+	case CheckStateSuccess:
+		return "success"
+	case CheckStateFailed:
+		return "failure"
+	default:
+		return "pending"
+	}
+}
+
+// ----------------------------------------------------------------------------
+// ensure type complies with interface
+var _ vcs.CommitStatusOptions = (*GithubCommitStatusOptions)(nil)
+
+type GithubCommitStatusOptions struct {
+	Name        string
+	TargetURL   string
+	Description string
+	State       string
+	// Summary is rendered as markdown in the check run output.
+	Summary string
+}
+
+func (o *GithubCommitStatusOptions) GetName() string {
+	return o.Name
+}
+func (o *GithubCommitStatusOptions) GetContext() string {
+	return o.Name
+}
+func (o *GithubCommitStatusOptions) GetTargetURL() string {
+	return o.TargetURL
+}
+func (o *GithubCommitStatusOptions) GetDescription() string {
+	return o.Description
+}
+func (o *GithubCommitStatusOptions) GetState() string {
+	return o.State
+}
+func (o *GithubCommitStatusOptions) GetPipelineID() int {
+	return 0
+}
+func (o *GithubCommitStatusOptions) GetSummary() string {
+	return o.Summary
+}
+
+// ensure type complies with interface
+var _ vcs.CommitStatus = (*GithubCheckRun)(nil)
+
+type GithubCheckRun struct {
+	*gogithub.CheckRun
+}
+
+func (c *GithubCheckRun) Info() string {
+	return fmt.Sprintf("%s %s %s", c.GetName(), c.GetStatus(), c.GetConclusion())
+}
+
+// ensure type complies with interface
+var _ vcs.CommitStatus = (*GithubRepoStatus)(nil)
+
+type GithubRepoStatus struct {
+	*gogithub.RepoStatus
+}
+
+func (s *GithubRepoStatus) Info() string {
+	return fmt.Sprintf("%s %s %s", s.GetContext(), s.GetState(), s.GetDescription())
+}
+
+// ensure type complies with interface
+var _ vcs.ProjectPipeline = (*GithubCheckSuite)(nil)
+
+type GithubCheckSuite struct {
+	*gogithub.CheckSuite
+}
+
+func (s *GithubCheckSuite) GetSource() string {
+	return s.GetApp().GetSlug()
+}
+func (s *GithubCheckSuite) GetID() int {
+	return int(s.CheckSuite.GetID())
+}
@@ -0,0 +1,119 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func setupChecksTestServer(t *testing.T, existingCheckRuns int, checksForbidden bool, requests *[]string, bodies *[]map[string]any) *httptest.Server {
+	t.Helper()
+	mux := http.NewServeMux()
+	record := func(r *http.Request) {
+		*requests = append(*requests, r.Method+" "+r.URL.Path)
+		if r.Body != nil && r.Method != http.MethodGet {
+			var body map[string]any
+			_ = json.NewDecoder(r.Body).Decode(&body)
+			*bodies = append(*bodies, body)
+		}
+	}
+	forbidden := func(w http.ResponseWriter) {
+		w.WriteHeader(http.StatusForbidden)
+		w.Write([]byte(`{"message":"You must authenticate via a GitHub App."}`))
+	}
+
+	mux.HandleFunc(fmt.Sprintf("/repos/%s/%s/commits/abc123/check-runs", testOwner, testRepo), func(w http.ResponseWriter, r *http.Request) {
+		record(r)
+		if checksForbidden {
+			forbidden(w)
+			return
+		}
+		runs := []map[string]any{}
+		for i := 0; i < existingCheckRuns; i++ {
+			runs = append(runs, map[string]any{"id": 42 + i, "name": r.URL.Query().Get("check_name")})
+		}
+		json.NewEncoder(w).Encode(map[string]any{"total_count": existingCheckRuns, "check_runs": runs})
+	})
+	mux.HandleFunc(fmt.Sprintf("/repos/%s/%s/check-runs", testOwner, testRepo), func(w http.ResponseWriter, r *http.Request) {
+		record(r)
+		json.NewEncoder(w).Encode(map[string]any{"id": 1, "name": "created"})
+	})
+	mux.HandleFunc(fmt.Sprintf("/repos/%s/%s/check-runs/42", testOwner, testRepo), func(w http.ResponseWriter, r *http.Request) {
+		record(r)
+		json.NewEncoder(w).Encode(map[string]any{"id": 42, "name": "updated"})
+	})
+	mux.HandleFunc(fmt.Sprintf("/repos/%s/%s/statuses/abc123", testOwner, testRepo), func(w http.ResponseWriter, r *http.Request) {
+		record(r)
+		json.NewEncoder(w).Encode(map[string]any{"id": 7, "context": "TFC/plan/ws-a"})
+	})
+	return httptest.NewServer(mux)
+}
+
+func TestGH_SetCommitStatus(t *testing.T) {
+	status := &GithubCommitStatusOptions{
+		Name:        "TFC/plan/ws-a",
+		TargetURL:   "https://app.terraform.io/app/org/workspaces/ws-a/runs/run-1",
+		Description: "succeeded.",
+		State:       CheckStateSuccess,
+		Summary:     "**Plan:** 1 to add, 0 to change, 0 to destroy.",
+	}
+	tests := []struct {
+		name            string
+		existing        int
+		forbidden       bool
+		wantLastRequest string
+		wantBody        map[string]any
+	}{
+		{
+			name:            "creates check run",
+			wantLastRequest: "POST /repos/test-org/test-repo/check-runs",
+			wantBody:        map[string]any{"name": "TFC/plan/ws-a", "head_sha": "abc123", "status": "completed", "conclusion": "success"},
+		},
+		{
+			name:            "updates existing check run",
+			existing:        1,
+			wantLastRequest: "PATCH /repos/test-org/test-repo/check-runs/42",
+			wantBody:        map[string]any{"name": "TFC/plan/ws-a", "status": "completed", "conclusion": "success"},
+		},
+		{
+			name:            "falls back to commit status without app auth",
+			forbidden:       true,
+			wantLastRequest: "POST /repos/test-org/test-repo/statuses/abc123",
+			wantBody:        map[string]any{"context": "TFC/plan/ws-a", "state": "success"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var requests []string
+			var bodies []map[string]any
+			server := setupChecksTestServer(t, tt.existing, tt.forbidden, &requests, &bodies)
+			defer server.Close()
+
+			client := newGHTestClient(t, server.URL)
+			if _, err := client.SetCommitStatus(context.Background(), testFullName, "abc123", status); err != nil {
+				t.Fatal(err)
+			}
+			if len(requests) == 0 || requests[len(requests)-1] != tt.wantLastRequest {
+				t.Fatalf("expected last request %q, got %v", tt.wantLastRequest, requests)
+			}
+			body := bodies[len(bodies)-1]
+			for k, v := range tt.wantBody {
+				if body[k] != v {
+					t.Errorf("expected %s=%v, got %v", k, v, body[k])
+				}
+			}
+			if !tt.forbidden {
+				if body["details_url"] != status.TargetURL {
+					t.Errorf("expected details_url %s, got %v", status.TargetURL, body["details_url"])
+				}
+				output, _ := body["output"].(map[string]any)
+				if output["summary"] != status.Summary {
+					t.Errorf("expected summary %q, got %v", status.Summary, output["summary"])
+				}
+			}
+		})
+	}
+}
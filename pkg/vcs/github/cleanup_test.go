@@ -238,6 +238,9 @@ func TestGH_GetOldRunUrls_DeleteDisabled_NoDeletion(t *testing.T) {
 	if !strings.Contains(result, "run-old") {
 		t.Fatalf("expected old run URL collected, got %q", result)
 	}
+	if strings.Contains(result, "run-current") {
+		t.Fatalf("expected the root comment's run to be skipped, got %q", result)
+	}
 }
 
 func TestGH_GetOldRunUrls_FullMultiWorkspaceScenario(t *testing.T) {
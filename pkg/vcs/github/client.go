@@ -3,6 +3,8 @@ package github
 import (
 	"context"
 	"fmt"
+	"net/http"
+	"net/url"
 	"os"
 	"path/filepath"
 	"strings"
@@ -25,12 +27,16 @@ import (
 
 // ensure type complies with interface
 var _ vcs.GitClient = (*Client)(nil)
+var _ vcs.Reactions = (*Client)(nil)
+var _ vcs.Maintainers = (*Client)(nil)
 
 type Client struct {
 	client *gogithub.Client
 	ctx    context.Context
 	token  string
 	cfg    config.Config
+	// app is set when authenticating as a GitHub App instead of with token.
+	app *appAuth
 }
 
 const DefaultMaxRetries = 3
@@ -42,6 +48,14 @@ func createBackOffWithRetries() backoff.BackOff {
 
 }
 func NewGithubClient(cfg config.Config) *Client {
+	if cfg.GithubAppID != 0 {
+		c, err := newGithubAppClient(cfg)
+		if err != nil {
+			log.Fatal().Err(err).Msg("could not create GitHub App client")
+		}
+		return c
+	}
+
 	token := os.Getenv("GITHUB_TOKEN")
 	ctx := context.Background()
 	ts := oauth2.StaticTokenSource(
@@ -49,14 +63,50 @@ func NewGithubClient(cfg config.Config) *Client {
 	)
 	tc := oauth2.NewClient(ctx, ts)
 
+	client, err := newRestClient(cfg, tc)
+	if err != nil {
+		log.Fatal().Err(err).Msg("could not create GitHub client")
+	}
 	return &Client{
-		client: gogithub.NewClient(tc),
+		client: client,
 		ctx:    ctx,
 		token:  token,
 		cfg:    cfg,
 	}
 }
 
+// newRestClient returns a REST API client sending requests through
+// httpClient, pointed at the GitHub Enterprise Server instance when one is
+// configured.
+func newRestClient(cfg config.Config, httpClient *http.Client) (*gogithub.Client, error) {
+	client := gogithub.NewClient(httpClient)
+	if cfg.GithubBaseURL == "" {
+		return client, nil
+	}
+	uploadURL := cfg.GithubUploadURL
+	if uploadURL == "" {
+		uploadURL = webURL(cfg).String()
+	}
+	client, err := client.WithEnterpriseURLs(cfg.GithubBaseURL, uploadURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid github enterprise url. %w", err)
+	}
+	return client, nil
+}
+
+// webURL is the root URL of the GitHub instance, which repositories are
+// cloned from.
+func webURL(cfg config.Config) *url.URL {
+	if cfg.GithubBaseURL == "" {
+		return &url.URL{Scheme: "https", Host: "github.com", Path: "/"}
+	}
+	u, err := url.Parse(cfg.GithubBaseURL)
+	if err != nil || u.Host == "" {
+		return &url.URL{Scheme: "https", Host: "github.com", Path: "/"}
+	}
+	return &url.URL{Scheme: u.Scheme, Host: u.Host, Path: "/"}
+}
+
 func (c *Client) GetMergeRequestApprovals(ctx context.Context, id int, project string) (vcs.MRApproved, error) {
 	ctx, span := otel.Tracer("TFC").Start(ctx, "GetMergeRequestApprovals")
 	defer span.End()
@@ -78,6 +128,80 @@ func (c *Client) MergeMR(ctx context.Context, mrIID int, project string) error {
 	}, createBackOffWithRetries())
 }
 
+// newGithubAppClient authenticates every request with the token of the app
+// installation on the repository owner.
+func newGithubAppClient(cfg config.Config) (*Client, error) {
+	key, err := os.ReadFile(cfg.GithubAppPrivateKeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("could not read github app private key. %w", err)
+	}
+	transport := &installationTransport{base: http.DefaultTransport}
+	client, err := newRestClient(cfg, &http.Client{Transport: transport})
+	if err != nil {
+		return nil, err
+	}
+	app, err := newAppAuth(int64(cfg.GithubAppID), key, http.DefaultTransport, client.BaseURL)
+	if err != nil {
+		return nil, err
+	}
+	transport.auth = app
+	transport.apiPath = client.BaseURL.Path
+	return &Client{
+		client: client,
+		ctx:    context.Background(),
+		cfg:    cfg,
+		app:    app,
+	}, nil
+}
+
+// VerifyInstallation returns an error wrapping ErrAppNotInstalled when
+// TFBuddy authenticates as a GitHub App that is not installed on the
+// repository. With a personal access token every repository is accepted.
+func (c *Client) VerifyInstallation(ctx context.Context, fullName string) error {
+	if c.app == nil {
+		return nil
+	}
+	parts, err := splitFullName(fullName)
+	if err != nil {
+		return err
+	}
+	return c.app.verifyRepository(ctx, parts[0], parts[1])
+}
+
+// botLogin returns the login TFBuddy's own comments are authored by.
+func (c *Client) botLogin(ctx context.Context) (string, error) {
+	if c.app != nil {
+		return c.app.botLogin(ctx)
+	}
+	currentUser, err := backoff.RetryWithData(func() (*gogithub.User, error) {
+		u, resp, err := c.client.Users.Get(ctx, "")
+		return u, utils.CreatePermanentHTTPError(resp.StatusCode, err)
+	}, createBackOffWithRetries())
+	if err != nil {
+		return "", err
+	}
+	return currentUser.GetLogin(), nil
+}
+
+// gitAuth returns the credentials used to clone and pull from the owner's
+// repositories.
+func (c *Client) gitAuth(ctx context.Context, owner string) (*githttp.BasicAuth, error) {
+	if c.app == nil {
+		return &githttp.BasicAuth{
+			Username: owner,
+			Password: c.token,
+		}, nil
+	}
+	token, err := c.app.token(ctx, owner)
+	if err != nil {
+		return nil, err
+	}
+	return &githttp.BasicAuth{
+		Username: "x-access-token",
+		Password: token,
+	}, nil
+}
+
 // GetOldRunUrls crawls PR comments authored by the bot, collects previous TFC
 // run URLs into a collapsible block, and (when TFBUDDY_DELETE_OLD_COMMENTS is
 // set) deletes old comments that belong to the same workspace+action combination.
@@ -99,10 +223,7 @@ func (c *Client) GetOldRunUrls(ctx context.Context, prID int, fullName string, r
 		return "", err
 	}
 
-	currentUser, err := backoff.RetryWithData(func() (*gogithub.User, error) {
-		u, resp, err := c.client.Users.Get(context.Background(), "")
-		return u, utils.CreatePermanentHTTPError(resp.StatusCode, err)
-	}, createBackOffWithRetries())
+	botLogin, err := c.botLogin(ctx)
 	if err != nil {
 		return "", err
 	}
@@ -111,7 +232,7 @@ func (c *Client) GetOldRunUrls(ctx context.Context, prID int, fullName string, r
 	var oldRunBlock string
 	var matchingCommentIDs []int64
 	for _, comment := range comments {
-		if comment.GetUser().GetLogin() != currentUser.GetLogin() {
+		if comment.GetUser().GetLogin() != botLogin {
 			continue
 		}
 
@@ -122,6 +243,8 @@ func (c *Client) GetOldRunUrls(ctx context.Context, prID int, fullName string, r
 		}
 
 		// Only collect run URL info from comments that match workspace+action.
+		// The root comment is edited in place with the current run, so its
+		// URL is not a previous one.
 		runUrl := utils.CaptureSubstring(body, utils.URL_RUN_PREFIX, utils.URL_RUN_SUFFIX)
 		runUrlRaw := utils.CaptureSubstring(runUrl, "[", "]")
 		runUrlSplit := strings.Split(runUrlRaw, "/")
@@ -134,7 +257,7 @@ func (c *Client) GetOldRunUrls(ctx context.Context, prID int, fullName string, r
 			runUrlRaw = runUrl
 		}
 		runStatus := utils.CaptureSubstring(body, utils.URL_RUN_STATUS_PREFIX, utils.URL_RUN_SUFFIX)
-		if runUrl != "" && runStatus != "" {
+		if runUrl != "" && runStatus != "" && comment.GetID() != int64(rootCommentID) {
 			oldRunUrls = append(oldRunUrls, fmt.Sprintf("|[%s](%s)|%s|%s|", runID, runUrlRaw, runStatus, comment.CreatedAt))
 		}
 
@@ -270,28 +393,35 @@ func (c *Client) CloneMergeRequest(ctx context.Context, project string, mr vcs.M
 		return nil, err
 	}
 
-	repo, err := backoff.RetryWithData(func() (*gogithub.Repository, error) {
-		r, resp, err := c.client.Repositories.Get(ctx, parts[0], parts[1])
-		return r, utils.CreatePermanentHTTPError(resp.StatusCode, err)
-	}, createBackOffWithRetries())
+	cloneURL := webURL(c.cfg).JoinPath(parts[0], parts[1]+".git").String()
+	log.Debug().Msg(cloneURL)
+	ref := plumbing.NewBranchReferenceName(mr.GetSourceBranch())
+	auth, err := c.gitAuth(ctx, parts[0])
 	if err != nil {
 		return nil, err
 	}
-	log.Debug().Msg(*repo.CloneURL)
-	ref := plumbing.NewBranchReferenceName(mr.GetSourceBranch())
-	auth := &githttp.BasicAuth{
-		Username: parts[0],
-		Password: c.token,
-	}
 
 	var progress sideband.Progress
 	if log.Trace().Enabled() {
 		progress = os.Stdout
 	}
 	cloneDepth := zgit.GetCloneDepth(c.cfg, GITHUB_CLONE_DEPTH_ENV)
+	if fork, ok := mr.(vcs.ForkMR); ok && fork.IsFromFork() {
+		// the head branch lives in the fork; GitHub mirrors it to the base
+		// repository as refs/pull/<number>/head.
+		gitRepo, err := zgit.CloneRef(dest, cloneURL, fmt.Sprintf("refs/pull/%d/head", mr.GetInternalID()), fork.GetHeadSHA(), &git.FetchOptions{
+			Auth:     auth,
+			Depth:    cloneDepth,
+			Progress: progress,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("could not clone MR from fork: %v", err)
+		}
+		return zgit.NewRepository(gitRepo, auth, dest), nil
+	}
 	gitRepo, err := git.PlainClone(dest, false, &git.CloneOptions{
 		Auth:          auth,
-		URL:           *repo.CloneURL,
+		URL:           cloneURL,
 		ReferenceName: ref,
 		SingleBranch:  true,
 		Depth:         cloneDepth,
@@ -324,10 +454,35 @@ func (c *Client) CloneMergeRequest(ctx context.Context, project string, mr vcs.M
 	return zgit.NewRepository(gitRepo, auth, dest), nil
 }
 
+// UpdateMergeRequestDiscussionNote edits the issue comment noteID in place.
+// GitHub has no discussion threads, so the status the comment showed before
+// is kept in a collapsed history block below the new body instead of being
+// posted as a reply.
 func (c *Client) UpdateMergeRequestDiscussionNote(ctx context.Context, mrIID, noteID int, project, discussionID, comment string) (vcs.MRNote, error) {
-	//TODO implement me
-	//panic("implement me")
-	return nil, nil
+	ctx, span := otel.Tracer("TFC").Start(ctx, "UpdateMergeRequestDiscussionNote")
+	defer span.End()
+
+	projectParts, err := splitFullName(project)
+	if err != nil {
+		return nil, utils.CreatePermanentError(err)
+	}
+	owner, repo := projectParts[0], projectParts[1]
+
+	return backoff.RetryWithData(func() (vcs.MRNote, error) {
+		existing, resp, err := c.client.Issues.GetComment(ctx, owner, repo, int64(noteID))
+		if err != nil {
+			return nil, utils.CreatePermanentHTTPError(resp.StatusCode, err)
+		}
+		body := foldStatusHistory(existing.GetBody(), existing.GetUpdatedAt().Time, comment)
+		iss, resp, err := c.client.Issues.EditComment(ctx, owner, repo, int64(noteID), &gogithub.IssueComment{
+			Body: String(body),
+		})
+		if err != nil {
+			log.Error().Err(err).Msg("github client: could not edit issue comment")
+			return nil, utils.CreatePermanentHTTPError(resp.StatusCode, err)
+		}
+		return &IssueComment{iss}, nil
+	}, createBackOffWithRetries())
 }
 
 func (c *Client) ResolveMergeRequestDiscussion(ctx context.Context, s string, i int, s2 string) error {
@@ -344,16 +499,6 @@ func (c *Client) AddMergeRequestDiscussionReply(ctx context.Context, prID int, f
 	return &IssueComment{iss}, err
 }
 
-func (c *Client) SetCommitStatus(ctx context.Context, projectWithNS string, commitSHA string, status vcs.CommitStatusOptions) (vcs.CommitStatus, error) {
-	//TODO implement me
-	return nil, nil
-}
-
-func (c *Client) GetPipelinesForCommit(ctx context.Context, projectWithNS string, commitSHA string) ([]vcs.ProjectPipeline, error) {
-	//TODO implement me
-	return nil, nil
-}
-
 func (c *Client) GetIssue(ctx context.Context, owner *gogithub.User, repo string, issueId int) (*gogithub.Issue, error) {
 	ctx, span := otel.Tracer("TFC").Start(ctx, "GetIssue")
 	defer span.End()
@@ -404,6 +549,58 @@ func (c *Client) PostIssueComment(ctx context.Context, prId int, fullName string
 	}, createBackOffWithRetries())
 }
 
+// githubReactions maps reactions to the content names of GitHub reactions.
+var githubReactions = map[vcs.Reaction]string{
+	vcs.ReactionPickedUp:   "eyes",
+	vcs.ReactionDispatched: "rocket",
+	vcs.ReactionRefused:    "-1",
+}
+
+// AddCommentReaction reacts to the issue comment commentID.
+func (c *Client) AddCommentReaction(ctx context.Context, fullName string, prID int, commentID int64, reaction vcs.Reaction) error {
+	ctx, span := otel.Tracer("TFC").Start(ctx, "AddCommentReaction")
+	defer span.End()
+
+	content, ok := githubReactions[reaction]
+	if !ok {
+		return utils.CreatePermanentError(fmt.Errorf("github client: unsupported reaction %q", reaction))
+	}
+	projectParts, err := splitFullName(fullName)
+	if err != nil {
+		return utils.CreatePermanentError(err)
+	}
+	return backoff.Retry(func() error {
+		_, resp, err := c.client.Reactions.CreateIssueCommentReaction(ctx, projectParts[0], projectParts[1], commentID, content)
+		return utils.CreatePermanentHTTPError(resp.StatusCode, err)
+	}, createBackOffWithRetries())
+}
+
+// IsMaintainer reports whether username has the maintain or admin role on
+// the repository fullName.
+func (c *Client) IsMaintainer(ctx context.Context, fullName string, username string) (bool, error) {
+	ctx, span := otel.Tracer("TFC").Start(ctx, "IsMaintainer")
+	defer span.End()
+
+	projectParts, err := splitFullName(fullName)
+	if err != nil {
+		return false, utils.CreatePermanentError(err)
+	}
+	return backoff.RetryWithData(func() (bool, error) {
+		level, resp, err := c.client.Repositories.GetPermissionLevel(ctx, projectParts[0], projectParts[1], username)
+		if resp != nil && resp.StatusCode == http.StatusNotFound {
+			return false, nil
+		}
+		if err != nil {
+			return false, utils.CreatePermanentHTTPError(resp.StatusCode, err)
+		}
+		switch level.GetRoleName() {
+		case "admin", "maintain":
+			return true, nil
+		}
+		return level.GetPermission() == "admin", nil
+	}, createBackOffWithRetries())
+}
+
 // PostPullRequestComment adds a review comment to an existing PullRequest
 func (c *Client) PostPullRequestComment(ctx context.Context, owner, repo string, prId int, body string) error {
 	ctx, span := otel.Tracer("TFC").Start(ctx, "PostPullRequestComment")
@@ -441,8 +638,13 @@ func ResolveOwnerName(owner *gogithub.User) (string, error) {
 	return *name, nil
 }
 
+// splitFullName returns the owner and name of a repository. Names qualified
+// with the GitHub host, e.g. github.example.com/owner/repo, are accepted.
 func splitFullName(fullName string) ([]string, error) {
 	parts := strings.Split(fullName, "/")
+	if len(parts) == 3 && strings.Contains(parts[0], ".") {
+		parts = parts[1:]
+	}
 	if len(parts) != 2 {
 		return nil, fmt.Errorf("github client: invalid repo format. %w", utils.ErrPermanent)
 	}
@@ -0,0 +1,183 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	gogithub "github.com/google/go-github/v69/github"
+	"github.com/zapier/tfbuddy/internal/config"
+	"github.com/zapier/tfbuddy/pkg/vcs"
+)
+
+func TestNewRestClient(t *testing.T) {
+	tests := []struct {
+		name       string
+		cfg        config.Config
+		wantBase   string
+		wantUpload string
+		wantWeb    string
+	}{
+		{
+			name:       "github.com",
+			cfg:        config.Config{},
+			wantBase:   "https://api.github.com/",
+			wantUpload: "https://uploads.github.com/",
+			wantWeb:    "https://github.com/",
+		},
+		{
+			name:       "enterprise server",
+			cfg:        config.Config{GithubBaseURL: "https://github.example.com/api/v3/"},
+			wantBase:   "https://github.example.com/api/v3/",
+			wantUpload: "https://github.example.com/api/uploads/",
+			wantWeb:    "https://github.example.com/",
+		},
+		{
+			name:       "enterprise server without api path",
+			cfg:        config.Config{GithubBaseURL: "https://github.example.com"},
+			wantBase:   "https://github.example.com/api/v3/",
+			wantUpload: "https://github.example.com/api/uploads/",
+			wantWeb:    "https://github.example.com/",
+		},
+		{
+			name: "enterprise server with separate upload host",
+			cfg: config.Config{
+				GithubBaseURL:   "https://github.example.com/api/v3/",
+				GithubUploadURL: "https://uploads.github.example.com/api/uploads/",
+			},
+			wantBase:   "https://github.example.com/api/v3/",
+			wantUpload: "https://uploads.github.example.com/api/uploads/",
+			wantWeb:    "https://github.example.com/",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, err := newRestClient(tt.cfg, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := client.BaseURL.String(); got != tt.wantBase {
+				t.Errorf("BaseURL = %s, want %s", got, tt.wantBase)
+			}
+			if got := client.UploadURL.String(); got != tt.wantUpload {
+				t.Errorf("UploadURL = %s, want %s", got, tt.wantUpload)
+			}
+			if got := webURL(tt.cfg).String(); got != tt.wantWeb {
+				t.Errorf("webURL() = %s, want %s", got, tt.wantWeb)
+			}
+		})
+	}
+}
+
+func TestSplitFullName(t *testing.T) {
+	tests := []struct {
+		fullName string
+		want     []string
+		wantErr  bool
+	}{
+		{fullName: "org/repo", want: []string{"org", "repo"}},
+		{fullName: "github.example.com/org/repo", want: []string{"org", "repo"}},
+		{fullName: "org", wantErr: true},
+		{fullName: "group/subgroup/repo", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.fullName, func(t *testing.T) {
+			got, err := splitFullName(tt.fullName)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("splitFullName() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("splitFullName() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAddCommentReaction(t *testing.T) {
+	var gotPath, gotContent string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+		var body struct {
+			Content string `json:"content"`
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+		gotContent = body.Content
+		w.WriteHeader(http.StatusCreated)
+		io.WriteString(w, `{"id":1}`)
+	}))
+	defer srv.Close()
+
+	client, err := newRestClient(config.Config{GithubBaseURL: srv.URL + "/api/v3/"}, srv.Client())
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := &Client{client: client, ctx: context.Background()}
+	if err := c.AddCommentReaction(context.Background(), "zapier/tfbuddy", 7, 42, vcs.ReactionRefused); err != nil {
+		t.Fatal(err)
+	}
+	if gotPath != "/api/v3/repos/zapier/tfbuddy/issues/comments/42/reactions" {
+		t.Errorf("path = %s", gotPath)
+	}
+	if gotContent != "-1" {
+		t.Errorf("content = %s, want -1", gotContent)
+	}
+}
+
+func TestIsMaintainer(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v3/repos/zapier/tfbuddy/collaborators/admin/permission":
+			io.WriteString(w, `{"permission":"admin","role_name":"admin"}`)
+		case "/api/v3/repos/zapier/tfbuddy/collaborators/maintainer/permission":
+			io.WriteString(w, `{"permission":"write","role_name":"maintain"}`)
+		case "/api/v3/repos/zapier/tfbuddy/collaborators/writer/permission":
+			io.WriteString(w, `{"permission":"write","role_name":"write"}`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	client, err := newRestClient(config.Config{GithubBaseURL: srv.URL + "/api/v3/"}, srv.Client())
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := &Client{client: client, ctx: context.Background()}
+	for user, want := range map[string]bool{"admin": true, "maintainer": true, "writer": false, "stranger": false} {
+		got, err := c.IsMaintainer(context.Background(), "zapier/tfbuddy", user)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != want {
+			t.Errorf("IsMaintainer(%s) = %v, want %v", user, got, want)
+		}
+	}
+}
+
+func TestGithubPRIsFromFork(t *testing.T) {
+	repo := func(id int64) *gogithub.Repository { return &gogithub.Repository{ID: &id} }
+	tests := []struct {
+		name string
+		head *gogithub.Repository
+		want bool
+	}{
+		{name: "same repository", head: repo(1), want: false},
+		{name: "fork", head: repo(2), want: true},
+		{name: "deleted fork", head: nil, want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pr := &GithubPR{&gogithub.PullRequest{
+				Head: &gogithub.PullRequestBranch{Repo: tt.head},
+				Base: &gogithub.PullRequestBranch{Repo: repo(1)},
+			}}
+			if got := pr.IsFromFork(); got != tt.want {
+				t.Errorf("IsFromFork() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -63,6 +63,11 @@ func NewGithubHooksHandler(cfg config.Config, vcs vcs.GitClient, tfc tfc_api.Api
 
 	// add Github event callbacks
 	ghEvents.OnIssueCommentCreated(h.handleIssueCommentCreatedEvent)
+	ghEvents.OnPullRequestEventOpened(h.handlePullRequestEvent)
+	ghEvents.OnPullRequestEventReopened(h.handlePullRequestEvent)
+	ghEvents.OnPullRequestEventSynchronize(h.handlePullRequestEvent)
+	ghEvents.OnPullRequestEventReadyForReview(h.handlePullRequestEvent)
+	ghEvents.OnPullRequestEventClosed(h.handlePullRequestEvent)
 	ghEvents.OnError(onError)
 	h.ghEvents = ghEvents
 
@@ -71,6 +76,10 @@ func NewGithubHooksHandler(cfg config.Config, vcs vcs.GitClient, tfc tfc_api.Api
 	if err != nil {
 		log.Error().Err(err).Msg("github worker: could not subscribe to hook stream")
 	}
+	_, err = prStream.QueueSubscribe("github_pr_event_worker", h.processPullRequestEvent)
+	if err != nil {
+		log.Error().Err(err).Msg("github worker: could not subscribe to pull request stream")
+	}
 
 	return h
 }
@@ -119,3 +128,24 @@ func (h *GithubHooksHandler) handleIssueCommentCreatedEvent(deliveryID string, e
 
 	return nil
 }
+
+func (h *GithubHooksHandler) handlePullRequestEvent(deliveryID string, eventName string, event *github.PullRequestEvent) error {
+	ctx, span := otel.Tracer("GithubHandler").Start(context.Background(), "Github - PullRequestHandler")
+	defer span.End()
+
+	lbls := prometheus.Labels{
+		"eventType":  eventName,
+		"repository": event.GetRepo().GetFullName(),
+	}
+	_, err := h.prStream.Publish(ctx, &PullRequestEventMsg{
+		Payload:    event,
+		DeliveryID: deliveryID,
+	})
+	if err != nil {
+		githubWebHookFailed.With(lbls).Inc()
+		return nil
+	}
+	githubWebHookSuccess.With(lbls).Inc()
+
+	return nil
+}
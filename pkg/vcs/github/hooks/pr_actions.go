@@ -0,0 +1,94 @@
+package hooks
+
+import (
+	"context"
+	"errors"
+
+	"github.com/rs/zerolog/log"
+	"github.com/zapier/tfbuddy/pkg/allow_list"
+	"github.com/zapier/tfbuddy/pkg/tfc_trigger"
+	"github.com/zapier/tfbuddy/pkg/utils"
+	"go.opentelemetry.io/otel"
+)
+
+func (h *GithubHooksHandler) processPullRequestEvent(msg *PullRequestEventMsg) error {
+	ctx, span := otel.Tracer("hooks").Start(msg.Context, "processPullRequestEvent")
+	defer span.End()
+
+	var prErr error
+	defer func() {
+		if r := recover(); r != nil {
+			log.Error().Msgf("Unrecoverable error in pull request event processing %v", r)
+			prErr = nil
+		}
+	}()
+	prErr = h.processPullRequest(ctx, msg)
+	return utils.EmitPermanentError(prErr, func(err error) {
+		log.Error().Msgf("got a permanent error attempting to process pull request event: %s", err.Error())
+	})
+}
+
+// processPullRequest is the GitHub counterpart of the GitLab
+// processMergeRequestEvent: new commits plan the touched workspaces and
+// closing (or merging) the PR releases the locks it holds.
+func (h *GithubHooksHandler) processPullRequest(ctx context.Context, msg *PullRequestEventMsg) error {
+	ctx, span := otel.Tracer("hooks").Start(ctx, "processPullRequest")
+	defer span.End()
+
+	if msg == nil || msg.Payload == nil || msg.Payload.PullRequest == nil {
+		return errors.New("msg is nil")
+	}
+	event := msg.Payload
+	pr := event.GetPullRequest()
+	fullName := event.GetRepo().GetFullName()
+
+	log.Debug().Str("repo", fullName).Str("action", event.GetAction()).Int("PR", pr.GetNumber()).Msg("processPullRequestEvent")
+	if !allow_list.IsGithubRepoAllowed(h.cfg, fullName) {
+		githubWebHookIgnored.WithLabelValues("pull_request", fullName, "repo-not-authorized").Inc()
+		return nil
+	}
+	installed, err := h.isAppInstalled(ctx, fullName)
+	if err != nil {
+		return err
+	}
+	if !installed {
+		githubWebHookIgnored.WithLabelValues("pull_request", fullName, "app-not-installed").Inc()
+		return nil
+	}
+
+	cfg, err := tfc_trigger.NewTFCTriggerConfig(&tfc_trigger.TFCTriggerOptions{
+		Action:                   tfc_trigger.PlanAction,
+		Branch:                   pr.GetHead().GetRef(),
+		CommitSHA:                pr.GetHead().GetSHA(),
+		ProjectNameWithNamespace: fullName,
+		MergeRequestIID:          pr.GetNumber(),
+		TriggerSource:            tfc_trigger.MergeRequestEventTrigger,
+		VcsProvider:              "github",
+		DeliveryID:               msg.DeliveryID,
+	})
+	if err != nil {
+		log.Error().Err(err).Msg("could not create TFCTriggerConfig")
+		return err
+	}
+
+	trigger := h.triggerCreation(h.cfg, h.vcs, h.tfc, h.runstream, cfg)
+	if h.workspaceStream != nil {
+		trigger.SetWorkspaceStream(h.workspaceStream)
+	}
+
+	switch event.GetAction() {
+	case "opened", "reopened", "synchronize", "ready_for_review":
+		log.Debug().Str("repo", fullName).Int("PR", pr.GetNumber()).Msg("triggering TFC events for pull request")
+		_, err := trigger.TriggerTFCEvents(ctx)
+		return err
+	case "closed":
+		// GitHub reports merges as a "closed" action with merged=true; both
+		// release the locks this PR holds.
+		log.Debug().Str("repo", fullName).Int("PR", pr.GetNumber()).Bool("merged", pr.GetMerged()).Msg("cleaning up after pull request")
+		return trigger.TriggerCleanupEvent(ctx)
+	default:
+		githubWebHookIgnored.WithLabelValues("pull_request", fullName, "unhandled-action").Inc()
+		log.Debug().Str("repo", fullName).Int("PR", pr.GetNumber()).Str("action", event.GetAction()).Msg("ignoring unknown pull request action")
+	}
+	return nil
+}
@@ -0,0 +1,101 @@
+package hooks
+
+import (
+	"context"
+	"testing"
+
+	gogithub "github.com/google/go-github/v69/github"
+	"github.com/zapier/tfbuddy/internal/config"
+	"github.com/zapier/tfbuddy/pkg/mocks"
+	"github.com/zapier/tfbuddy/pkg/runstream"
+	"github.com/zapier/tfbuddy/pkg/tfc_api"
+	"github.com/zapier/tfbuddy/pkg/tfc_trigger"
+	"github.com/zapier/tfbuddy/pkg/vcs"
+	"github.com/zapier/tfbuddy/pkg/vcs/github"
+	"go.uber.org/mock/gomock"
+)
+
+func TestProcessPullRequest(t *testing.T) {
+	tests := []struct {
+		name        string
+		action      string
+		repo        string
+		wantTrigger bool
+		wantCleanup bool
+		install     error
+	}{
+		{name: "opened", action: "opened", repo: "zapier/tfbuddy", wantTrigger: true},
+		{name: "reopened", action: "reopened", repo: "zapier/tfbuddy", wantTrigger: true},
+		{name: "synchronize", action: "synchronize", repo: "zapier/tfbuddy", wantTrigger: true},
+		{name: "ready for review", action: "ready_for_review", repo: "zapier/tfbuddy", wantTrigger: true},
+		{name: "closed", action: "closed", repo: "zapier/tfbuddy", wantCleanup: true},
+		{name: "labeled", action: "labeled", repo: "zapier/tfbuddy"},
+		{name: "repo not allowed", action: "opened", repo: "other/repo"},
+		{name: "app not installed", action: "opened", repo: "zapier/tfbuddy", install: github.ErrAppNotInstalled},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			mockTrigger := mocks.NewMockTrigger(mockCtrl)
+			if tt.wantTrigger {
+				mockTrigger.EXPECT().TriggerTFCEvents(gomock.Any()).Return(&tfc_trigger.TriggeredTFCWorkspaces{}, nil)
+			}
+			if tt.wantCleanup {
+				mockTrigger.EXPECT().TriggerCleanupEvent(gomock.Any()).Return(nil)
+			}
+
+			var gotOpts *tfc_trigger.TFCTriggerOptions
+			h := &GithubHooksHandler{
+				cfg: config.Config{GithubRepoAllowList: []string{"zapier/"}},
+				vcs: &installationCheckingClient{err: tt.install},
+				triggerCreation: func(_ config.Config, _ vcs.GitClient, _ tfc_api.ApiClient, _ runstream.StreamClient, opts *tfc_trigger.TFCTriggerOptions) tfc_trigger.Trigger {
+					gotOpts = opts
+					return mockTrigger
+				},
+			}
+
+			err := h.processPullRequest(context.Background(), &PullRequestEventMsg{
+				DeliveryID: "delivery-1",
+				Payload: &gogithub.PullRequestEvent{
+					Action: gogithub.Ptr(tt.action),
+					Repo:   &gogithub.Repository{FullName: gogithub.Ptr(tt.repo)},
+					PullRequest: &gogithub.PullRequest{
+						Number: gogithub.Ptr(7),
+						Head:   &gogithub.PullRequestBranch{Ref: gogithub.Ptr("feature"), SHA: gogithub.Ptr("abc123")},
+					},
+				},
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tt.wantTrigger || tt.wantCleanup {
+				if gotOpts == nil {
+					t.Fatal("expected a trigger to be created")
+				}
+				if gotOpts.TriggerSource != tfc_trigger.MergeRequestEventTrigger || gotOpts.VcsProvider != "github" ||
+					gotOpts.DeliveryID != "delivery-1" || gotOpts.CommitSHA != "abc123" || gotOpts.MergeRequestIID != 7 {
+					t.Errorf("unexpected trigger options %+v", gotOpts)
+				}
+			}
+		})
+	}
+}
+
+// installationCheckingClient is a GitHub client authenticated as an app.
+type installationCheckingClient struct {
+	vcs.GitClient
+	err error
+}
+
+func (c *installationCheckingClient) VerifyInstallation(ctx context.Context, fullName string) error {
+	return c.err
+}
+
+func TestPullRequestEventMsg_GetId(t *testing.T) {
+	msg := &PullRequestEventMsg{DeliveryID: "delivery-1"}
+	if got := msg.GetId(context.Background()); got != "delivery-1" {
+		t.Errorf("GetId() = %s, want delivery-1", got)
+	}
+}
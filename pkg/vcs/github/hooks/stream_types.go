@@ -30,12 +30,19 @@ const PullRequestEventType = "PullRequestEvent"
 
 type PullRequestEventMsg struct {
 	Payload *github.PullRequestEvent `json:"payload"`
-	Carrier propagation.MapCarrier   `json:"Carrier"`
-	Context context.Context
+	// DeliveryID is the X-GitHub-Delivery header. Used as the stream dedup key
+	// so redelivered hooks are dropped but distinct pushes to a PR are not.
+	DeliveryID string                 `json:"deliveryID"`
+	Carrier    propagation.MapCarrier `json:"Carrier"`
+	Context    context.Context
 }
 
 func (e *PullRequestEventMsg) GetId(ctx context.Context) string {
-	return *e.Payload.PullRequest.URL
+	if e.DeliveryID != "" {
+		return e.DeliveryID
+	}
+	pr := e.Payload.GetPullRequest()
+	return fmt.Sprintf("%s/%s/%s", pr.GetURL(), e.Payload.GetAction(), pr.GetHead().GetSHA())
 }
 
 func (e *PullRequestEventMsg) DecodeEventData(b []byte) error {
@@ -11,6 +11,7 @@ import (
 	"github.com/zapier/tfbuddy/pkg/comment_actions"
 	"github.com/zapier/tfbuddy/pkg/tfc_trigger"
 	"github.com/zapier/tfbuddy/pkg/utils"
+	"github.com/zapier/tfbuddy/pkg/vcs"
 	"github.com/zapier/tfbuddy/pkg/vcs/github"
 	"go.opentelemetry.io/otel"
 )
@@ -32,6 +33,23 @@ func (h *GithubHooksHandler) processIssueCommentEvent(msg *GithubIssueCommentEve
 	})
 }
 
+// isAppInstalled reports whether the GitHub App TFBuddy authenticates as is
+// installed on the repository. Clients using a token accept every repository.
+func (h *GithubHooksHandler) isAppInstalled(ctx context.Context, fullName string) (bool, error) {
+	v, ok := h.vcs.(interface {
+		VerifyInstallation(ctx context.Context, fullName string) error
+	})
+	if !ok {
+		return true, nil
+	}
+	err := v.VerifyInstallation(ctx, fullName)
+	if errors.Is(err, github.ErrAppNotInstalled) {
+		log.Warn().Str("repo", fullName).Msg("ignoring event, GitHub App is not installed on repository")
+		return false, nil
+	}
+	return err == nil, err
+}
+
 func (h *GithubHooksHandler) processIssueComment(ctx context.Context, msg *GithubIssueCommentEventMsg) error {
 	ctx, span := otel.Tracer("hooks").Start(ctx, "processIssueComment")
 	defer span.End()
@@ -45,6 +63,17 @@ func (h *GithubHooksHandler) processIssueComment(ctx context.Context, msg *Githu
 	log.Debug().Str("repo", *event.Repo.FullName).Msg("processIssueCommentEvent")
 	fullName := event.Repo.FullName
 	if !allow_list.IsGithubRepoAllowed(h.cfg, *fullName) {
+		if _, err := comment_actions.ParseCommentCommand(event.GetComment().GetBody()); err == nil {
+			h.react(ctx, event, vcs.ReactionRefused)
+		}
+		return nil
+	}
+	installed, err := h.isAppInstalled(ctx, *fullName)
+	if err != nil {
+		return err
+	}
+	if !installed {
+		githubWebHookIgnored.WithLabelValues("issue_comment_created", *fullName, "app-not-installed").Inc()
 		return nil
 	}
 
@@ -64,6 +93,7 @@ func (h *GithubHooksHandler) processIssueComment(ctx context.Context, msg *Githu
 		}
 		return err
 	}
+	h.react(ctx, event, vcs.ReactionPickedUp)
 
 	pr, err := h.vcs.GetMergeRequest(ctx, *event.Issue.Number, event.GetRepo().GetFullName())
 	if err != nil {
@@ -73,12 +103,13 @@ func (h *GithubHooksHandler) processIssueComment(ctx context.Context, msg *Githu
 	pullReq := pr.(*github.GithubPR)
 
 	opts.TriggerOpts.Branch = pr.GetSourceBranch()
-	opts.TriggerOpts.CommitSHA = pullReq.GetBase().GetSHA()
+	opts.TriggerOpts.CommitSHA = pullReq.GetHead().GetSHA()
 	opts.TriggerOpts.ProjectNameWithNamespace = event.GetRepo().GetFullName()
 	opts.TriggerOpts.MergeRequestIID = *event.Issue.Number
 	opts.TriggerOpts.TriggerSource = tfc_trigger.CommentTrigger
 	opts.TriggerOpts.VcsProvider = "github"
 	opts.TriggerOpts.DeliveryID = msg.DeliveryID
+	opts.TriggerOpts.Commenter = event.GetComment().GetUser().GetLogin()
 
 	cfg, err := tfc_trigger.NewTFCTriggerConfig(opts.TriggerOpts)
 	if err != nil {
@@ -98,15 +129,19 @@ func (h *GithubHooksHandler) processIssueComment(ctx context.Context, msg *Githu
 		log.Info().Msg("Got TFC apply command")
 		if !pullReq.IsApproved() {
 			h.postPullRequestComment(ctx, event, ":no_entry: Apply failed. Pull Request requires approval.")
+			h.react(ctx, event, vcs.ReactionRefused)
 			return nil
 		}
 
 		if pullReq.HasConflicts() {
 			h.postPullRequestComment(ctx, event, ":no_entry: Apply failed. Pull Request has conflicts that need to be resolved.")
+			h.react(ctx, event, vcs.ReactionRefused)
 			return nil
 		}
 	case "lock":
 		log.Info().Msg("Got TFC lock command")
+	case "override-policy":
+		log.Info().Msg("Got TFC override-policy command")
 	case "plan":
 		log.Info().Msg("Got TFC plan command")
 	case "unlock":
@@ -115,6 +150,9 @@ func (h *GithubHooksHandler) processIssueComment(ctx context.Context, msg *Githu
 		return fmt.Errorf("could not parse command")
 	}
 	executedWorkspaces, tfError := trigger.TriggerTFCEvents(ctx)
+	for _, reaction := range executedWorkspaces.Reactions(tfError) {
+		h.react(ctx, event, reaction)
+	}
 	if tfError == nil && executedWorkspaces != nil && len(executedWorkspaces.Errored) > 0 {
 		for _, failedWS := range executedWorkspaces.Errored {
 			h.postPullRequestComment(ctx, event, fmt.Sprintf(":no_entry: %s could not be run because: %s", failedWS.Name, failedWS.Error))
@@ -134,3 +172,8 @@ func (h *GithubHooksHandler) postPullRequestComment(ctx context.Context, event *
 	log.Debug().Str("repo", event.GetRepo().GetFullName()).Int("PR", prID).Msg("postPullRequestComment")
 	return h.vcs.CreateMergeRequestComment(ctx, prID, event.GetRepo().GetFullName(), body)
 }
+
+// react adds reaction to the comment that triggered event.
+func (h *GithubHooksHandler) react(ctx context.Context, event *gogithub.IssueCommentEvent, reaction vcs.Reaction) {
+	vcs.React(ctx, h.vcs, event.GetRepo().GetFullName(), event.GetIssue().GetNumber(), event.GetComment().GetID(), reaction)
+}
@@ -0,0 +1,142 @@
+package github
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/go-tfe"
+	"github.com/rs/zerolog/log"
+	"github.com/zapier/tfbuddy/internal/config"
+	"github.com/zapier/tfbuddy/pkg/runstream"
+	"github.com/zapier/tfbuddy/pkg/tfc_api"
+	"go.opentelemetry.io/otel"
+)
+
+// updateCommitStatusForRun mirrors the GitLab status updater: every run gets
+// one check per workspace and action named `TFC/<action>/<workspace>`.
+// Auto-merging is handled by postRunStatusComment, so unlike GitLab it is not
+// repeated here.
+func (w *RunEventsWorker) updateCommitStatusForRun(ctx context.Context, run *tfe.Run, rmd runstream.RunMetadata) {
+	ctx, span := otel.Tracer("TFC").Start(ctx, "updateCommitStatusForRun")
+	defer span.End()
+
+	switch run.Status {
+	// https://www.terraform.io/cloud-docs/api-docs/run#run-states
+	case tfe.RunPending:
+		// The initial status of a run once it has been created.
+		if rmd.GetAction() == runstream.PlanAction {
+			w.updateStatus(ctx, CheckStatePending, "plan", run, rmd)
+			w.updateStatus(ctx, CheckStateFailed, "apply", run, rmd)
+		} else {
+			w.updateStatus(ctx, CheckStatePending, "apply", run, rmd)
+		}
+
+	case tfe.RunApplyQueued:
+		w.updateStatus(ctx, CheckStatePending, "apply", run, rmd)
+
+	case tfe.RunApplying:
+		w.updateStatus(ctx, CheckStateRunning, "apply", run, rmd)
+
+	case tfe.RunApplied:
+		if len(run.TargetAddrs) > 0 {
+			w.updateStatus(ctx, CheckStatePending, "apply", run, rmd)
+			return
+		}
+		w.updateStatus(ctx, CheckStateSuccess, "apply", run, rmd)
+
+	case tfe.RunCanceled:
+		w.updateStatus(ctx, CheckStateFailed, rmd.GetAction(), run, rmd)
+
+	case tfe.RunDiscarded:
+		w.updateStatus(ctx, CheckStateFailed, "plan", run, rmd)
+		w.updateStatus(ctx, CheckStateFailed, "apply", run, rmd)
+
+	case tfe.RunErrored:
+		w.updateStatus(ctx, CheckStateFailed, rmd.GetAction(), run, rmd)
+
+	case tfe.RunPlanning:
+		w.updateStatus(ctx, CheckStateRunning, rmd.GetAction(), run, rmd)
+
+	case tfe.RunPlanned:
+		// this status is for Apply runs (as opposed to `RunPlannedAndFinished` below, so don't update the status.
+		return
+
+	case tfe.RunPlannedAndFinished:
+		w.updateStatus(ctx, CheckStateSuccess, rmd.GetAction(), run, rmd)
+		if run.HasChanges {
+			w.updateStatus(ctx, CheckStatePending, "apply", run, rmd)
+		}
+
+	case tfe.RunPolicySoftFailed:
+		if w.cfg.FailCIOnSentinelSoftFail && rmd.GetAction() == runstream.PlanAction {
+			w.updateStatus(ctx, CheckStateFailed, "plan", run, rmd)
+		} else {
+			w.updateStatus(ctx, CheckStateSuccess, rmd.GetAction(), run, rmd)
+		}
+
+	case tfe.RunPolicyChecked:
+		// no op
+
+	default:
+		log.Debug().Str("repo", rmd.GetMRProjectNameWithNamespace()).Int("prID", rmd.GetMRInternalID()).Str("status", string(run.Status)).Msg("ignoring run status")
+	}
+}
+
+func (w *RunEventsWorker) updateStatus(ctx context.Context, state string, action string, run *tfe.Run, rmd runstream.RunMetadata) {
+	ctx, span := otel.Tracer("TFC").Start(ctx, "updateStatus")
+	defer span.End()
+
+	status := &GithubCommitStatusOptions{
+		Name:        statusName(rmd.GetWorkspace(), action),
+		TargetURL:   runUrlForTFRunMetadata(w.cfg, rmd),
+		Description: descriptionForState(state),
+		State:       state,
+		Summary:     summaryForRun(run),
+	}
+
+	log.Debug().Str("repo", rmd.GetMRProjectNameWithNamespace()).Int("prID", rmd.GetMRInternalID()).Interface("new_status", status).Msg("updating GitHub commit status")
+	cs, err := w.client.SetCommitStatus(ctx, rmd.GetMRProjectNameWithNamespace(), rmd.GetCommitSHA(), status)
+	if err != nil {
+		log.Error().Str("repo", rmd.GetMRProjectNameWithNamespace()).Int("prID", rmd.GetMRInternalID()).Err(err).Interface("status", status).Msg("could not update status")
+		return
+	}
+	log.Debug().Str("repo", rmd.GetMRProjectNameWithNamespace()).Int("prID", rmd.GetMRInternalID()).Str("commit_status", cs.Info()).Msg("updated Commit Status")
+}
+
+func statusName(ws, action string) string {
+	return fmt.Sprintf("TFC/%v/%s", action, ws)
+}
+
+func descriptionForState(state string) string {
+	switch state {
+	case CheckStatePending:
+		return "pending..."
+	case CheckStateRunning:
+		return "in progress..."
+	case CheckStateFailed:
+		return "failed."
+	case CheckStateSuccess:
+		return "succeeded."
+	}
+	return "unknown"
+}
+
+// summaryForRun describes the run, and its plan once finished, in the check output.
+func summaryForRun(run *tfe.Run) string {
+	summary := fmt.Sprintf("Terraform Cloud run `%s` is `%s`.", run.ID, run.Status)
+	if run.Plan == nil || run.Plan.Status != tfe.PlanFinished {
+		return summary
+	}
+	if !run.Plan.HasChanges {
+		return summary + "\n\nNo changes. Your infrastructure matches the configuration."
+	}
+	return summary + fmt.Sprintf("\n\n**Plan:** %d to add, %d to change, %d to destroy.",
+		run.Plan.ResourceAdditions,
+		run.Plan.ResourceChanges,
+		run.Plan.ResourceDestructions,
+	)
+}
+
+func runUrlForTFRunMetadata(cfg config.Config, rmd runstream.RunMetadata) string {
+	return tfc_api.RunURL(cfg, rmd.GetOrganization(), rmd.GetWorkspace(), rmd.GetRunID())
+}
@@ -62,7 +62,7 @@ func (w *RunEventsWorker) eventStreamCallback(re runstream.RunEvent) bool {
 	run.Status = tfe.RunStatus(re.GetNewStatus())
 
 	w.postRunStatusComment(ctx, run, re.GetMetadata())
-	//w.updateCommitStatusForRun(run, re.GetMetadata())
+	w.updateCommitStatusForRun(ctx, run, re.GetMetadata())
 	return true
 }
 
@@ -89,12 +89,7 @@ func (w *RunEventsWorker) postRunStatusComment(ctx context.Context, run *tfe.Run
 		if commentBody != "" {
 			body += fmt.Sprintf("\n%s", commentBody)
 		}
-		w.client.CreateMergeRequestComment(
-			ctx,
-			rmd.GetMRInternalID(),
-			rmd.GetMRProjectNameWithNamespace(),
-			body,
-		)
+		w.updateRunStatusComment(ctx, rmd, body)
 	}
 	if run.Status == tfe.RunApplied {
 		if len(run.TargetAddrs) > 0 {
@@ -112,6 +107,30 @@ func (w *RunEventsWorker) postRunStatusComment(ctx context.Context, run *tfe.Run
 		}
 	}
 }
+
+// updateRunStatusComment edits the comment created when the run was
+// triggered so each workspace keeps a single comment on the PR. Runs without
+// a root comment, or whose comment can no longer be edited, get a new one.
+func (w *RunEventsWorker) updateRunStatusComment(ctx context.Context, rmd runstream.RunMetadata, body string) {
+	if rmd.GetRootNoteID() != 0 {
+		_, err := w.client.UpdateMergeRequestDiscussionNote(
+			ctx,
+			rmd.GetMRInternalID(),
+			int(rmd.GetRootNoteID()),
+			rmd.GetMRProjectNameWithNamespace(),
+			rmd.GetDiscussionID(),
+			body,
+		)
+		if err == nil {
+			return
+		}
+		log.Error().Str("project", rmd.GetMRProjectNameWithNamespace()).Int("prID", rmd.GetMRInternalID()).Int64("commentID", rmd.GetRootNoteID()).Err(err).Msg("could not update PR comment, posting a new one")
+	}
+	if err := w.client.CreateMergeRequestComment(ctx, rmd.GetMRInternalID(), rmd.GetMRProjectNameWithNamespace(), body); err != nil {
+		log.Error().Str("project", rmd.GetMRProjectNameWithNamespace()).Int("prID", rmd.GetMRInternalID()).Err(err).Msg("could not post PR comment")
+	}
+}
+
 func (w *RunEventsWorker) mergePRIfPossible(ctx context.Context, rmd runstream.RunMetadata) {
 	if !rmd.GetAutoMerge() {
 		return
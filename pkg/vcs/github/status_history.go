@@ -0,0 +1,31 @@
+package github
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/zapier/tfbuddy/pkg/utils"
+)
+
+const (
+	STATUS_HISTORY_PREFIX = "<details><summary>Status history</summary>\n\n"
+	STATUS_HISTORY_SUFFIX = "\n</details>"
+)
+
+// foldStatusHistory builds the new body of an edited status comment. The run
+// status shown by the previous body, and when it was set, is appended to the
+// collapsed history carried over from earlier edits.
+func foldStatusHistory(previous string, previousAt time.Time, next string) string {
+	var entries []string
+	if history := utils.CaptureSubstring(previous, STATUS_HISTORY_PREFIX, STATUS_HISTORY_SUFFIX); history != "" {
+		entries = strings.Split(history, "\n")
+	}
+	if status := strings.Trim(utils.CaptureSubstring(previous, utils.URL_RUN_STATUS_PREFIX, utils.URL_RUN_SUFFIX), "`"); status != "" {
+		entries = append(entries, fmt.Sprintf("* `%s` at %s", status, previousAt.UTC().Format(time.RFC3339)))
+	}
+	if len(entries) == 0 {
+		return next
+	}
+	return fmt.Sprintf("%s\n\n%s%s%s", next, STATUS_HISTORY_PREFIX, strings.Join(entries, "\n"), STATUS_HISTORY_SUFFIX)
+}
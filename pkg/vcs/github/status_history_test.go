@@ -0,0 +1,92 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFoldStatusHistory(t *testing.T) {
+	at := time.Date(2026, 3, 31, 18, 0, 0, 0, time.UTC)
+	planning := buildGHCommentBody("ws-a", "plan", "run-1", "https://tfc/run-1", "planning")
+	finished := buildGHCommentBody("ws-a", "plan", "run-1", "https://tfc/run-1", "planned_and_finished")
+
+	tests := []struct {
+		name     string
+		previous string
+		want     string
+	}{
+		{
+			name:     "initial comment has no status",
+			previous: "Starting TFC plan for Workspace: `foo-corp/ws-a`.\n",
+			want:     finished,
+		},
+		{
+			name:     "previous status is folded",
+			previous: planning,
+			want:     finished + "\n\n" + STATUS_HISTORY_PREFIX + "* `planning` at 2026-03-31T18:00:00Z" + STATUS_HISTORY_SUFFIX,
+		},
+		{
+			name:     "existing history is kept",
+			previous: planning + "\n\n" + STATUS_HISTORY_PREFIX + "* `pending` at 2026-03-31T17:59:00Z" + STATUS_HISTORY_SUFFIX,
+			want: finished + "\n\n" + STATUS_HISTORY_PREFIX +
+				"* `pending` at 2026-03-31T17:59:00Z\n* `planning` at 2026-03-31T18:00:00Z" + STATUS_HISTORY_SUFFIX,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := foldStatusHistory(tt.previous, at, finished)
+			if got != tt.want {
+				t.Errorf("foldStatusHistory() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGH_UpdateMergeRequestDiscussionNote_EditsInPlace(t *testing.T) {
+	const commentID = 42
+	var edited string
+
+	mux := http.NewServeMux()
+	mux.HandleFunc(fmt.Sprintf("/repos/%s/%s/issues/comments/%d", testOwner, testRepo, commentID), func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.Method {
+		case http.MethodGet:
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"id":         commentID,
+				"body":       buildGHCommentBody("ws-a", "plan", "run-1", "https://tfc/run-1", "planning"),
+				"updated_at": "2026-03-31T18:00:00Z",
+			})
+		case http.MethodPatch:
+			var req map[string]string
+			json.NewDecoder(r.Body).Decode(&req)
+			edited = req["body"]
+			json.NewEncoder(w).Encode(map[string]interface{}{"id": commentID, "body": edited})
+		default:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	client := newGHTestClient(t, server.URL)
+	note, err := client.UpdateMergeRequestDiscussionNote(context.Background(), testPRID, commentID, testFullName, "42",
+		buildGHCommentBody("ws-a", "plan", "run-1", "https://tfc/run-1", "planned_and_finished"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if note.GetNoteID() != commentID {
+		t.Fatalf("expected note %d, got %d", commentID, note.GetNoteID())
+	}
+	if !strings.Contains(edited, "**Status**: `planned_and_finished`") {
+		t.Fatalf("expected edited comment to show the new status, got %q", edited)
+	}
+	if !strings.Contains(edited, "* `planning` at 2026-03-31T18:00:00Z") {
+		t.Fatalf("expected previous status in history, got %q", edited)
+	}
+}
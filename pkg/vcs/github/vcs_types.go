@@ -19,14 +19,18 @@ func (c *GithubPRIssueComment) GetDiscussionID() string {
 	return fmt.Sprintf("%d", *c.ID)
 }
 
+// GetMRNotes returns the comment itself: it is the root note that run status
+// updates edit in place.
 func (c *GithubPRIssueComment) GetMRNotes() []vcs.MRNote {
-	return nil
+	return []vcs.MRNote{&IssueComment{c.IssueComment}}
 }
 
 // ----------------------------------------------------------------------------
 
 // ensure type complies with interface
 var _ vcs.MR = (*GithubPR)(nil)
+var _ vcs.ForkMR = (*GithubPR)(nil)
+var _ vcs.DraftMR = (*GithubPR)(nil)
 
 type GithubPR struct {
 	*gogithub.PullRequest
@@ -57,6 +61,18 @@ func (gm *GithubPR) GetTargetBranch() string {
 func (gm *GithubPR) GetState() string {
 	return gm.PullRequest.GetState()
 }
+func (gm *GithubPR) IsDraft() bool {
+	return gm.PullRequest.GetDraft()
+}
+
+// IsFromFork reports whether the head branch lives in another repository than
+// the base branch. A pull request whose fork was deleted has no head repository.
+func (gm *GithubPR) IsFromFork() bool {
+	return gm.PullRequest.GetHead().GetRepo().GetID() != gm.PullRequest.GetBase().GetRepo().GetID()
+}
+func (gm *GithubPR) GetHeadSHA() string {
+	return gm.PullRequest.GetHead().GetSHA()
+}
 func (gm *GithubPR) IsApproved() bool {
 	return *gm.MergeableState != "blocked"
 }
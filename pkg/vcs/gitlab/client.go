@@ -4,6 +4,8 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"net/http"
+	"net/url"
 	"os"
 	"strings"
 	"time"
@@ -25,6 +27,9 @@ type GitlabClient struct {
 	token     string
 	tokenUser string
 	cfg       config.Config
+	// webURL is the root URL of the instance, which projects are cloned from.
+	webURL *url.URL
+	tls    tlsSettings
 }
 
 const DefaultMaxRetries = 3
@@ -52,13 +57,42 @@ func NewGitlabClient(cfg config.Config) *GitlabClient {
 		}
 	}
 
-	var err error
-	glClient, err := gogitlab.NewClient(token)
+	c, err := newGitlabClient(cfg, cfg.GitlabBaseURL, token, tokenUser)
 	if err != nil {
 		log.Fatal().Msgf("Failed to create client: %v", err)
 	}
+	return c
+}
+
+// newGitlabClient creates a client for the instance at baseURL, gitlab.com
+// when empty.
+func newGitlabClient(cfg config.Config, baseURL, token, tokenUser string) (*GitlabClient, error) {
+	tls, err := loadTLSSettings(cfg)
+	if err != nil {
+		return nil, err
+	}
+	web, err := instanceWebURL(baseURL)
+	if err != nil {
+		return nil, err
+	}
+
+	opts := []gogitlab.ClientOptionFunc{gogitlab.WithBaseURL(web.String())}
+	if httpClient := tls.httpClient(); httpClient != nil {
+		opts = append(opts, gogitlab.WithHTTPClient(httpClient))
+	}
+	glClient, err := gogitlab.NewClient(token, opts...)
+	if err != nil {
+		return nil, err
+	}
 
-	return &GitlabClient{client: glClient, token: token, tokenUser: tokenUser, cfg: cfg}
+	return &GitlabClient{
+		client:    glClient,
+		token:     token,
+		tokenUser: tokenUser,
+		cfg:       cfg,
+		webURL:    web,
+		tls:       tls,
+	}, nil
 }
 func (c *GitlabClient) ResolveMergeRequestDiscussion(ctx context.Context, projectWithNamespace string, mrIID int, discussionID string) error {
 	_, span := otel.Tracer("TFC").Start(ctx, "ResolveMergeRequestDiscussion")
@@ -341,6 +375,43 @@ func (c *GitlabClient) AddMergeRequestDiscussionReply(ctx context.Context, mrIID
 	return nil, utils.CreatePermanentError(errors.New("comment is empty"))
 }
 
+// AddCommentReaction awards an emoji to the merge request note noteID.
+func (c *GitlabClient) AddCommentReaction(ctx context.Context, project string, mrIID int, noteID int64, reaction vcs.Reaction) error {
+	_, span := otel.Tracer("TFC").Start(ctx, "AddCommentReaction")
+	defer span.End()
+
+	return backoff.Retry(func() error {
+		log.Debug().Str("project", project).Int("mrIID", mrIID).Int64("noteID", noteID).Str("reaction", string(reaction)).Msg("awarding Gitlab emoji")
+		_, resp, err := c.client.AwardEmoji.CreateMergeRequestAwardEmojiOnNote(project, mrIID, int(noteID), &gogitlab.CreateAwardEmojiOptions{Name: string(reaction)})
+		return utils.CreatePermanentHTTPError(resp.StatusCode, err)
+	}, createBackOffWithRetries())
+}
+
+// IsMaintainer reports whether username has at least the Maintainer role on
+// project, directly or through a parent group.
+func (c *GitlabClient) IsMaintainer(ctx context.Context, project string, username string) (bool, error) {
+	_, span := otel.Tracer("TFC").Start(ctx, "IsMaintainer")
+	defer span.End()
+
+	return backoff.RetryWithData(func() (bool, error) {
+		users, resp, err := c.client.Users.ListUsers(&gogitlab.ListUsersOptions{Username: &username})
+		if err != nil {
+			return false, utils.CreatePermanentHTTPError(resp.StatusCode, err)
+		}
+		if len(users) == 0 {
+			return false, nil
+		}
+		member, resp, err := c.client.ProjectMembers.GetInheritedProjectMember(project, users[0].ID)
+		if resp != nil && resp.StatusCode == http.StatusNotFound {
+			return false, nil
+		}
+		if err != nil {
+			return false, utils.CreatePermanentHTTPError(resp.StatusCode, err)
+		}
+		return member.AccessLevel >= gogitlab.MaintainerPermissions, nil
+	}, createBackOffWithRetries())
+}
+
 // ResolveMergeRequestDiscussionReply marks a discussion thread as resolved /  unresolved.
 func (c *GitlabClient) ResolveMergeRequestDiscussionReply(ctx context.Context, mrIID int, project, discussionID string, resolved bool) error {
 	_, span := otel.Tracer("TFC").Start(ctx, "ResolveMergeRequestDiscussionReply")
@@ -411,6 +482,9 @@ func (g *GitlabClient) GetMergeRequestModifiedFiles(ctx context.Context, mrIID i
 	}, createBackOffWithRetries())
 }
 
+var _ vcs.ForkMR = (*GitlabMR)(nil)
+var _ vcs.DraftMR = (*GitlabMR)(nil)
+
 type GitlabMR struct {
 	*gogitlab.MergeRequest
 }
@@ -440,6 +514,20 @@ func (gm *GitlabMR) GetState() string {
 	return gm.MergeRequest.State
 }
 
+// IsDraft reports whether the MR is a draft. Older Gitlab versions only set
+// the deprecated work in progress flag.
+func (gm *GitlabMR) IsDraft() bool {
+	return gm.MergeRequest.Draft || gm.MergeRequest.WorkInProgress
+}
+
+// IsFromFork reports whether the source branch lives in a fork of the target project.
+func (gm *GitlabMR) IsFromFork() bool {
+	return gm.MergeRequest.SourceProjectID != gm.MergeRequest.TargetProjectID
+}
+func (gm *GitlabMR) GetHeadSHA() string {
+	return gm.MergeRequest.SHA
+}
+
 type GitlabMRAuthor struct {
 	*gogitlab.BasicUser
 }
@@ -555,6 +643,17 @@ func (gE *GitlabMergeCommentEvent) GetType() string {
 func (gE *GitlabMergeCommentEvent) GetDiscussionID() string {
 	return gE.ObjectAttributes.DiscussionID
 }
+func (gE *GitlabMergeCommentEvent) GetNoteID() int64 {
+	return int64(gE.ObjectAttributes.ID)
+}
+
+// GetCommenter returns the username of the author of the note.
+func (gE *GitlabMergeCommentEvent) GetCommenter() string {
+	if gE.User == nil {
+		return ""
+	}
+	return gE.User.Username
+}
 func (gE *GitlabMergeCommentEvent) GetSHA() string {
 	return gE.MergeRequest.LastCommit.ID
 }
@@ -2,6 +2,7 @@ package gitlab
 
 import (
 	"context"
+	"fmt"
 	"os"
 	"path/filepath"
 
@@ -12,7 +13,6 @@ import (
 	"github.com/rs/zerolog/log"
 	zgit "github.com/zapier/tfbuddy/pkg/git"
 	"github.com/zapier/tfbuddy/pkg/vcs"
-	"gitlab.com/gitlab-org/api/client-go"
 	"go.opentelemetry.io/otel"
 	"gopkg.in/errgo.v2/fmt/errors"
 )
@@ -20,21 +20,11 @@ import (
 const GITLAB_CLONE_DEPTH_ENV = "TFBUDDY_GITLAB_CLONE_DEPTH"
 
 // CloneMergeRequest performs a git clone of the target Gitlab project & merge request branch to the `dest` path.
+// Merge requests from forks are cloned at their head commit instead.
 func (c *GitlabClient) CloneMergeRequest(ctx context.Context, project string, mr vcs.MR, dest string) (vcs.GitRepo, error) {
 	_, span := otel.Tracer("TFC").Start(ctx, "CloneMergeRequest")
 	defer span.End()
 
-	proj, _, err := c.client.Projects.GetProject(project, &gitlab.GetProjectOptions{
-		License:              ptr(false),
-		Statistics:           ptr(false),
-		WithCustomAttributes: ptr(false),
-	})
-	if err != nil {
-		err = errors.Newf("could not clone MR - unable to read project details from Gitlab API: %v", err)
-		span.RecordError(err)
-		return nil, err
-	}
-
 	ref := plumbing.NewBranchReferenceName(mr.GetSourceBranch())
 	auth := &githttp.BasicAuth{
 		Username: c.tokenUser,
@@ -46,14 +36,35 @@ func (c *GitlabClient) CloneMergeRequest(ctx context.Context, project string, mr
 		progress = os.Stdout
 	}
 	cloneDepth := zgit.GetCloneDepth(c.cfg, GITLAB_CLONE_DEPTH_ENV)
+	cloneURL := c.webURL.JoinPath(project + ".git").String()
+
+	if fork, ok := mr.(vcs.ForkMR); ok && fork.IsFromFork() {
+		// the source branch lives in the fork; Gitlab keeps its head in the
+		// target project as refs/merge-requests/<iid>/head.
+		repo, err := zgit.CloneRef(dest, cloneURL, fmt.Sprintf("refs/merge-requests/%d/head", mr.GetInternalID()), fork.GetHeadSHA(), &git.FetchOptions{
+			Auth:            auth,
+			Depth:           cloneDepth,
+			Progress:        progress,
+			CABundle:        c.tls.caBundle,
+			InsecureSkipTLS: c.tls.insecureSkipVerify,
+		})
+		if err != nil {
+			err = errors.Newf("could not clone MR from fork: %v", err)
+			span.RecordError(err)
+			return nil, err
+		}
+		return zgit.NewRepository(repo, auth, dest).WithTLS(c.tls.caBundle, c.tls.insecureSkipVerify), nil
+	}
 
 	repo, err := git.PlainClone(dest, false, &git.CloneOptions{
-		Auth:          auth,
-		URL:           proj.HTTPURLToRepo,
-		ReferenceName: ref,
-		SingleBranch:  true,
-		Depth:         cloneDepth,
-		Progress:      progress,
+		Auth:            auth,
+		URL:             cloneURL,
+		ReferenceName:   ref,
+		SingleBranch:    true,
+		Depth:           cloneDepth,
+		Progress:        progress,
+		CABundle:        c.tls.caBundle,
+		InsecureSkipTLS: c.tls.insecureSkipVerify,
 	})
 
 	if err != nil && err != git.ErrRepositoryAlreadyExists {
@@ -70,8 +81,10 @@ func (c *GitlabClient) CloneMergeRequest(ctx context.Context, project string, mr
 		Depth: cloneDepth,
 		Auth:  auth,
 		//RecurseSubmodules: 0,
-		Progress: progress,
-		Force:    false,
+		Progress:        progress,
+		Force:           false,
+		CABundle:        c.tls.caBundle,
+		InsecureSkipTLS: c.tls.insecureSkipVerify,
 	})
 	if err != nil && err != git.NoErrAlreadyUpToDate {
 		err = errors.Newf("could not pull MR: %v", err)
@@ -85,6 +98,6 @@ func (c *GitlabClient) CloneMergeRequest(ctx context.Context, project string, mr
 		//nolint
 		filepath.WalkDir(dest, zgit.WalkRepo)
 	}
-	return zgit.NewRepository(repo, auth, dest), nil
+	return zgit.NewRepository(repo, auth, dest).WithTLS(c.tls.caBundle, c.tls.insecureSkipVerify), nil
 
 }
@@ -0,0 +1,293 @@
+package gitlab
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+	"github.com/zapier/tfbuddy/internal/config"
+	"github.com/zapier/tfbuddy/pkg/utils"
+	"github.com/zapier/tfbuddy/pkg/vcs"
+)
+
+const defaultGitlabURL = "https://gitlab.com/"
+
+// tlsSettings are applied to both API requests and git clones.
+type tlsSettings struct {
+	caBundle           []byte
+	insecureSkipVerify bool
+}
+
+func loadTLSSettings(cfg config.Config) (tlsSettings, error) {
+	s := tlsSettings{insecureSkipVerify: cfg.GitlabInsecureSkipVerify}
+	if cfg.GitlabCAFile != "" {
+		b, err := os.ReadFile(cfg.GitlabCAFile)
+		if err != nil {
+			return s, fmt.Errorf("could not read gitlab CA file. %w", err)
+		}
+		s.caBundle = b
+	}
+	return s, nil
+}
+
+// httpClient returns an HTTP client trusting the extra CAs, or nil when the
+// defaults apply.
+func (s tlsSettings) httpClient() *http.Client {
+	if s.caBundle == nil && !s.insecureSkipVerify {
+		return nil
+	}
+	pool, err := x509.SystemCertPool()
+	if err != nil {
+		pool = x509.NewCertPool()
+	}
+	if s.caBundle != nil && !pool.AppendCertsFromPEM(s.caBundle) {
+		log.Warn().Msg("no certificates found in gitlab CA file")
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = &tls.Config{
+		RootCAs: pool,
+		//nolint:gosec // opt-in for test instances
+		InsecureSkipVerify: s.insecureSkipVerify,
+	}
+	return &http.Client{Transport: transport}
+}
+
+// instanceWebURL returns the root URL of a GitLab instance, accepting both
+// the web URL and the API URL (ending in /api/v4).
+func instanceWebURL(baseURL string) (*url.URL, error) {
+	if baseURL == "" {
+		baseURL = defaultGitlabURL
+	}
+	u, err := url.Parse(baseURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid gitlab url %q. %w", baseURL, err)
+	}
+	if u.Scheme == "" || u.Host == "" {
+		return nil, fmt.Errorf("invalid gitlab url %q, expected e.g. https://gitlab.example.com", baseURL)
+	}
+	p := strings.TrimSuffix(strings.TrimSuffix(u.Path, "/"), "/api/v4")
+	return &url.URL{Scheme: u.Scheme, Host: u.Host, Path: p + "/"}, nil
+}
+
+// QualifiedProjectPath names a project so it can be routed to the GitLab
+// instance it lives on. Projects on the default instance keep their path,
+// projects on other instances are prefixed with the host, e.g.
+// gitlab.example.com/group/project.
+func QualifiedProjectPath(cfg config.Config, projectWebURL, pathWithNamespace string) string {
+	u, err := url.Parse(projectWebURL)
+	if err != nil || u.Host == "" {
+		return pathWithNamespace
+	}
+	def, err := instanceWebURL(cfg.GitlabBaseURL)
+	if err != nil || strings.EqualFold(u.Host, def.Host) {
+		return pathWithNamespace
+	}
+	return strings.ToLower(u.Host) + "/" + pathWithNamespace
+}
+
+var envHostReplacer = regexp.MustCompile(`[^A-Z0-9]+`)
+
+// instanceTokenEnv is the environment variable holding the token of an
+// additional instance, e.g. GITLAB_TOKEN_GITLAB_EXAMPLE_COM.
+func instanceTokenEnv(host string) string {
+	return "GITLAB_TOKEN_" + envHostReplacer.ReplaceAllString(strings.ToUpper(host), "_")
+}
+
+// NewGitlabClients creates the client for the default GitLab instance and,
+// when `gitlab-instances` is set, one for every additional instance behind a
+// MultiClient routing host qualified project paths.
+func NewGitlabClients(cfg config.Config) vcs.GitClient {
+	def := NewGitlabClient(cfg)
+	if len(cfg.GitlabInstances) == 0 {
+		if def == nil {
+			return nil
+		}
+		return def
+	}
+
+	m := &MultiClient{
+		defaultClient: def,
+		clients:       map[string]*GitlabClient{},
+	}
+	tokenUser := os.Getenv("GITLAB_TOKEN_USER")
+	for _, instance := range cfg.GitlabInstances {
+		web, err := instanceWebURL(instance)
+		if err != nil {
+			log.Fatal().Err(err).Msg("invalid gitlab instance")
+		}
+		host := strings.ToLower(web.Host)
+		token := os.Getenv(instanceTokenEnv(host))
+		if token == "" {
+			log.Fatal().Str("host", host).Msgf("%s is not set, cannot create Gitlab API client", instanceTokenEnv(host))
+		}
+		c, err := newGitlabClient(cfg, instance, token, tokenUser)
+		if err != nil {
+			log.Fatal().Err(err).Str("host", host).Msg("could not create Gitlab API client")
+		}
+		log.Info().Str("host", host).Msg("added GitLab instance")
+		m.clients[host] = c
+	}
+	return m
+}
+
+// ensure type complies with interface
+var _ vcs.GitClient = (*MultiClient)(nil)
+var _ vcs.Reactions = (*MultiClient)(nil)
+var _ vcs.Maintainers = (*MultiClient)(nil)
+
+// MultiClient serves several GitLab instances. Projects qualified with the
+// host of an additional instance go to its client, everything else to the
+// default instance.
+type MultiClient struct {
+	defaultClient *GitlabClient
+	clients       map[string]*GitlabClient
+}
+
+// clientFor returns the client for the project and the project path on that
+// instance.
+func (m *MultiClient) clientFor(project string) (*GitlabClient, string, error) {
+	if host, path, ok := strings.Cut(project, "/"); ok {
+		if c, ok := m.clients[strings.ToLower(host)]; ok {
+			return c, path, nil
+		}
+	}
+	if m.defaultClient == nil {
+		return nil, "", utils.CreatePermanentError(fmt.Errorf("no GitLab client configured for project %s", project))
+	}
+	return m.defaultClient, project, nil
+}
+
+func (m *MultiClient) GetMergeRequestApprovals(ctx context.Context, id int, project string) (vcs.MRApproved, error) {
+	c, path, err := m.clientFor(project)
+	if err != nil {
+		return nil, err
+	}
+	return c.GetMergeRequestApprovals(ctx, id, path)
+}
+
+func (m *MultiClient) CreateMergeRequestComment(ctx context.Context, id int, fullPath string, comment string) error {
+	c, path, err := m.clientFor(fullPath)
+	if err != nil {
+		return err
+	}
+	return c.CreateMergeRequestComment(ctx, id, path, comment)
+}
+
+func (m *MultiClient) CreateMergeRequestDiscussion(ctx context.Context, mrID int, fullPath string, comment string) (vcs.MRDiscussionNotes, error) {
+	c, path, err := m.clientFor(fullPath)
+	if err != nil {
+		return nil, err
+	}
+	return c.CreateMergeRequestDiscussion(ctx, mrID, path, comment)
+}
+
+func (m *MultiClient) GetMergeRequest(ctx context.Context, mrIID int, project string) (vcs.DetailedMR, error) {
+	c, path, err := m.clientFor(project)
+	if err != nil {
+		return nil, err
+	}
+	return c.GetMergeRequest(ctx, mrIID, path)
+}
+
+func (m *MultiClient) GetRepoFile(ctx context.Context, project string, file string, ref string) ([]byte, error) {
+	c, path, err := m.clientFor(project)
+	if err != nil {
+		return nil, err
+	}
+	return c.GetRepoFile(ctx, path, file, ref)
+}
+
+func (m *MultiClient) GetMergeRequestModifiedFiles(ctx context.Context, mrIID int, projectID string) ([]string, error) {
+	c, path, err := m.clientFor(projectID)
+	if err != nil {
+		return nil, err
+	}
+	return c.GetMergeRequestModifiedFiles(ctx, mrIID, path)
+}
+
+func (m *MultiClient) CloneMergeRequest(ctx context.Context, project string, mr vcs.MR, dest string) (vcs.GitRepo, error) {
+	c, path, err := m.clientFor(project)
+	if err != nil {
+		return nil, err
+	}
+	return c.CloneMergeRequest(ctx, path, mr, dest)
+}
+
+func (m *MultiClient) UpdateMergeRequestDiscussionNote(ctx context.Context, mrIID, noteID int, project, discussionID, comment string) (vcs.MRNote, error) {
+	c, path, err := m.clientFor(project)
+	if err != nil {
+		return nil, err
+	}
+	return c.UpdateMergeRequestDiscussionNote(ctx, mrIID, noteID, path, discussionID, comment)
+}
+
+func (m *MultiClient) ResolveMergeRequestDiscussion(ctx context.Context, project string, mrIID int, discussionID string) error {
+	c, path, err := m.clientFor(project)
+	if err != nil {
+		return err
+	}
+	return c.ResolveMergeRequestDiscussion(ctx, path, mrIID, discussionID)
+}
+
+func (m *MultiClient) AddMergeRequestDiscussionReply(ctx context.Context, mrIID int, project, discussionID, comment string) (vcs.MRNote, error) {
+	c, path, err := m.clientFor(project)
+	if err != nil {
+		return nil, err
+	}
+	return c.AddMergeRequestDiscussionReply(ctx, mrIID, path, discussionID, comment)
+}
+
+func (m *MultiClient) SetCommitStatus(ctx context.Context, projectWithNS string, commitSHA string, status vcs.CommitStatusOptions) (vcs.CommitStatus, error) {
+	c, path, err := m.clientFor(projectWithNS)
+	if err != nil {
+		return nil, err
+	}
+	return c.SetCommitStatus(ctx, path, commitSHA, status)
+}
+
+func (m *MultiClient) GetPipelinesForCommit(ctx context.Context, projectWithNS string, commitSHA string) ([]vcs.ProjectPipeline, error) {
+	c, path, err := m.clientFor(projectWithNS)
+	if err != nil {
+		return nil, err
+	}
+	return c.GetPipelinesForCommit(ctx, path, commitSHA)
+}
+
+func (m *MultiClient) GetOldRunUrls(ctx context.Context, mrIID int, project string, rootCommentID int, workspace string, action string) (string, error) {
+	c, path, err := m.clientFor(project)
+	if err != nil {
+		return "", err
+	}
+	return c.GetOldRunUrls(ctx, mrIID, path, rootCommentID, workspace, action)
+}
+
+func (m *MultiClient) MergeMR(ctx context.Context, mrIID int, project string) error {
+	c, path, err := m.clientFor(project)
+	if err != nil {
+		return err
+	}
+	return c.MergeMR(ctx, mrIID, path)
+}
+
+func (m *MultiClient) AddCommentReaction(ctx context.Context, project string, mrIID int, noteID int64, reaction vcs.Reaction) error {
+	c, path, err := m.clientFor(project)
+	if err != nil {
+		return err
+	}
+	return c.AddCommentReaction(ctx, path, mrIID, noteID, reaction)
+}
+
+func (m *MultiClient) IsMaintainer(ctx context.Context, project string, username string) (bool, error) {
+	c, path, err := m.clientFor(project)
+	if err != nil {
+		return false, err
+	}
+	return c.IsMaintainer(ctx, path, username)
+}
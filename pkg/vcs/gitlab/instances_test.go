@@ -0,0 +1,131 @@
+package gitlab
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/zapier/tfbuddy/internal/config"
+)
+
+func TestInstanceWebURL(t *testing.T) {
+	tests := []struct {
+		baseURL string
+		want    string
+		wantErr bool
+	}{
+		{baseURL: "", want: "https://gitlab.com/"},
+		{baseURL: "https://gitlab.example.com", want: "https://gitlab.example.com/"},
+		{baseURL: "https://gitlab.example.com/api/v4/", want: "https://gitlab.example.com/"},
+		{baseURL: "https://example.com/gitlab/api/v4", want: "https://example.com/gitlab/"},
+		{baseURL: "gitlab.example.com", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.baseURL, func(t *testing.T) {
+			got, err := instanceWebURL(tt.baseURL)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("instanceWebURL() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && got.String() != tt.want {
+				t.Errorf("instanceWebURL() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestQualifiedProjectPath(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     config.Config
+		webURL  string
+		want    string
+		project string
+	}{
+		{
+			name:    "gitlab.com project",
+			webURL:  "https://gitlab.com/group/project",
+			project: "group/project",
+			want:    "group/project",
+		},
+		{
+			name:    "default self-managed project",
+			cfg:     config.Config{GitlabBaseURL: "https://gitlab.example.com"},
+			webURL:  "https://gitlab.example.com/group/project",
+			project: "group/project",
+			want:    "group/project",
+		},
+		{
+			name:    "additional instance project",
+			cfg:     config.Config{GitlabBaseURL: "https://gitlab.example.com"},
+			webURL:  "https://Gitlab.Other.com/group/project",
+			project: "group/project",
+			want:    "gitlab.other.com/group/project",
+		},
+		{
+			name:    "missing web url",
+			project: "group/project",
+			want:    "group/project",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := QualifiedProjectPath(tt.cfg, tt.webURL, tt.project); got != tt.want {
+				t.Errorf("QualifiedProjectPath() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestInstanceTokenEnv(t *testing.T) {
+	if got := instanceTokenEnv("gitlab.example.com:8443"); got != "GITLAB_TOKEN_GITLAB_EXAMPLE_COM_8443" {
+		t.Errorf("instanceTokenEnv() = %s", got)
+	}
+}
+
+func TestMultiClient_RoutesByHost(t *testing.T) {
+	var mu sync.Mutex
+	var requests []string
+	newServer := func(name string) *httptest.Server {
+		return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			mu.Lock()
+			requests = append(requests, name+" "+r.Header.Get("PRIVATE-TOKEN")+" "+r.URL.EscapedPath())
+			mu.Unlock()
+			w.Write([]byte("content"))
+		}))
+	}
+	def := newServer("default")
+	defer def.Close()
+	other := newServer("other")
+	defer other.Close()
+
+	defClient, err := newGitlabClient(config.Config{}, def.URL, "default-token", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherClient, err := newGitlabClient(config.Config{}, other.URL, "other-token", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := &MultiClient{
+		defaultClient: defClient,
+		clients:       map[string]*GitlabClient{"gitlab.other.com": otherClient},
+	}
+
+	for _, project := range []string{testProject, "gitlab.other.com/" + testProject, "group.with.dots/project"} {
+		if _, err := m.GetRepoFile(context.Background(), project, ".tfbuddy.yaml", "main"); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	want := []string{
+		"default default-token /api/v4/projects/test-group%2Ftest-project/repository/files/%2Etfbuddy%2Eyaml/raw",
+		"other other-token /api/v4/projects/test-group%2Ftest-project/repository/files/%2Etfbuddy%2Eyaml/raw",
+		"default default-token /api/v4/projects/group%2Ewith%2Edots%2Fproject/repository/files/%2Etfbuddy%2Eyaml/raw",
+	}
+	if strings.Join(requests, "\n") != strings.Join(want, "\n") {
+		t.Fatalf("expected requests\n%s\ngot\n%s", strings.Join(want, "\n"), strings.Join(requests, "\n"))
+	}
+}
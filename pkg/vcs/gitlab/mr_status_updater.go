@@ -9,7 +9,9 @@ import (
 	"github.com/cenkalti/backoff/v4"
 	"github.com/hashicorp/go-tfe"
 	"github.com/rs/zerolog/log"
+	"github.com/zapier/tfbuddy/internal/config"
 	"github.com/zapier/tfbuddy/pkg/runstream"
+	"github.com/zapier/tfbuddy/pkg/tfc_api"
 	gogitlab "gitlab.com/gitlab-org/api/client-go"
 	"go.opentelemetry.io/otel"
 )
@@ -114,7 +116,7 @@ func (p *RunStatusUpdater) updateStatus(ctx context.Context, state gogitlab.Buil
 	status := &gogitlab.SetCommitStatusOptions{
 		Name:        statusName(rmd.GetWorkspace(), action),
 		Context:     statusName(rmd.GetWorkspace(), action),
-		TargetURL:   runUrlForTFRunMetadata(rmd),
+		TargetURL:   runUrlForTFRunMetadata(p.cfg, rmd),
 		Description: descriptionForState(state),
 		State:       state,
 	}
@@ -172,13 +174,8 @@ func descriptionForState(state gogitlab.BuildStateValue) *string {
 	return ptr("unknown")
 }
 
-func runUrlForTFRunMetadata(rmd runstream.RunMetadata) *string {
-	return ptr(fmt.Sprintf(
-		"https://app.terraform.io/app/%s/workspaces/%s/runs/%s",
-		rmd.GetOrganization(),
-		rmd.GetWorkspace(),
-		rmd.GetRunID(),
-	))
+func runUrlForTFRunMetadata(cfg config.Config, rmd runstream.RunMetadata) *string {
+	return ptr(tfc_api.RunURL(cfg, rmd.GetOrganization(), rmd.GetWorkspace(), rmd.GetRunID()))
 }
 
 func (p *RunStatusUpdater) getLatestPipelineID(ctx context.Context, rmd runstream.RunMetadata) *int {
@@ -18,7 +18,7 @@ type RunStatusUpdater struct {
 	eventQCloser func()
 }
 
-func NewRunStatusProcessor(cfg config.Config, client *GitlabClient, rs runstream.StreamClient, tfc tfc_api.ApiClient) *RunStatusUpdater {
+func NewRunStatusProcessor(cfg config.Config, client vcs.GitClient, rs runstream.StreamClient, tfc tfc_api.ApiClient) *RunStatusUpdater {
 	rsp := &RunStatusUpdater{
 		cfg:    cfg,
 		client: client,
@@ -20,6 +20,18 @@ type GitClient interface {
 	GetOldRunUrls(ctx context.Context, mrIID int, project string, rootCommentID int, workspace string, action string) (string, error)
 	MergeMR(ctx context.Context, mrIID int, project string) error
 }
+
+// Reactions is implemented by clients that can react to merge request
+// comments with an emoji.
+type Reactions interface {
+	AddCommentReaction(ctx context.Context, project string, mrIID int, commentID int64, reaction Reaction) error
+}
+
+// Maintainers is implemented by clients that can tell whether a user may
+// maintain a project. Runs for merge requests from forks need a maintainer.
+type Maintainers interface {
+	IsMaintainer(ctx context.Context, project string, username string) (bool, error)
+}
 type GitRepo interface {
 	FetchUpstreamBranch(string) error
 	GetMergeBase(oldest, newest string) (string, error)
@@ -52,6 +64,19 @@ type MR interface {
 	GetAuthor() MRAuthor
 	GetInternalID() int
 }
+
+// DraftMR is implemented by merge requests that can be marked as a draft
+// (or work in progress) and are not ready for review yet.
+type DraftMR interface {
+	IsDraft() bool
+}
+
+// ForkMR is implemented by merge requests that know whether their source
+// branch lives in a fork of the target project.
+type ForkMR interface {
+	IsFromFork() bool
+	GetHeadSHA() string
+}
 type MRBranches interface {
 	GetSourceBranch() string
 	GetTargetBranch() string
@@ -0,0 +1,34 @@
+package vcs
+
+import (
+	"context"
+
+	"github.com/rs/zerolog/log"
+)
+
+// Reaction is an emoji TFBuddy adds to the comment that issued a command.
+// Values are GitLab award emoji names, other providers translate them.
+type Reaction string
+
+const (
+	// ReactionPickedUp acknowledges a command taken from the hooks stream.
+	ReactionPickedUp Reaction = "eyes"
+	// ReactionDispatched marks a command whose runs were started.
+	ReactionDispatched Reaction = "rocket"
+	// ReactionRefused marks a command that was not run, e.g. because the
+	// merge request is not approved.
+	ReactionRefused Reaction = "thumbsdown"
+)
+
+// React adds reaction to the comment commentID when client supports
+// reactions. Reactions only acknowledge a command, so failures are logged
+// and otherwise ignored.
+func React(ctx context.Context, client GitClient, project string, mrIID int, commentID int64, reaction Reaction) {
+	r, ok := client.(Reactions)
+	if !ok || commentID == 0 {
+		return
+	}
+	if err := r.AddCommentReaction(ctx, project, mrIID, commentID, reaction); err != nil {
+		log.Warn().Err(err).Str("project", project).Int("mrIID", mrIID).Int64("commentID", commentID).Str("reaction", string(reaction)).Msg("could not react to comment")
+	}
+}